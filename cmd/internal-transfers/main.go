@@ -15,6 +15,7 @@ import (
 	"github.com/chandra-shekhar/internal-transfers/internal/router"
 	"github.com/chandra-shekhar/internal-transfers/internal/server"
 	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/chandra-shekhar/internal-transfers/internal/worker"
 )
 
 const DefaultContextTimeout = 30
@@ -28,6 +29,16 @@ func main() {
 	// Initialize logger
 	log := logger.NewLogger(cfg.Primary.Env)
 
+	// Run a one-off command instead of the server when one is given
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "reconcile":
+			os.Exit(runReconcile(cfg, &log, os.Args[2:]))
+		default:
+			log.Fatal().Str("command", os.Args[1]).Msg("unknown command")
+		}
+	}
+
 	// Note: Migrations are handled separately
 	// In production, run migrations before starting the application
 
@@ -50,6 +61,10 @@ func main() {
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 
+	// Start background jobs
+	workers := worker.New(srv, services)
+	workers.Start(ctx)
+
 	// Start server
 	go func() {
 		if err = srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -61,6 +76,13 @@ func main() {
 	<-ctx.Done()
 	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout*time.Second)
 
+	// Background jobs stop with the signal context; let them finish before closing the pool
+	workers.Wait()
+
+	if err = services.Outbox.Close(); err != nil {
+		log.Error().Err(err).Msg("failed to close outbox publisher")
+	}
+
 	if err = srv.Shutdown(ctx); err != nil {
 		log.Fatal().Err(err).Msg("server forced to shutdown")
 	}
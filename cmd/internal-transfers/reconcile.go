@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"os/signal"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/config"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/rs/zerolog"
+)
+
+// Exit codes of the reconcile command
+const (
+	exitOK    = 0
+	exitError = 1
+	exitDrift = 2
+)
+
+// runReconcile checks the balance invariants once and writes a JSON report.
+// It exits with exitDrift when an issue was found and not fixed.
+func runReconcile(cfg *config.Config, log *zerolog.Logger, args []string) int {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	fix := flags.Bool("fix", false, "write reconciliation entries for balances that drifted from the ledger")
+	output := flags.String("output", "", "write the JSON report to this file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	// Keep stdout free for the report
+	cmdLog := log.Output(os.Stderr)
+
+	srv, err := server.New(cfg, &cmdLog)
+	if err != nil {
+		cmdLog.Error().Err(err).Msg("failed to initialize server")
+		return exitError
+	}
+	defer srv.DB.Close()
+
+	repos := repository.NewRepositories(srv)
+	services := service.NewServices(srv, repos)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	report, err := services.Reconciliation.Run(ctx, *fix)
+	if err != nil {
+		cmdLog.Error().Err(err).Msg("reconciliation failed")
+		return exitError
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			cmdLog.Error().Err(err).Str("output", *output).Msg("failed to create report file")
+			return exitError
+		}
+		defer out.Close()
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		cmdLog.Error().Err(err).Msg("failed to write reconciliation report")
+		return exitError
+	}
+
+	if !report.Clean() {
+		return exitDrift
+	}
+
+	return exitOK
+}
@@ -4,17 +4,32 @@ import (
 	"os"
 	"strings"
 
+	"github.com/chandra-shekhar/internal-transfers/internal/currency"
+	"github.com/chandra-shekhar/internal-transfers/internal/fx"
 	"github.com/go-playground/validator/v10"
 	_ "github.com/joho/godotenv/autoload"
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/v2"
 	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
 )
 
 type Config struct {
-	Primary  Primary        `koanf:"primary" validate:"required"`
-	Server   ServerConfig   `koanf:"server" validate:"required"`
-	Database DatabaseConfig `koanf:"database" validate:"required"`
+	Primary     Primary           `koanf:"primary" validate:"required"`
+	Server      ServerConfig      `koanf:"server" validate:"required"`
+	Database    DatabaseConfig    `koanf:"database" validate:"required"`
+	Idempotency IdempotencyConfig `koanf:"idempotency"`
+	Reconcile   ReconcileConfig   `koanf:"reconcile"`
+	Scheduler   SchedulerConfig   `koanf:"scheduler"`
+	Hold        HoldConfig        `koanf:"hold"`
+	Currency    CurrencyConfig    `koanf:"currency"`
+	FX          FXConfig          `koanf:"fx"`
+	Interest    InterestConfig    `koanf:"interest"`
+	Async       AsyncConfig       `koanf:"async"`
+	Outbox      OutboxConfig      `koanf:"outbox"`
+	Webhook     WebhookConfig     `koanf:"webhook"`
+	Stream      StreamConfig      `koanf:"stream"`
+	Snapshot    SnapshotConfig    `koanf:"snapshot"`
 }
 
 type Primary struct {
@@ -42,6 +57,131 @@ type DatabaseConfig struct {
 	ConnMaxIdleTime int    `koanf:"conn_max_idle_time" validate:"required"`
 }
 
+type IdempotencyConfig struct {
+	// RetentionHours is how long a key is remembered before it can be reused
+	RetentionHours int `koanf:"retention_hours" validate:"min=0"`
+	// PurgeInterval is the interval in seconds between expired key cleanups
+	PurgeInterval int `koanf:"purge_interval" validate:"min=0"`
+}
+
+type ReconcileConfig struct {
+	// Interval is the interval in seconds between background reconciliation runs, 0 disables them
+	Interval int `koanf:"interval" validate:"min=0"`
+}
+
+type SchedulerConfig struct {
+	// Interval is the interval in seconds between scans for due scheduled transfers, 0 disables them
+	Interval int `koanf:"interval" validate:"min=0"`
+	// BatchSize is the maximum number of scheduled transfers and standing orders executed per scan
+	BatchSize int `koanf:"batch_size" validate:"min=0"`
+	// RetryDelay is the delay in seconds before a standing order that hit insufficient funds is retried
+	RetryDelay int `koanf:"retry_delay" validate:"min=0"`
+}
+
+type HoldConfig struct {
+	// DefaultTTL is how long in seconds a hold lasts when the request sets no expiry
+	DefaultTTL int `koanf:"default_ttl" validate:"min=0"`
+}
+
+type CurrencyConfig struct {
+	// Default is the ISO 4217 code of accounts created without a currency
+	Default string `koanf:"default"`
+}
+
+type FXConfig struct {
+	// Provider is where rates come from: the fx_rates table or a local rates file
+	Provider string `koanf:"provider" validate:"omitempty,oneof=table file"`
+	// RatesFile is the .json or .csv file read by the file provider
+	RatesFile string `koanf:"rates_file"`
+	// Spread is the fraction taken off every rate, such as 0.0025
+	Spread string `koanf:"spread"`
+	// QuoteTTL is how long in seconds a quote can be executed
+	QuoteTTL int `koanf:"quote_ttl" validate:"min=0"`
+	// PnLAccounts maps each currency FX can convert into to the account receiving rounding
+	// remainders, as CURRENCY:ACCOUNT_ID pairs separated by commas
+	PnLAccounts string `koanf:"pnl_accounts"`
+}
+
+type InterestConfig struct {
+	// Interval is the interval in seconds between interest accrual and capitalization runs, 0 disables them
+	Interval int `koanf:"interval" validate:"min=0"`
+}
+
+type AsyncConfig struct {
+	// Workers is the number of workers settling transfers made with ?mode=async
+	Workers int `koanf:"workers" validate:"min=0"`
+	// Interval is the interval in seconds at which an idle worker looks for pending transfers, 0 disables the workers
+	Interval int `koanf:"interval" validate:"min=0"`
+	// BatchSize is the maximum number of pending transfers a worker settles per run
+	BatchSize int `koanf:"batch_size" validate:"min=0"`
+}
+
+type OutboxConfig struct {
+	// Publisher is where relayed events go: stdout or a file of JSON lines
+	Publisher string `koanf:"publisher" validate:"omitempty,oneof=stdout file"`
+	// File is the file the file publisher appends to
+	File string `koanf:"file"`
+	// Interval is the interval in seconds between outbox relay runs, 0 disables the relay
+	Interval int `koanf:"interval" validate:"min=0"`
+	// BatchSize is the maximum number of events published per relay run
+	BatchSize int `koanf:"batch_size" validate:"min=0"`
+}
+
+type WebhookConfig struct {
+	// Interval is the interval in seconds between webhook delivery runs, 0 disables delivery
+	Interval int `koanf:"interval" validate:"min=0"`
+	// BatchSize is the maximum number of deliveries sent concurrently per run
+	BatchSize int `koanf:"batch_size" validate:"min=0"`
+	// Timeout is how long in seconds a receiver has to respond
+	Timeout int `koanf:"timeout" validate:"min=0"`
+	// MaxAttempts is the number of attempts before a delivery is moved to the dead letters
+	MaxAttempts int `koanf:"max_attempts" validate:"min=0"`
+	// BackoffBase is the wait in seconds after the first failed attempt, doubled after each further one
+	BackoffBase int `koanf:"backoff_base" validate:"min=0"`
+	// BackoffMax caps the wait in seconds between two attempts
+	BackoffMax int `koanf:"backoff_max" validate:"min=0"`
+	// RotationGrace is how long in seconds a rotated secret stays valid by default
+	RotationGrace int `koanf:"rotation_grace" validate:"min=0"`
+}
+
+type StreamConfig struct {
+	// Heartbeat is the interval in seconds between keep-alives on an idle account stream. Each
+	// heartbeat also checks the ledger, so postings are never later than this.
+	Heartbeat int `koanf:"heartbeat" validate:"min=0"`
+}
+
+type SnapshotConfig struct {
+	// Interval is the interval in seconds between balance snapshot runs, 0 disables them
+	Interval int `koanf:"interval" validate:"min=0"`
+	// Delay is how long in seconds after midnight UTC a day is snapshotted, leaving time for
+	// transfers that started before midnight to commit
+	Delay int `koanf:"delay" validate:"min=0"`
+}
+
+const (
+	DefaultIdempotencyRetentionHours = 24
+	DefaultIdempotencyPurgeInterval  = 3600
+	DefaultSchedulerBatchSize        = 100
+	DefaultSchedulerRetryDelay       = 3600
+	DefaultHoldTTL                   = 7 * 24 * 3600
+	DefaultCurrency                  = "USD"
+	DefaultFXProvider                = "table"
+	DefaultFXSpread                  = "0"
+	DefaultFXQuoteTTL                = 30
+	DefaultAsyncWorkers              = 4
+	DefaultAsyncBatchSize            = 100
+	DefaultOutboxPublisher           = "stdout"
+	DefaultOutboxBatchSize           = 100
+	DefaultWebhookBatchSize          = 50
+	DefaultWebhookTimeout            = 10
+	DefaultWebhookMaxAttempts        = 8
+	DefaultWebhookBackoffBase        = 30
+	DefaultWebhookBackoffMax         = 6 * 3600
+	DefaultWebhookRotationGrace      = 24 * 3600
+	DefaultStreamHeartbeat           = 15
+	DefaultSnapshotDelay             = 600
+)
+
 func LoadConfig() (*Config, error) {
 	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
 
@@ -78,6 +218,68 @@ func LoadConfig() (*Config, error) {
 		logger.Fatal().Err(err).Msg("could not unmarshal database config")
 	}
 
+	err = k.Unmarshal("idempotency", &mainConfig.Idempotency)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not unmarshal idempotency config")
+	}
+
+	err = k.Unmarshal("reconcile", &mainConfig.Reconcile)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not unmarshal reconcile config")
+	}
+
+	err = k.Unmarshal("scheduler", &mainConfig.Scheduler)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not unmarshal scheduler config")
+	}
+
+	err = k.Unmarshal("hold", &mainConfig.Hold)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not unmarshal hold config")
+	}
+
+	err = k.Unmarshal("currency", &mainConfig.Currency)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not unmarshal currency config")
+	}
+
+	err = k.Unmarshal("fx", &mainConfig.FX)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not unmarshal fx config")
+	}
+
+	err = k.Unmarshal("interest", &mainConfig.Interest)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not unmarshal interest config")
+	}
+
+	err = k.Unmarshal("async", &mainConfig.Async)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not unmarshal async config")
+	}
+
+	err = k.Unmarshal("outbox", &mainConfig.Outbox)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not unmarshal outbox config")
+	}
+
+	err = k.Unmarshal("webhook", &mainConfig.Webhook)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not unmarshal webhook config")
+	}
+
+	err = k.Unmarshal("stream", &mainConfig.Stream)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not unmarshal stream config")
+	}
+
+	err = k.Unmarshal("snapshot", &mainConfig.Snapshot)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("could not unmarshal snapshot config")
+	}
+
+	applyDefaults(mainConfig)
+
 	validate := validator.New()
 
 	err = validate.Struct(mainConfig)
@@ -85,5 +287,93 @@ func LoadConfig() (*Config, error) {
 		logger.Fatal().Err(err).Msg("config validation failed")
 	}
 
+	if _, ok := currency.Lookup(mainConfig.Currency.Default); !ok {
+		logger.Fatal().Str("currency", mainConfig.Currency.Default).Msg("default currency is not supported")
+	}
+
+	spread, err := decimal.NewFromString(mainConfig.FX.Spread)
+	if err != nil || spread.IsNegative() || spread.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		logger.Fatal().Str("spread", mainConfig.FX.Spread).Msg("fx spread must be a fraction between 0 and 1")
+	}
+
+	if _, err := fx.ParseAccounts(mainConfig.FX.PnLAccounts); err != nil {
+		logger.Fatal().Err(err).Msg("invalid fx pnl accounts")
+	}
+
+	if mainConfig.FX.Provider == "file" && mainConfig.FX.RatesFile == "" {
+		logger.Fatal().Msg("fx rates file is required for the file provider")
+	}
+
+	if mainConfig.Outbox.Publisher == "file" && mainConfig.Outbox.File == "" {
+		logger.Fatal().Msg("outbox file is required for the file publisher")
+	}
+
 	return mainConfig, nil
 }
+
+// applyDefaults fills in optional settings that were not provided
+func applyDefaults(cfg *Config) {
+	if cfg.Idempotency.RetentionHours == 0 {
+		cfg.Idempotency.RetentionHours = DefaultIdempotencyRetentionHours
+	}
+	if cfg.Idempotency.PurgeInterval == 0 {
+		cfg.Idempotency.PurgeInterval = DefaultIdempotencyPurgeInterval
+	}
+	if cfg.Scheduler.BatchSize == 0 {
+		cfg.Scheduler.BatchSize = DefaultSchedulerBatchSize
+	}
+	if cfg.Scheduler.RetryDelay == 0 {
+		cfg.Scheduler.RetryDelay = DefaultSchedulerRetryDelay
+	}
+	if cfg.Hold.DefaultTTL == 0 {
+		cfg.Hold.DefaultTTL = DefaultHoldTTL
+	}
+	if cfg.Currency.Default == "" {
+		cfg.Currency.Default = DefaultCurrency
+	}
+	if cfg.FX.Provider == "" {
+		cfg.FX.Provider = DefaultFXProvider
+	}
+	if cfg.FX.Spread == "" {
+		cfg.FX.Spread = DefaultFXSpread
+	}
+	if cfg.FX.QuoteTTL == 0 {
+		cfg.FX.QuoteTTL = DefaultFXQuoteTTL
+	}
+	if cfg.Async.Workers == 0 {
+		cfg.Async.Workers = DefaultAsyncWorkers
+	}
+	if cfg.Async.BatchSize == 0 {
+		cfg.Async.BatchSize = DefaultAsyncBatchSize
+	}
+	if cfg.Outbox.Publisher == "" {
+		cfg.Outbox.Publisher = DefaultOutboxPublisher
+	}
+	if cfg.Outbox.BatchSize == 0 {
+		cfg.Outbox.BatchSize = DefaultOutboxBatchSize
+	}
+	if cfg.Webhook.BatchSize == 0 {
+		cfg.Webhook.BatchSize = DefaultWebhookBatchSize
+	}
+	if cfg.Webhook.Timeout == 0 {
+		cfg.Webhook.Timeout = DefaultWebhookTimeout
+	}
+	if cfg.Webhook.MaxAttempts == 0 {
+		cfg.Webhook.MaxAttempts = DefaultWebhookMaxAttempts
+	}
+	if cfg.Webhook.BackoffBase == 0 {
+		cfg.Webhook.BackoffBase = DefaultWebhookBackoffBase
+	}
+	if cfg.Webhook.BackoffMax == 0 {
+		cfg.Webhook.BackoffMax = DefaultWebhookBackoffMax
+	}
+	if cfg.Webhook.RotationGrace == 0 {
+		cfg.Webhook.RotationGrace = DefaultWebhookRotationGrace
+	}
+	if cfg.Stream.Heartbeat == 0 {
+		cfg.Stream.Heartbeat = DefaultStreamHeartbeat
+	}
+	if cfg.Snapshot.Delay == 0 {
+		cfg.Snapshot.Delay = DefaultSnapshotDelay
+	}
+}
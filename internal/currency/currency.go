@@ -0,0 +1,53 @@
+// Package currency describes the ISO 4217 currencies accounts can hold and the precision
+// amounts in each of them are kept at
+package currency
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// StorageScale and storageIntegerDigits describe the NUMERIC(20, 5) columns amounts are
+// stored in. No currency may use more decimals than the columns keep.
+const (
+	StorageScale         = 5
+	storageIntegerDigits = 15
+)
+
+// Currency is an ISO 4217 currency and the number of decimals its minor unit allows
+type Currency struct {
+	Code      string
+	Precision int32
+}
+
+var currencies = map[string]Currency{}
+
+func init() {
+	for code, precision := range map[string]int32{
+		"AED": 2, "AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0, "CNY": 2,
+		"CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2, "IDR": 2, "ILS": 2,
+		"INR": 2, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2, "MYR": 2,
+		"NOK": 2, "NZD": 2, "OMR": 3, "PHP": 2, "PLN": 2, "SAR": 2, "SEK": 2, "SGD": 2,
+		"THB": 2, "TND": 3, "TRY": 2, "USD": 2, "VND": 0, "ZAR": 2,
+	} {
+		if precision > StorageScale {
+			panic("currency " + code + " needs more decimals than amounts are stored with")
+		}
+		currencies[code] = Currency{Code: code, Precision: precision}
+	}
+}
+
+// Lookup returns the currency with the given upper case code
+func Lookup(code string) (Currency, bool) {
+	c, ok := currencies[code]
+	return c, ok
+}
+
+// Fits reports whether amount has no more decimals than the currency allows
+func (c Currency) Fits(amount decimal.Decimal) bool {
+	return amount.Equal(amount.Truncate(c.Precision))
+}
+
+// Max is the largest amount a balance in this currency can hold
+func (c Currency) Max() decimal.Decimal {
+	return decimal.New(1, storageIntegerDigits).Sub(decimal.New(1, -c.Precision))
+}
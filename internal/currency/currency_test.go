@@ -0,0 +1,46 @@
+package currency_test
+
+import (
+	"testing"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/currency"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	jpy, ok := currency.Lookup("JPY")
+	require.True(t, ok)
+	assert.Equal(t, int32(0), jpy.Precision)
+
+	bhd, ok := currency.Lookup("BHD")
+	require.True(t, ok)
+	assert.Equal(t, int32(3), bhd.Precision)
+
+	_, ok = currency.Lookup("usd")
+	assert.False(t, ok)
+
+	_, ok = currency.Lookup("XXX")
+	assert.False(t, ok)
+}
+
+func TestCurrency_Fits(t *testing.T) {
+	jpy, _ := currency.Lookup("JPY")
+	bhd, _ := currency.Lookup("BHD")
+
+	assert.True(t, jpy.Fits(decimal.RequireFromString("1500")))
+	assert.True(t, jpy.Fits(decimal.RequireFromString("1500.000")))
+	assert.False(t, jpy.Fits(decimal.RequireFromString("1500.5")))
+
+	assert.True(t, bhd.Fits(decimal.RequireFromString("12.345")))
+	assert.False(t, bhd.Fits(decimal.RequireFromString("12.3456")))
+}
+
+func TestCurrency_Max(t *testing.T) {
+	jpy, _ := currency.Lookup("JPY")
+	usd, _ := currency.Lookup("USD")
+
+	assert.Equal(t, "999999999999999", jpy.Max().String())
+	assert.Equal(t, "999999999999999.99", usd.Max().String())
+}
@@ -104,6 +104,47 @@ func (db *postgresDB) Ping(ctx context.Context) error {
 	return db.pool.Ping(ctx)
 }
 
+func (db *postgresDB) Listen(ctx context.Context, channel string) (Listener, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+
+	return &postgresListener{conn: conn}, nil
+}
+
+// postgresListener is a pooled connection listening on a channel
+type postgresListener struct {
+	conn *pgxpool.Conn
+}
+
+func (l *postgresListener) WaitForNotification(ctx context.Context) (*pgconn.Notification, error) {
+	return l.conn.Conn().WaitForNotification(ctx)
+}
+
+// Close stops listening before the connection goes back to the pool. A connection that
+// cannot be cleaned up is closed instead, so no other caller receives its notifications.
+func (l *postgresListener) Close(ctx context.Context) error {
+	defer l.conn.Release()
+
+	// Cancelling a wait closes the connection, which the pool then discards
+	if l.conn.Conn().IsClosed() {
+		return nil
+	}
+
+	if _, err := l.conn.Exec(ctx, "UNLISTEN *"); err != nil {
+		_ = l.conn.Conn().Close(ctx)
+		return fmt.Errorf("failed to unlisten: %w", err)
+	}
+
+	return nil
+}
+
 func (db *postgresDB) Close() error {
 	db.log.Info().Msg("closing database connection pool")
 	db.pool.Close()
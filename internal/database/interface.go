@@ -15,9 +15,17 @@ type DB interface {
 	Begin(ctx context.Context) (pgx.Tx, error)
 	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
 	Ping(ctx context.Context) error
+	// Listen holds a connection out of the pool that receives the notifications sent on channel
+	Listen(ctx context.Context, channel string) (Listener, error)
 	Close() error
 }
 
+// Listener receives Postgres notifications on a dedicated connection until it is closed
+type Listener interface {
+	WaitForNotification(ctx context.Context) (*pgconn.Notification, error)
+	Close(ctx context.Context) error
+}
+
 // TxQuerier represents a transaction or connection that can execute queries
 type TxQuerier interface {
 	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
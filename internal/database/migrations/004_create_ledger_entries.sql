@@ -0,0 +1,59 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL,
+    transaction_id BIGINT,
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('debit', 'credit')),
+    entry_type VARCHAR(50) NOT NULL,
+    amount NUMERIC(20, 5) NOT NULL CHECK (amount > 0),
+    balance_after NUMERIC(20, 5) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (account_id) REFERENCES accounts(id),
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id)
+);
+
+-- Create indexes for rebuilding balances and looking up the postings of a transfer
+CREATE INDEX idx_ledger_entries_account ON ledger_entries(account_id, id);
+CREATE INDEX idx_ledger_entries_transaction ON ledger_entries(transaction_id);
+
+-- Open the ledger with the balances accounts hold today
+INSERT INTO ledger_entries (account_id, direction, entry_type, amount, balance_after)
+SELECT id, 'credit', 'opening', balance, balance
+FROM accounts
+WHERE balance > 0
+ORDER BY id;
+
+-- Reject any balance that does not match the latest posting of the account
+CREATE OR REPLACE FUNCTION check_balance_posted()
+RETURNS TRIGGER AS $$
+DECLARE
+    posted_balance NUMERIC(20, 5);
+BEGIN
+    SELECT balance_after INTO posted_balance
+    FROM ledger_entries
+    WHERE account_id = NEW.id
+    ORDER BY id DESC
+    LIMIT 1;
+
+    IF (SELECT balance FROM accounts WHERE id = NEW.id) <> COALESCE(posted_balance, 0) THEN
+        RAISE EXCEPTION 'balance of account % changed without a ledger posting', NEW.id
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+-- Deferred so the check runs at commit, after the posting has been written
+CREATE CONSTRAINT TRIGGER check_accounts_balance_posted
+    AFTER INSERT OR UPDATE OF balance ON accounts
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_balance_posted();
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP TRIGGER IF EXISTS check_accounts_balance_posted ON accounts;
+DROP FUNCTION IF EXISTS check_balance_posted();
+DROP TABLE IF EXISTS ledger_entries;
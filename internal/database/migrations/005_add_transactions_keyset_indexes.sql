@@ -0,0 +1,17 @@
+-- Write your migrate up statements here
+-- Create composite indexes for keyset pagination of account statements
+CREATE INDEX idx_transactions_source_keyset ON transactions(source_account_id, created_at DESC, id DESC);
+CREATE INDEX idx_transactions_destination_keyset ON transactions(destination_account_id, created_at DESC, id DESC);
+
+-- Superseded by the keyset indexes
+DROP INDEX IF EXISTS idx_transactions_source_account;
+DROP INDEX IF EXISTS idx_transactions_destination_account;
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+CREATE INDEX idx_transactions_source_account ON transactions(source_account_id);
+CREATE INDEX idx_transactions_destination_account ON transactions(destination_account_id);
+DROP INDEX IF EXISTS idx_transactions_destination_keyset;
+DROP INDEX IF EXISTS idx_transactions_source_keyset;
@@ -0,0 +1,22 @@
+-- Write your migrate up statements here
+ALTER TABLE transactions
+    ADD COLUMN kind VARCHAR(50) NOT NULL DEFAULT 'transfer',
+    ADD COLUMN parent_transaction_id BIGINT REFERENCES transactions(id),
+    ADD COLUMN reversed_amount NUMERIC(20, 5) NOT NULL DEFAULT 0,
+    ADD COLUMN reason TEXT,
+    ADD CONSTRAINT transactions_reversed_amount_check CHECK (reversed_amount >= 0 AND reversed_amount <= amount);
+
+-- Create index to find the reversals of a transaction
+CREATE INDEX idx_transactions_parent ON transactions(parent_transaction_id);
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP INDEX IF EXISTS idx_transactions_parent;
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS transactions_reversed_amount_check,
+    DROP COLUMN IF EXISTS reason,
+    DROP COLUMN IF EXISTS reversed_amount,
+    DROP COLUMN IF EXISTS parent_transaction_id,
+    DROP COLUMN IF EXISTS kind;
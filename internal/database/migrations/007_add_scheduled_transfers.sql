@@ -0,0 +1,18 @@
+-- Write your migrate up statements here
+ALTER TABLE transactions
+    ADD COLUMN execute_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN failure_code VARCHAR(100),
+    ADD COLUMN failure_reason TEXT;
+
+-- Create index for the scheduler to find due transfers and for listing scheduled transfers
+CREATE INDEX idx_transactions_execute_at ON transactions(execute_at, id) WHERE execute_at IS NOT NULL;
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP INDEX IF EXISTS idx_transactions_execute_at;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS failure_reason,
+    DROP COLUMN IF EXISTS failure_code,
+    DROP COLUMN IF EXISTS execute_at;
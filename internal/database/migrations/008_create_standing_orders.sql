@@ -0,0 +1,43 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS standing_orders (
+    id BIGSERIAL PRIMARY KEY,
+    source_account_id BIGINT NOT NULL REFERENCES accounts(id),
+    destination_account_id BIGINT NOT NULL REFERENCES accounts(id),
+    amount NUMERIC(20, 5) NOT NULL CHECK (amount > 0),
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('daily', 'weekly', 'monthly', 'cron')),
+    schedule_day INT,
+    cron_expression VARCHAR(255),
+    start_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_at TIMESTAMP WITH TIME ZONE,
+    max_occurrences INT CHECK (max_occurrences > 0),
+    occurrences INT NOT NULL DEFAULT 0,
+    insufficient_funds_policy VARCHAR(20) NOT NULL CHECK (insufficient_funds_policy IN ('skip', 'retry', 'suspend')),
+    max_retries INT NOT NULL DEFAULT 0 CHECK (max_retries >= 0),
+    retry_count INT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    last_failure_code VARCHAR(100),
+    last_failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (source_account_id != destination_account_id)
+);
+
+-- Create index for the scheduler to find due orders
+CREATE INDEX idx_standing_orders_due ON standing_orders(next_run_at) WHERE status = 'active';
+
+-- Create index to list the orders of an account
+CREATE INDEX idx_standing_orders_source_account ON standing_orders(source_account_id);
+
+-- Link every occurrence to the order that produced it
+ALTER TABLE transactions ADD COLUMN standing_order_id BIGINT REFERENCES standing_orders(id);
+CREATE INDEX idx_transactions_standing_order ON transactions(standing_order_id) WHERE standing_order_id IS NOT NULL;
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP INDEX IF EXISTS idx_transactions_standing_order;
+ALTER TABLE transactions DROP COLUMN IF EXISTS standing_order_id;
+DROP TABLE IF EXISTS standing_orders;
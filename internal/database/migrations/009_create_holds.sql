@@ -0,0 +1,31 @@
+-- Write your migrate up statements here
+ALTER TABLE accounts
+    ADD COLUMN held_amount NUMERIC(20, 5) NOT NULL DEFAULT 0 CHECK (held_amount >= 0);
+
+CREATE TABLE IF NOT EXISTS holds (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    amount NUMERIC(20, 5) NOT NULL CHECK (amount > 0),
+    captured_amount NUMERIC(20, 5) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    reference VARCHAR(255),
+    capture_transaction_id BIGINT REFERENCES transactions(id),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    released_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (captured_amount >= 0 AND captured_amount <= amount)
+);
+
+-- Create index for the expiry job to find lapsed holds
+CREATE INDEX idx_holds_expires_at ON holds(expires_at) WHERE status = 'active';
+
+-- Create index to look up the holds of an account
+CREATE INDEX idx_holds_account ON holds(account_id, created_at DESC, id DESC);
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP TABLE IF EXISTS holds;
+ALTER TABLE accounts DROP COLUMN IF EXISTS held_amount;
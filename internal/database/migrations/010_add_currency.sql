@@ -0,0 +1,26 @@
+-- Write your migrate up statements here
+-- Accounts created before currencies existed are taken to be USD
+ALTER TABLE accounts ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE accounts ALTER COLUMN currency DROP DEFAULT;
+
+-- Transactions, standing orders and holds are denominated in the currency of the account they draw on
+ALTER TABLE transactions ADD COLUMN currency CHAR(3);
+UPDATE transactions t SET currency = a.currency FROM accounts a WHERE a.id = t.source_account_id;
+ALTER TABLE transactions ALTER COLUMN currency SET NOT NULL;
+
+ALTER TABLE standing_orders ADD COLUMN currency CHAR(3);
+UPDATE standing_orders o SET currency = a.currency FROM accounts a WHERE a.id = o.source_account_id;
+ALTER TABLE standing_orders ALTER COLUMN currency SET NOT NULL;
+
+ALTER TABLE holds ADD COLUMN currency CHAR(3);
+UPDATE holds h SET currency = a.currency FROM accounts a WHERE a.id = h.account_id;
+ALTER TABLE holds ALTER COLUMN currency SET NOT NULL;
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+ALTER TABLE holds DROP COLUMN IF EXISTS currency;
+ALTER TABLE standing_orders DROP COLUMN IF EXISTS currency;
+ALTER TABLE transactions DROP COLUMN IF EXISTS currency;
+ALTER TABLE accounts DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,57 @@
+-- Write your migrate up statements here
+-- Published exchange rates, one row per pair and effective time
+CREATE TABLE IF NOT EXISTS fx_rates (
+    id BIGSERIAL PRIMARY KEY,
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (base_currency != quote_currency)
+);
+
+CREATE INDEX idx_fx_rates_pair ON fx_rates(base_currency, quote_currency, effective_at DESC);
+
+-- Quotes a client fetched and may execute once before they expire
+CREATE TABLE IF NOT EXISTS fx_quotes (
+    id BIGSERIAL PRIMARY KEY,
+    source_currency CHAR(3) NOT NULL,
+    destination_currency CHAR(3) NOT NULL,
+    amount NUMERIC(20, 5) NOT NULL CHECK (amount > 0),
+    converted_amount NUMERIC(20, 5) NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL,
+    spread NUMERIC(10, 6) NOT NULL,
+    rate_timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    transaction_id BIGINT REFERENCES transactions(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- The conversion of a cross-currency transfer is locked into its row
+ALTER TABLE transactions
+    ADD COLUMN fx_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN fx_quote_id BIGINT REFERENCES fx_quotes(id),
+    ADD COLUMN destination_amount NUMERIC(20, 5),
+    ADD COLUMN destination_currency CHAR(3),
+    ADD COLUMN fx_rate NUMERIC(20, 10),
+    ADD COLUMN fx_spread NUMERIC(10, 6),
+    ADD COLUMN fx_rate_timestamp TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN fx_remainder NUMERIC(20, 5),
+    ADD COLUMN fx_pnl_account_id BIGINT REFERENCES accounts(id);
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS fx_pnl_account_id,
+    DROP COLUMN IF EXISTS fx_remainder,
+    DROP COLUMN IF EXISTS fx_rate_timestamp,
+    DROP COLUMN IF EXISTS fx_spread,
+    DROP COLUMN IF EXISTS fx_rate,
+    DROP COLUMN IF EXISTS destination_currency,
+    DROP COLUMN IF EXISTS destination_amount,
+    DROP COLUMN IF EXISTS fx_quote_id,
+    DROP COLUMN IF EXISTS fx_requested;
+DROP TABLE IF EXISTS fx_quotes;
+DROP TABLE IF EXISTS fx_rates;
@@ -0,0 +1,35 @@
+-- Write your migrate up statements here
+ALTER TABLE accounts
+    ADD COLUMN overdraft_limit NUMERIC(20, 5) NOT NULL DEFAULT 0 CHECK (overdraft_limit >= 0),
+    ADD COLUMN max_balance NUMERIC(20, 5) CHECK (max_balance >= 0);
+
+-- A balance may go negative down to the account's overdraft limit
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_balance_check;
+ALTER TABLE accounts
+    ADD CONSTRAINT accounts_balance_within_overdraft CHECK (balance >= -overdraft_limit);
+
+CREATE TABLE IF NOT EXISTS account_limit_changes (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    previous_overdraft_limit NUMERIC(20, 5) NOT NULL,
+    overdraft_limit NUMERIC(20, 5) NOT NULL,
+    previous_max_balance NUMERIC(20, 5),
+    max_balance NUMERIC(20, 5),
+    changed_by VARCHAR(255) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create index to list the changes of an account, newest first
+CREATE INDEX idx_account_limit_changes_account ON account_limit_changes(account_id, id DESC);
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP TABLE IF EXISTS account_limit_changes;
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_balance_within_overdraft;
+ALTER TABLE accounts ADD CONSTRAINT accounts_balance_check CHECK (balance >= 0);
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS max_balance,
+    DROP COLUMN IF EXISTS overdraft_limit;
@@ -0,0 +1,15 @@
+-- Write your migrate up statements here
+ALTER TABLE accounts
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'frozen_debit', 'frozen_all', 'closed')),
+    ADD COLUMN status_reason TEXT,
+    ADD COLUMN closed_at TIMESTAMP WITH TIME ZONE;
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS closed_at,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,37 @@
+-- Write your migrate up statements here
+ALTER TABLE accounts ADD COLUMN account_group VARCHAR(100);
+
+CREATE INDEX idx_accounts_group ON accounts(account_group) WHERE account_group IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS velocity_limits (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT REFERENCES accounts(id),
+    account_group VARCHAR(100),
+    -- Amounts are in this currency, and only transfers in it count against the limit
+    currency CHAR(3) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('outgoing_amount', 'outgoing_count', 'single_amount')),
+    window_seconds INTEGER CHECK (window_seconds > 0),
+    max_value NUMERIC(20, 5) NOT NULL CHECK (max_value > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    -- A limit applies to exactly one account or one group
+    CHECK ((account_id IS NULL) <> (account_group IS NULL)),
+    -- Rolling limits need a window, the single transfer limit has none
+    CHECK ((kind = 'single_amount') = (window_seconds IS NULL))
+);
+
+-- Create indexes to find the limits that apply to a transfer
+CREATE INDEX idx_velocity_limits_account ON velocity_limits(account_id) WHERE account_id IS NOT NULL;
+CREATE INDEX idx_velocity_limits_group ON velocity_limits(account_group) WHERE account_group IS NOT NULL;
+
+-- Create index to sum the recent outgoing transfers of an account
+CREATE INDEX idx_transactions_source_completed ON transactions(source_account_id, completed_at)
+    WHERE kind = 'transfer';
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP INDEX IF EXISTS idx_transactions_source_completed;
+DROP TABLE IF EXISTS velocity_limits;
+DROP INDEX IF EXISTS idx_accounts_group;
+ALTER TABLE accounts DROP COLUMN IF EXISTS account_group;
@@ -0,0 +1,47 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS fee_rules (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('flat', 'percentage', 'tiered', 'waiver')),
+    currency CHAR(3) NOT NULL,
+    flat_amount NUMERIC(20, 5) CHECK (flat_amount >= 0),
+    rate NUMERIC(10, 6) CHECK (rate >= 0 AND rate < 1),
+    min_fee NUMERIC(20, 5) CHECK (min_fee >= 0),
+    max_fee NUMERIC(20, 5) CHECK (max_fee >= 0),
+    tiers JSONB,
+    -- A rule without accounts applies to every transfer in its currency
+    source_account_id BIGINT REFERENCES accounts(id),
+    destination_account_id BIGINT REFERENCES accounts(id),
+    payer VARCHAR(10) NOT NULL DEFAULT 'sender' CHECK (payer IN ('sender', 'receiver')),
+    revenue_account_id BIGINT REFERENCES accounts(id),
+    priority INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    -- A waiver names the account pair it exempts, every other rule needs somewhere to post its fees
+    CHECK ((kind = 'waiver') = (revenue_account_id IS NULL)),
+    CHECK (kind <> 'waiver' OR (source_account_id IS NOT NULL AND destination_account_id IS NOT NULL))
+);
+
+-- Create index to find the active rules of a currency
+CREATE INDEX idx_fee_rules_active_currency ON fee_rules(currency, priority, id) WHERE active;
+
+-- The fee charged for a transfer and the child transaction that posted it
+ALTER TABLE transactions
+    ADD COLUMN fee_rule_id BIGINT REFERENCES fee_rules(id),
+    ADD COLUMN fee_amount NUMERIC(20, 5),
+    ADD COLUMN fee_payer VARCHAR(10) CHECK (fee_payer IN ('sender', 'receiver')),
+    ADD COLUMN fee_waived BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN fee_transaction_id BIGINT REFERENCES transactions(id);
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS fee_transaction_id,
+    DROP COLUMN IF EXISTS fee_waived,
+    DROP COLUMN IF EXISTS fee_payer,
+    DROP COLUMN IF EXISTS fee_amount,
+    DROP COLUMN IF EXISTS fee_rule_id;
+DROP INDEX IF EXISTS idx_fee_rules_active_currency;
+DROP TABLE IF EXISTS fee_rules;
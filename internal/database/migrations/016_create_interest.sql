@@ -0,0 +1,45 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS interest_products (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    annual_rate NUMERIC(10, 6) NOT NULL CHECK (annual_rate >= 0 AND annual_rate < 1),
+    day_count VARCHAR(10) NOT NULL CHECK (day_count IN ('act_365', '30_360')),
+    -- Interest is paid out of this account, in the currency of the product
+    expense_account_id BIGINT NOT NULL REFERENCES accounts(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE accounts ADD COLUMN interest_product_id BIGINT REFERENCES interest_products(id);
+
+CREATE INDEX idx_accounts_interest_product ON accounts(interest_product_id) WHERE interest_product_id IS NOT NULL;
+
+-- One accrual per account and business date makes the accrual job safe to rerun
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    product_id BIGINT NOT NULL REFERENCES interest_products(id),
+    business_date DATE NOT NULL,
+    balance NUMERIC(20, 5) NOT NULL,
+    annual_rate NUMERIC(10, 6) NOT NULL,
+    day_count VARCHAR(10) NOT NULL,
+    amount NUMERIC(25, 10) NOT NULL CHECK (amount >= 0),
+    -- Set once the accrual has been capitalized by an interest transaction
+    transaction_id BIGINT REFERENCES transactions(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (account_id, business_date)
+);
+
+-- Create index to find the accruals that are still to be capitalized
+CREATE INDEX idx_interest_accruals_unposted ON interest_accruals(account_id, business_date)
+    WHERE transaction_id IS NULL;
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP INDEX IF EXISTS idx_interest_accruals_unposted;
+DROP TABLE IF EXISTS interest_accruals;
+DROP INDEX IF EXISTS idx_accounts_interest_product;
+ALTER TABLE accounts DROP COLUMN IF EXISTS interest_product_id;
+DROP TABLE IF EXISTS interest_products;
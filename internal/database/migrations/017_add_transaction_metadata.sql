@@ -0,0 +1,29 @@
+-- Write your migrate up statements here
+-- Caller supplied details that tie a transfer back to upstream systems
+ALTER TABLE transactions
+    ADD COLUMN reference VARCHAR(255),
+    ADD COLUMN description TEXT,
+    ADD COLUMN external_id VARCHAR(255),
+    ADD COLUMN metadata JSONB;
+
+-- An external ID identifies one transfer of its source account
+CREATE UNIQUE INDEX idx_transactions_source_external_id ON transactions(source_account_id, external_id) WHERE external_id IS NOT NULL;
+
+-- Create indexes for searching transactions, newest first
+CREATE INDEX idx_transactions_reference ON transactions(reference, created_at DESC, id DESC) WHERE reference IS NOT NULL;
+CREATE INDEX idx_transactions_external_id ON transactions(external_id) WHERE external_id IS NOT NULL;
+CREATE INDEX idx_transactions_metadata ON transactions USING GIN (metadata jsonb_path_ops);
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP INDEX IF EXISTS idx_transactions_metadata;
+DROP INDEX IF EXISTS idx_transactions_external_id;
+DROP INDEX IF EXISTS idx_transactions_reference;
+DROP INDEX IF EXISTS idx_transactions_source_external_id;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS metadata,
+    DROP COLUMN IF EXISTS external_id,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS reference;
@@ -0,0 +1,24 @@
+-- Write your migrate up statements here
+-- The version of an account, bumped by every update so clients can detect concurrent changes
+ALTER TABLE accounts ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- Create function to bump the version of an updated account
+CREATE OR REPLACE FUNCTION bump_version_column()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+-- Create trigger to bump the version on every update, including balance postings
+CREATE TRIGGER bump_accounts_version BEFORE UPDATE
+    ON accounts FOR EACH ROW EXECUTE FUNCTION bump_version_column();
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP TRIGGER IF EXISTS bump_accounts_version ON accounts;
+DROP FUNCTION IF EXISTS bump_version_column();
+ALTER TABLE accounts DROP COLUMN IF EXISTS version;
@@ -0,0 +1,13 @@
+-- Write your migrate up statements here
+-- Kept so an asynchronous transfer checks the source version the client expected when it settles
+ALTER TABLE transactions ADD COLUMN expected_source_version BIGINT;
+
+-- Create index for workers claiming pending transfers in arrival order
+CREATE INDEX idx_transactions_pending ON transactions(created_at, id) WHERE status = 'pending';
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP INDEX IF EXISTS idx_transactions_pending;
+ALTER TABLE transactions DROP COLUMN IF EXISTS expected_source_version;
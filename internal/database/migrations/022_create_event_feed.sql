@@ -0,0 +1,40 @@
+-- Write your migrate up statements here
+-- Published outbox events in the order they were published. Positions are assigned under a
+-- lock held until commit, so they become visible in increasing order.
+CREATE TABLE IF NOT EXISTS events (
+    position BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL UNIQUE REFERENCES outbox(id),
+    event_type VARCHAR(50) NOT NULL,
+    aggregate_type VARCHAR(20) NOT NULL,
+    aggregate_id BIGINT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    published_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Events published before the feed existed start it, in ID order
+INSERT INTO events (event_id, event_type, aggregate_type, aggregate_id, payload, created_at, published_at)
+SELECT id, event_type, aggregate_type, aggregate_id, payload, created_at, published_at
+FROM outbox
+WHERE published_at IS NOT NULL
+ORDER BY id;
+
+-- Named readers of the feed and the position each has committed
+CREATE TABLE IF NOT EXISTS event_consumer_groups (
+    name VARCHAR(100) PRIMARY KEY,
+    position BIGINT NOT NULL DEFAULT 0 CHECK (position >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create trigger to auto-update updated_at
+CREATE TRIGGER update_event_consumer_groups_updated_at BEFORE UPDATE
+    ON event_consumer_groups FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP TRIGGER IF EXISTS update_event_consumer_groups_updated_at ON event_consumer_groups;
+DROP TABLE IF EXISTS event_consumer_groups;
+DROP TABLE IF EXISTS events;
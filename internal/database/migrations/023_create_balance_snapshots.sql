@@ -0,0 +1,32 @@
+-- Write your migrate up statements here
+-- End-of-day (UTC) balances of the accounts that moved that day. A balance at any earlier time
+-- is the latest snapshot before it plus the postings since.
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    snapshot_date DATE NOT NULL,
+    -- The end of the day, the first instant the snapshot does not cover
+    closing_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    balance NUMERIC(20, 5) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, snapshot_date)
+);
+
+-- One row per day the snapshot job has completed, so days are taken in order and only once
+CREATE TABLE IF NOT EXISTS balance_snapshot_runs (
+    snapshot_date DATE PRIMARY KEY,
+    accounts INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for summing the postings of a day and of an account since its last snapshot
+CREATE INDEX idx_ledger_entries_created ON ledger_entries(created_at);
+CREATE INDEX idx_ledger_entries_account_created ON ledger_entries(account_id, created_at);
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP INDEX IF EXISTS idx_ledger_entries_account_created;
+DROP INDEX IF EXISTS idx_ledger_entries_created;
+DROP TABLE IF EXISTS balance_snapshot_runs;
+DROP TABLE IF EXISTS balance_snapshots;
@@ -0,0 +1,14 @@
+-- Write your migrate up statements here
+-- FX rounding remainders owed to each P&L account that do not add up to a whole minor unit of
+-- its currency yet. They are posted once they do, so P&L balances stay at currency precision.
+CREATE TABLE IF NOT EXISTS fx_remainders (
+    account_id BIGINT PRIMARY KEY REFERENCES accounts(id),
+    pending NUMERIC(20, 5) NOT NULL DEFAULT 0 CHECK (pending >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP TABLE IF EXISTS fx_remainders;
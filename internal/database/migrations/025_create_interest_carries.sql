@@ -0,0 +1,14 @@
+-- Write your migrate up statements here
+-- Capitalized interest that did not add up to a whole minor unit of the account currency. It is
+-- added to the accruals of the next capitalization instead of being rounded away.
+CREATE TABLE IF NOT EXISTS interest_carries (
+    account_id BIGINT PRIMARY KEY REFERENCES accounts(id),
+    pending NUMERIC(25, 10) NOT NULL DEFAULT 0 CHECK (pending >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+---- create above / drop below ----
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
+DROP TABLE IF EXISTS interest_carries;
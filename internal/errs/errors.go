@@ -36,6 +36,153 @@ var (
 		Override: false,
 	}
 
+	ErrTransactionNotFound = &HTTPError{
+		Code:     "TRANSACTION_NOT_FOUND",
+		Message:  "Transaction not found",
+		Status:   http.StatusNotFound,
+		Override: false,
+	}
+
+	ErrTransactionNotReversible = &HTTPError{
+		Code:     "TRANSACTION_NOT_REVERSIBLE",
+		Message:  "Transaction cannot be reversed",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrDuplicateExternalID = &HTTPError{
+		Code:     "DUPLICATE_EXTERNAL_ID",
+		Message:  "The source account already made a transfer with this external ID",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrTransactionNotCancellable = &HTTPError{
+		Code:     "TRANSACTION_NOT_CANCELLABLE",
+		Message:  "Only scheduled transactions can be cancelled",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrReversalExceedsAmount = &HTTPError{
+		Code:     "REVERSAL_EXCEEDS_AMOUNT",
+		Message:  "Reversal amount exceeds the amount left to reverse",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrBatchRejected = &HTTPError{
+		Code:     "BATCH_REJECTED",
+		Message:  "One or more batch legs were rejected, nothing was transferred",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrStandingOrderNotFound = &HTTPError{
+		Code:     "STANDING_ORDER_NOT_FOUND",
+		Message:  "Standing order not found",
+		Status:   http.StatusNotFound,
+		Override: false,
+	}
+
+	ErrInvalidStandingOrder = &HTTPError{
+		Code:     "INVALID_STANDING_ORDER",
+		Message:  "Invalid standing order schedule",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrStandingOrderStateConflict = &HTTPError{
+		Code:     "STANDING_ORDER_STATE_CONFLICT",
+		Message:  "Standing order cannot change to the requested state",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrHoldNotFound = &HTTPError{
+		Code:     "HOLD_NOT_FOUND",
+		Message:  "Hold not found",
+		Status:   http.StatusNotFound,
+		Override: false,
+	}
+
+	ErrHoldNotActive = &HTTPError{
+		Code:     "HOLD_NOT_ACTIVE",
+		Message:  "Hold is no longer active",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrHoldExpired = &HTTPError{
+		Code:     "HOLD_EXPIRED",
+		Message:  "Hold has expired and its funds were released",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrCaptureExceedsHold = &HTTPError{
+		Code:     "CAPTURE_EXCEEDS_HOLD",
+		Message:  "Capture amount exceeds the held amount",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrUnsupportedCurrency = &HTTPError{
+		Code:     "UNSUPPORTED_CURRENCY",
+		Message:  "Currency is not supported",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrCurrencyMismatch = &HTTPError{
+		Code:     "CURRENCY_MISMATCH",
+		Message:  "Source and destination accounts hold different currencies",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrInvalidAmountPrecision = &HTTPError{
+		Code:     "INVALID_AMOUNT_PRECISION",
+		Message:  "Amount has more decimal places than the currency allows",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrFXUnavailable = &HTTPError{
+		Code:     "FX_UNAVAILABLE",
+		Message:  "FX conversion is not available for this currency pair",
+		Status:   http.StatusUnprocessableEntity,
+		Override: false,
+	}
+
+	ErrFXQuoteNotFound = &HTTPError{
+		Code:     "FX_QUOTE_NOT_FOUND",
+		Message:  "FX quote not found",
+		Status:   http.StatusNotFound,
+		Override: false,
+	}
+
+	ErrFXQuoteExpired = &HTTPError{
+		Code:     "FX_QUOTE_EXPIRED",
+		Message:  "FX quote has expired",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrFXQuoteUsed = &HTTPError{
+		Code:     "FX_QUOTE_USED",
+		Message:  "FX quote has already been executed",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrFXQuoteMismatch = &HTTPError{
+		Code:     "FX_QUOTE_MISMATCH",
+		Message:  "FX quote does not match the transfer",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
 	ErrInvalidAmount = &HTTPError{
 		Code:     "INVALID_AMOUNT",
 		Message:  "Invalid amount",
@@ -78,6 +225,146 @@ var (
 		Override: false,
 	}
 
+	ErrSourceAccountFrozen = &HTTPError{
+		Code:     "SOURCE_ACCOUNT_FROZEN",
+		Message:  "Source account is frozen for debits",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrDestinationAccountFrozen = &HTTPError{
+		Code:     "DESTINATION_ACCOUNT_FROZEN",
+		Message:  "Destination account is frozen for credits",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrSourceAccountClosed = &HTTPError{
+		Code:     "SOURCE_ACCOUNT_CLOSED",
+		Message:  "Source account is closed",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrDestinationAccountClosed = &HTTPError{
+		Code:     "DESTINATION_ACCOUNT_CLOSED",
+		Message:  "Destination account is closed",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrAccountClosed = &HTTPError{
+		Code:     "ACCOUNT_CLOSED",
+		Message:  "Account is closed",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrAccountVersionMismatch = &HTTPError{
+		Code:     "ACCOUNT_VERSION_MISMATCH",
+		Message:  "Account has changed since the expected version",
+		Status:   http.StatusPreconditionFailed,
+		Override: false,
+	}
+
+	ErrAccountNotFrozen = &HTTPError{
+		Code:     "ACCOUNT_NOT_FROZEN",
+		Message:  "Account is not frozen",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrAccountNotEmpty = &HTTPError{
+		Code:     "ACCOUNT_NOT_EMPTY",
+		Message:  "Account balance must be zero or swept to another account before closing",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrLimitExceeded = &HTTPError{
+		Code:     "LIMIT_EXCEEDED",
+		Message:  "Transfer exceeds a velocity limit",
+		Status:   http.StatusUnprocessableEntity,
+		Override: false,
+	}
+
+	ErrVelocityLimitNotFound = &HTTPError{
+		Code:     "VELOCITY_LIMIT_NOT_FOUND",
+		Message:  "Velocity limit not found",
+		Status:   http.StatusNotFound,
+		Override: false,
+	}
+
+	ErrInterestProductNotFound = &HTTPError{
+		Code:     "INTEREST_PRODUCT_NOT_FOUND",
+		Message:  "Interest product not found",
+		Status:   http.StatusNotFound,
+		Override: false,
+	}
+
+	ErrFeeRuleNotFound = &HTTPError{
+		Code:     "FEE_RULE_NOT_FOUND",
+		Message:  "Fee rule not found",
+		Status:   http.StatusNotFound,
+		Override: false,
+	}
+
+	ErrWebhookNotFound = &HTTPError{
+		Code:     "WEBHOOK_NOT_FOUND",
+		Message:  "Webhook not found",
+		Status:   http.StatusNotFound,
+		Override: false,
+	}
+
+	ErrWebhookDeadLetterNotFound = &HTTPError{
+		Code:     "WEBHOOK_DEAD_LETTER_NOT_FOUND",
+		Message:  "Webhook dead letter not found",
+		Status:   http.StatusNotFound,
+		Override: false,
+	}
+
+	ErrDeadLetterAlreadyReplayed = &HTTPError{
+		Code:     "DEAD_LETTER_ALREADY_REPLAYED",
+		Message:  "Dead letter was already replayed",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrConsumerGroupNotFound = &HTTPError{
+		Code:     "CONSUMER_GROUP_NOT_FOUND",
+		Message:  "Consumer group not found",
+		Status:   http.StatusNotFound,
+		Override: false,
+	}
+
+	ErrConsumerGroupOffsetBehind = &HTTPError{
+		Code:     "CONSUMER_GROUP_OFFSET_BEHIND",
+		Message:  "Consumer group has already committed a later offset",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
+	ErrCursorAheadOfFeed = &HTTPError{
+		Code:     "CURSOR_AHEAD_OF_FEED",
+		Message:  "Cursor is past the last event in the feed",
+		Status:   http.StatusUnprocessableEntity,
+		Override: false,
+	}
+
+	ErrAccountNotOpenAsOf = &HTTPError{
+		Code:     "ACCOUNT_NOT_OPEN_AS_OF",
+		Message:  "Account was opened after the requested time",
+		Status:   http.StatusUnprocessableEntity,
+		Override: false,
+	}
+
+	ErrLimitConflictsWithBalance = &HTTPError{
+		Code:     "LIMIT_CONFLICTS_WITH_BALANCE",
+		Message:  "The current balance is outside the requested limits",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
+
 	ErrInvalidFormat = &HTTPError{
 		Code:     "INVALID_FORMAT",
 		Message:  "Invalid format",
@@ -99,6 +386,62 @@ var (
 		Override: false,
 	}
 
+	ErrInvalidTransactionID = &HTTPError{
+		Code:     "INVALID_TRANSACTION_ID",
+		Message:  "Invalid transaction ID format",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrInvalidStandingOrderID = &HTTPError{
+		Code:     "INVALID_STANDING_ORDER_ID",
+		Message:  "Invalid standing order ID format",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrInvalidHoldID = &HTTPError{
+		Code:     "INVALID_HOLD_ID",
+		Message:  "Invalid hold ID format",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrInvalidVelocityLimitID = &HTTPError{
+		Code:     "INVALID_VELOCITY_LIMIT_ID",
+		Message:  "Invalid velocity limit ID format",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrInvalidFeeRuleID = &HTTPError{
+		Code:     "INVALID_FEE_RULE_ID",
+		Message:  "Invalid fee rule ID format",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrInvalidWebhookID = &HTTPError{
+		Code:     "INVALID_WEBHOOK_ID",
+		Message:  "Invalid webhook ID format",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrInvalidDeadLetterID = &HTTPError{
+		Code:     "INVALID_DEAD_LETTER_ID",
+		Message:  "Invalid dead letter ID format",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
+	ErrInvalidFXQuoteID = &HTTPError{
+		Code:     "INVALID_FX_QUOTE_ID",
+		Message:  "Invalid FX quote ID format",
+		Status:   http.StatusBadRequest,
+		Override: false,
+	}
+
 	ErrInvalidRequest = &HTTPError{
 		Code:     "INVALID_REQUEST",
 		Message:  "Invalid request format",
@@ -112,6 +455,20 @@ var (
 		Status:   http.StatusBadRequest,
 		Override: false,
 	}
+
+	ErrIdempotencyKeyReused = &HTTPError{
+		Code:     "IDEMPOTENCY_KEY_REUSED",
+		Message:  "Idempotency key was already used with a different request payload",
+		Status:   http.StatusUnprocessableEntity,
+		Override: false,
+	}
+
+	ErrIdempotencyKeyInProgress = &HTTPError{
+		Code:     "IDEMPOTENCY_KEY_IN_PROGRESS",
+		Message:  "A request with this idempotency key is still being processed",
+		Status:   http.StatusConflict,
+		Override: false,
+	}
 )
 
 // IsHTTPError checks if an error is an HTTPError
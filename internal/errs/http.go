@@ -53,6 +53,17 @@ func (e *HTTPError) WithMessage(message string) *HTTPError {
 	}
 }
 
+func (e *HTTPError) WithErrors(errors []FieldError) *HTTPError {
+	return &HTTPError{
+		Code:     e.Code,
+		Message:  e.Message,
+		Status:   e.Status,
+		Override: e.Override,
+		Errors:   errors,
+		Action:   e.Action,
+	}
+}
+
 func MakeUpperCaseWithUnderscores(str string) string {
 	return strings.ToUpper(strings.ReplaceAll(str, " ", "_"))
 }
@@ -0,0 +1,85 @@
+// Package event describes the state changes recorded in the outbox and the publishers that
+// deliver them to downstream systems.
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Type names a state change, such as transaction.completed
+type Type string
+
+const (
+	TypeAccountCreated       Type = "account.created"
+	TypeAccountStatusChanged Type = "account.status_changed"
+	TypeTransactionCompleted Type = "transaction.completed"
+	TypeTransactionFailed    Type = "transaction.failed"
+	TypeTransactionCancelled Type = "transaction.cancelled"
+	// TypeTransactionReversed is recorded for the original transfer whenever a reversal sends
+	// money back, with its status reversed or partially_reversed
+	TypeTransactionReversed Type = "transaction.reversed"
+)
+
+// Types lists every event type in a stable order
+var Types = []Type{
+	TypeAccountCreated,
+	TypeAccountStatusChanged,
+	TypeTransactionCompleted,
+	TypeTransactionFailed,
+	TypeTransactionCancelled,
+	TypeTransactionReversed,
+}
+
+// Valid reports whether t is a known event type
+func (t Type) Valid() bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// AggregateType is the kind of record an event is about
+type AggregateType string
+
+const (
+	AggregateAccount     AggregateType = "account"
+	AggregateTransaction AggregateType = "transaction"
+)
+
+// Event is a state change as it is stored in the outbox and published. IDs increase in the
+// order events were recorded.
+type Event struct {
+	ID            int64         `json:"id"`
+	Type          Type          `json:"type"`
+	AggregateType AggregateType `json:"aggregate_type"`
+	AggregateID   int64         `json:"aggregate_id"`
+	// Data is the API representation of the record after the change
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// New builds an event whose data is data encoded as JSON
+func New(eventType Type, aggregateType AggregateType, aggregateID int64, data interface{}) (*Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Data:          raw,
+	}, nil
+}
+
+// Publisher delivers events to downstream systems. Events are handed over in ID order and
+// may be delivered more than once, so consumers deduplicate on the event ID.
+type Publisher interface {
+	Publish(ctx context.Context, event *Event) error
+	Close() error
+}
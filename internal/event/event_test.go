@@ -0,0 +1,96 @@
+package event_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEvent(t *testing.T, id int64) *event.Event {
+	t.Helper()
+
+	e, err := event.New(event.TypeTransactionCompleted, event.AggregateTransaction, 42, map[string]string{"status": "completed"})
+	require.NoError(t, err)
+	e.ID = id
+	e.CreatedAt = time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	return e
+}
+
+func TestNew_EncodesData(t *testing.T) {
+	e := newEvent(t, 1)
+
+	assert.Equal(t, event.TypeTransactionCompleted, e.Type)
+	assert.Equal(t, event.AggregateTransaction, e.AggregateType)
+	assert.Equal(t, int64(42), e.AggregateID)
+	assert.JSONEq(t, `{"status":"completed"}`, string(e.Data))
+}
+
+func TestType_Valid(t *testing.T) {
+	for _, known := range event.Types {
+		assert.True(t, known.Valid(), known)
+	}
+	assert.False(t, event.Type("transaction.deleted").Valid())
+}
+
+func TestWriterPublisher_WritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := event.NewWriterPublisher(&buf)
+
+	require.NoError(t, publisher.Publish(context.Background(), newEvent(t, 1)))
+	require.NoError(t, publisher.Publish(context.Background(), newEvent(t, 2)))
+	require.NoError(t, publisher.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var decoded event.Event
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &decoded))
+	assert.Equal(t, int64(2), decoded.ID)
+	assert.Equal(t, event.TypeTransactionCompleted, decoded.Type)
+	assert.JSONEq(t, `{"status":"completed"}`, string(decoded.Data))
+}
+
+func TestOpenFilePublisher_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	for id := int64(1); id <= 2; id++ {
+		publisher, err := event.OpenFilePublisher(path)
+		require.NoError(t, err)
+		require.NoError(t, publisher.Publish(context.Background(), newEvent(t, id)))
+		require.NoError(t, publisher.Close())
+	}
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(content), "\n"))
+}
+
+func TestMemoryPublisher_KeepsEventsInOrder(t *testing.T) {
+	publisher := event.NewMemoryPublisher()
+
+	require.NoError(t, publisher.Publish(context.Background(), newEvent(t, 1)))
+	require.NoError(t, publisher.Publish(context.Background(), newEvent(t, 2)))
+
+	events := publisher.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(1), events[0].ID)
+	assert.Equal(t, int64(2), events[1].ID)
+}
+
+func TestMemoryPublisher_Err(t *testing.T) {
+	publisher := event.NewMemoryPublisher()
+	publisher.Err = errors.New("broker down")
+
+	assert.EqualError(t, publisher.Publish(context.Background(), newEvent(t, 1)), "broker down")
+	assert.Empty(t, publisher.Events())
+}
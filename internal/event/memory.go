@@ -0,0 +1,48 @@
+package event
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryPublisher keeps published events in memory, for tests. Err, when set, is returned by
+// Publish instead of keeping the event.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []*Event
+	Err    error
+}
+
+// NewMemoryPublisher returns an empty in-memory publisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish keeps a copy of event
+func (p *MemoryPublisher) Publish(_ context.Context, event *Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.Err != nil {
+		return p.Err
+	}
+
+	published := *event
+	p.events = append(p.events, &published)
+	return nil
+}
+
+// Events returns the events published so far, in order
+func (p *MemoryPublisher) Events() []*Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	events := make([]*Event, len(p.events))
+	copy(events, p.events)
+	return events
+}
+
+// Close does nothing
+func (p *MemoryPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,127 @@
+// Package fee computes transfer fees from flat, percentage and tiered schedules.
+package fee
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Kind is how a schedule prices a transfer
+type Kind string
+
+const (
+	// KindFlat charges the same fee for every amount
+	KindFlat Kind = "flat"
+	// KindPercentage charges a share of the amount, optionally bounded by a minimum and maximum
+	KindPercentage Kind = "percentage"
+	// KindTiered charges the fee of the first tier the amount falls into
+	KindTiered Kind = "tiered"
+)
+
+// Tier prices amounts up to and including UpTo. The last tier may leave UpTo unset to cover
+// every larger amount.
+type Tier struct {
+	UpTo *decimal.Decimal `json:"up_to,omitempty"`
+	Flat decimal.Decimal  `json:"flat"`
+	Rate decimal.Decimal  `json:"rate"`
+}
+
+// Schedule is the pricing of one fee rule
+type Schedule struct {
+	Kind Kind
+	Flat decimal.Decimal
+	// Rate is a fraction of the amount, 0.015 for 1.5%
+	Rate  decimal.Decimal
+	Min   *decimal.Decimal
+	Max   *decimal.Decimal
+	Tiers []Tier
+}
+
+// Compute returns the fee for amount, rounded half up to precision decimals
+func (s Schedule) Compute(amount decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	var fee decimal.Decimal
+	switch s.Kind {
+	case KindFlat:
+		fee = s.Flat
+	case KindPercentage:
+		fee = amount.Mul(s.Rate).Round(precision)
+		if s.Min != nil && fee.LessThan(*s.Min) {
+			fee = *s.Min
+		}
+		if s.Max != nil && fee.GreaterThan(*s.Max) {
+			fee = *s.Max
+		}
+	case KindTiered:
+		tier, ok := s.tierFor(amount)
+		if !ok {
+			return decimal.Zero, fmt.Errorf("no tier covers amount %s", amount.String())
+		}
+		fee = tier.Flat.Add(amount.Mul(tier.Rate))
+	default:
+		return decimal.Zero, fmt.Errorf("unknown fee kind %q", s.Kind)
+	}
+
+	return fee.Round(precision), nil
+}
+
+// tierFor returns the tier with the lowest bound at or above amount
+func (s Schedule) tierFor(amount decimal.Decimal) (Tier, bool) {
+	tiers := make([]Tier, len(s.Tiers))
+	copy(tiers, s.Tiers)
+	sort.SliceStable(tiers, func(i, j int) bool {
+		if tiers[i].UpTo == nil || tiers[j].UpTo == nil {
+			return tiers[j].UpTo == nil && tiers[i].UpTo != nil
+		}
+		return tiers[i].UpTo.LessThan(*tiers[j].UpTo)
+	})
+
+	for _, tier := range tiers {
+		if tier.UpTo == nil || amount.LessThanOrEqual(*tier.UpTo) {
+			return tier, true
+		}
+	}
+
+	return Tier{}, false
+}
+
+// Validate checks that the schedule can price amounts
+func (s Schedule) Validate() error {
+	switch s.Kind {
+	case KindFlat:
+		if !s.Flat.IsPositive() {
+			return fmt.Errorf("flat fee must be positive")
+		}
+	case KindPercentage:
+		if !s.Rate.IsPositive() || s.Rate.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+			return fmt.Errorf("rate must be between 0 and 1")
+		}
+		if s.Min != nil && s.Min.IsNegative() {
+			return fmt.Errorf("minimum fee cannot be negative")
+		}
+		if s.Min != nil && s.Max != nil && s.Min.GreaterThan(*s.Max) {
+			return fmt.Errorf("minimum fee exceeds maximum fee")
+		}
+	case KindTiered:
+		if len(s.Tiers) == 0 {
+			return fmt.Errorf("tiered fee needs at least one tier")
+		}
+		open := 0
+		for _, tier := range s.Tiers {
+			if tier.UpTo == nil {
+				open++
+			}
+			if tier.Flat.IsNegative() || tier.Rate.IsNegative() || tier.Rate.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+				return fmt.Errorf("tier fees must be non-negative and rates below 1")
+			}
+		}
+		if open > 1 {
+			return fmt.Errorf("only one tier may leave up_to unset")
+		}
+	default:
+		return fmt.Errorf("unknown fee kind %q", s.Kind)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package fee_test
+
+import (
+	"testing"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/fee"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func d(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func ptr(value string) *decimal.Decimal {
+	parsed := d(value)
+	return &parsed
+}
+
+func TestCompute_Flat(t *testing.T) {
+	charged, err := fee.Schedule{Kind: fee.KindFlat, Flat: d("1.50")}.Compute(d("1000"), 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, "1.5", charged.String())
+}
+
+func TestCompute_PercentageIsBoundedByMinAndMax(t *testing.T) {
+	schedule := fee.Schedule{Kind: fee.KindPercentage, Rate: d("0.015"), Min: ptr("0.50"), Max: ptr("25")}
+
+	tests := []struct {
+		amount string
+		want   string
+	}{
+		{"10", "0.5"},
+		{"100.10", "1.5"},
+		{"333.33", "5"},
+		{"5000", "25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount, func(t *testing.T) {
+			charged, err := schedule.Compute(d(tt.amount), 2)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, charged.String())
+		})
+	}
+}
+
+func TestCompute_TieredPicksTheFirstTierCoveringTheAmount(t *testing.T) {
+	schedule := fee.Schedule{Kind: fee.KindTiered, Tiers: []fee.Tier{
+		{Flat: d("10"), Rate: d("0.001")},
+		{UpTo: ptr("100"), Flat: d("0.25")},
+		{UpTo: ptr("1000"), Flat: d("1"), Rate: d("0.002")},
+	}}
+
+	small, err := schedule.Compute(d("100"), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "0.25", small.String())
+
+	medium, err := schedule.Compute(d("500"), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "2", medium.String())
+
+	large, err := schedule.Compute(d("20000"), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "30", large.String())
+}
+
+func TestCompute_TieredWithoutCoveringTier(t *testing.T) {
+	schedule := fee.Schedule{Kind: fee.KindTiered, Tiers: []fee.Tier{{UpTo: ptr("100"), Flat: d("1")}}}
+
+	_, err := schedule.Compute(d("100.01"), 2)
+
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, fee.Schedule{Kind: fee.KindPercentage, Rate: d("0.01")}.Validate())
+	assert.Error(t, fee.Schedule{Kind: fee.KindPercentage, Rate: d("1.5")}.Validate())
+	assert.Error(t, fee.Schedule{Kind: fee.KindPercentage, Rate: d("0.01"), Min: ptr("5"), Max: ptr("1")}.Validate())
+	assert.Error(t, fee.Schedule{Kind: fee.KindFlat}.Validate())
+	assert.Error(t, fee.Schedule{Kind: fee.KindTiered, Tiers: []fee.Tier{{Flat: d("1")}, {Flat: d("2")}}}.Validate())
+}
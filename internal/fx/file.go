@@ -0,0 +1,143 @@
+package fx
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// StaticProvider serves a fixed set of rates loaded once, for running without a rates feed
+type StaticProvider struct {
+	rates map[string]*Rate
+}
+
+// fileRate is a rate as written in a JSON or CSV rates file
+type fileRate struct {
+	Base      string `json:"base"`
+	Quote     string `json:"quote"`
+	Rate      string `json:"rate"`
+	Timestamp string `json:"timestamp"`
+}
+
+// NewStaticProvider builds a provider from the given rates. A later rate for the same pair
+// replaces an earlier one.
+func NewStaticProvider(rates []*Rate) *StaticProvider {
+	p := &StaticProvider{rates: make(map[string]*Rate, len(rates))}
+	for _, rate := range rates {
+		p.rates[pairKey(rate.Base, rate.Quote)] = rate
+	}
+	return p
+}
+
+// LoadFile reads rates from a .json file holding an array of {base, quote, rate, timestamp}
+// objects or from a .csv file with a base,quote,rate,timestamp header. Timestamps are RFC 3339.
+func LoadFile(path string) (*StaticProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rates file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []fileRate
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.NewDecoder(f).Decode(&entries); err != nil {
+			return nil, fmt.Errorf("failed to decode rates file: %w", err)
+		}
+	case ".csv":
+		entries, err = readCSV(f)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported rates file %s, expected .json or .csv", path)
+	}
+
+	rates := make([]*Rate, 0, len(entries))
+	for i, entry := range entries {
+		rate, err := entry.parse()
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate %d in %s: %w", i+1, path, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return NewStaticProvider(rates), nil
+}
+
+// Rate implements RateProvider
+func (p *StaticProvider) Rate(_ context.Context, base, quote string) (*Rate, error) {
+	rate, ok := p.rates[pairKey(base, quote)]
+	if !ok {
+		return nil, ErrRateNotFound
+	}
+
+	copied := *rate
+	return &copied, nil
+}
+
+func readCSV(r io.Reader) ([]fileRate, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates file: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"base", "quote", "rate", "timestamp"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("rates file is missing the %s column", name)
+		}
+	}
+
+	entries := make([]fileRate, 0, len(records)-1)
+	for _, record := range records[1:] {
+		entries = append(entries, fileRate{
+			Base:      record[columns["base"]],
+			Quote:     record[columns["quote"]],
+			Rate:      record[columns["rate"]],
+			Timestamp: record[columns["timestamp"]],
+		})
+	}
+
+	return entries, nil
+}
+
+func (e fileRate) parse() (*Rate, error) {
+	value, err := decimal.NewFromString(strings.TrimSpace(e.Rate))
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate value: %w", err)
+	}
+	if !value.IsPositive() {
+		return nil, fmt.Errorf("rate must be positive")
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, strings.TrimSpace(e.Timestamp))
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp: %w", err)
+	}
+
+	return &Rate{
+		Base:      strings.ToUpper(strings.TrimSpace(e.Base)),
+		Quote:     strings.ToUpper(strings.TrimSpace(e.Quote)),
+		Value:     value,
+		Timestamp: timestamp,
+	}, nil
+}
+
+func pairKey(base, quote string) string {
+	return base + "/" + quote
+}
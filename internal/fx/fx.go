@@ -0,0 +1,80 @@
+// Package fx quotes exchange rates for transfers between accounts of different currencies
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrRateNotFound is returned by a RateProvider that has no rate for a currency pair
+var ErrRateNotFound = errors.New("fx rate not found")
+
+// Rate is the price of one unit of Base in units of Quote
+type Rate struct {
+	Base      string
+	Quote     string
+	Value     decimal.Decimal
+	Timestamp time.Time
+}
+
+// RateProvider quotes the current rate for a currency pair
+type RateProvider interface {
+	// Rate returns the latest rate converting base into quote, or ErrRateNotFound
+	Rate(ctx context.Context, base, quote string) (*Rate, error)
+}
+
+// ParseAccounts parses a comma separated list of CURRENCY:ACCOUNT_ID pairs, such as
+// "USD:900001,EUR:900002", into a map keyed by currency
+func ParseAccounts(value string) (map[string]int64, error) {
+	accounts := make(map[string]int64)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		code, id, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid account mapping %q, expected CURRENCY:ACCOUNT_ID", pair)
+		}
+
+		accountID, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+		if err != nil || accountID < 1 {
+			return nil, fmt.Errorf("invalid account id in mapping %q", pair)
+		}
+
+		accounts[strings.ToUpper(strings.TrimSpace(code))] = accountID
+	}
+
+	return accounts, nil
+}
+
+// Conversion is an amount converted at a rate less a spread
+type Conversion struct {
+	// EffectiveRate is the rate after the spread was taken off
+	EffectiveRate decimal.Decimal
+	// Amount is the converted amount rounded down to the precision of the target currency
+	Amount decimal.Decimal
+	// Remainder is what rounding down cut off, kept at remainderScale decimals
+	Remainder decimal.Decimal
+}
+
+// Convert converts amount at rate with the spread, a fraction such as 0.0025, taken off the rate.
+// The result is rounded down to precision decimals and the remainder is cut at remainderScale.
+func Convert(amount, rate, spread decimal.Decimal, precision, remainderScale int32) Conversion {
+	effective := rate.Mul(decimal.NewFromInt(1).Sub(spread))
+	exact := amount.Mul(effective)
+	converted := exact.RoundFloor(precision)
+
+	return Conversion{
+		EffectiveRate: effective,
+		Amount:        converted,
+		Remainder:     exact.Sub(converted).RoundFloor(remainderScale),
+	}
+}
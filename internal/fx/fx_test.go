@@ -0,0 +1,85 @@
+package fx_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/fx"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert_RoundsDownAndKeepsRemainder(t *testing.T) {
+	// 100.00 EUR at 1.08427 less a 0.25% spread is 108.1589325 USD
+	conversion := fx.Convert(
+		decimal.RequireFromString("100"),
+		decimal.RequireFromString("1.08427"),
+		decimal.RequireFromString("0.0025"),
+		2, 5,
+	)
+
+	assert.Equal(t, "1.0815593250", conversion.EffectiveRate.StringFixed(10))
+	assert.Equal(t, "108.15", conversion.Amount.String())
+	assert.Equal(t, "0.00593", conversion.Remainder.String())
+}
+
+func TestConvert_ZeroDecimalCurrency(t *testing.T) {
+	conversion := fx.Convert(
+		decimal.RequireFromString("10.50"),
+		decimal.RequireFromString("161.237"),
+		decimal.Zero,
+		0, 5,
+	)
+
+	assert.Equal(t, "1692", conversion.Amount.String())
+	assert.Equal(t, "0.9885", conversion.Remainder.String())
+}
+
+func TestLoadFile_JSONAndCSV(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "rates.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(`[
+		{"base": "EUR", "quote": "USD", "rate": "1.08427", "timestamp": "2026-10-16T08:00:00Z"}
+	]`), 0o600))
+
+	csvPath := filepath.Join(dir, "rates.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("base,quote,rate,timestamp\nusd,jpy,149.52,2026-10-16T08:00:00Z\n"), 0o600))
+
+	provider, err := fx.LoadFile(jsonPath)
+	require.NoError(t, err)
+	rate, err := provider.Rate(context.Background(), "EUR", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "1.08427", rate.Value.String())
+	assert.Equal(t, time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC), rate.Timestamp)
+
+	provider, err = fx.LoadFile(csvPath)
+	require.NoError(t, err)
+	rate, err = provider.Rate(context.Background(), "USD", "JPY")
+	require.NoError(t, err)
+	assert.Equal(t, "149.52", rate.Value.String())
+
+	_, err = provider.Rate(context.Background(), "JPY", "USD")
+	assert.ErrorIs(t, err, fx.ErrRateNotFound)
+}
+
+func TestLoadFile_RejectsUnknownExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.txt")
+	require.NoError(t, os.WriteFile(path, []byte("EUR USD 1.08"), 0o600))
+
+	_, err := fx.LoadFile(path)
+	assert.Error(t, err)
+}
+
+func TestParseAccounts(t *testing.T) {
+	accounts, err := fx.ParseAccounts("USD:900001, eur:900002")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"USD": 900001, "EUR": 900002}, accounts)
+
+	_, err = fx.ParseAccounts("USD=900001")
+	assert.Error(t, err)
+}
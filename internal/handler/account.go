@@ -31,6 +31,7 @@ func (h *AccountHandler) CreateAccount(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return h.HandleBindError(c, err)
 	}
+	req.IdempotencyKey = c.Request().Header.Get(IdempotencyKeyHeader)
 
 	if err := c.Validate(req); err != nil {
 		return h.HandleValidationError(c, err)
@@ -75,5 +76,179 @@ func (h *AccountHandler) GetAccount(c echo.Context) error {
 		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to get account"))
 	}
 
+	h.setETag(c, response.Version)
+	return h.RespondOK(c, response)
+}
+
+// UpdateAccountLimits handles PUT /admin/accounts/{account_id}/limits
+func (h *AccountHandler) UpdateAccountLimits(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	var req model.UpdateAccountLimitsRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.AccountID = accountID
+
+	expectedVersion, rejection := h.expectedVersion(c)
+	if rejection != nil {
+		return h.RespondWithHTTPError(c, rejection)
+	}
+	req.ExpectedVersion = expectedVersion
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.accountService.UpdateLimits(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		if strings.Contains(err.Error(), "invalid amount format") {
+			return h.RespondWithHTTPError(c, errs.ErrInvalidFormat.WithMessage("Invalid amount format"))
+		}
+
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to update account limits")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to update account limits"))
+	}
+
+	h.setETag(c, response.Version)
+	return h.RespondOK(c, response)
+}
+
+// ListAccountLimitChanges handles GET /admin/accounts/{account_id}/limits/history
+func (h *AccountHandler) ListAccountLimitChanges(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	response, err := h.accountService.ListLimitChanges(c.Request().Context(), accountID)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to list account limit changes")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to list account limit changes"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// FreezeAccount handles POST /accounts/{account_id}/freeze
+func (h *AccountHandler) FreezeAccount(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	var req model.FreezeAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.AccountID = accountID
+
+	expectedVersion, rejection := h.expectedVersion(c)
+	if rejection != nil {
+		return h.RespondWithHTTPError(c, rejection)
+	}
+	req.ExpectedVersion = expectedVersion
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.accountService.FreezeAccount(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to freeze account")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to freeze account"))
+	}
+
+	h.setETag(c, response.Version)
+	return h.RespondOK(c, response)
+}
+
+// UnfreezeAccount handles POST /accounts/{account_id}/unfreeze
+func (h *AccountHandler) UnfreezeAccount(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	var req model.UnfreezeAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.AccountID = accountID
+
+	expectedVersion, rejection := h.expectedVersion(c)
+	if rejection != nil {
+		return h.RespondWithHTTPError(c, rejection)
+	}
+	req.ExpectedVersion = expectedVersion
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.accountService.UnfreezeAccount(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to unfreeze account")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to unfreeze account"))
+	}
+
+	h.setETag(c, response.Version)
+	return h.RespondOK(c, response)
+}
+
+// CloseAccount handles POST /accounts/{account_id}/close
+func (h *AccountHandler) CloseAccount(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	var req model.CloseAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.AccountID = accountID
+	req.IdempotencyKey = c.Request().Header.Get(IdempotencyKeyHeader)
+
+	expectedVersion, rejection := h.expectedVersion(c)
+	if rejection != nil {
+		return h.RespondWithHTTPError(c, rejection)
+	}
+	req.ExpectedVersion = expectedVersion
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.accountService.CloseAccount(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to close account")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to close account"))
+	}
+
+	h.setETag(c, response.Version)
 	return h.RespondOK(c, response)
 }
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// AccountStreamHandler handles live account streams
+type AccountStreamHandler struct {
+	*BaseHandler
+	streamService *service.AccountStreamService
+}
+
+// NewAccountStreamHandler creates a new account stream handler
+func NewAccountStreamHandler(base *BaseHandler, streamService *service.AccountStreamService) *AccountStreamHandler {
+	return &AccountStreamHandler{
+		BaseHandler:   base,
+		streamService: streamService,
+	}
+}
+
+// StreamAccount handles GET /accounts/{account_id}/stream. Browsers cannot set headers on an
+// EventSource, so the first connection may resume with a last_event_id query parameter instead.
+func (h *AccountStreamHandler) StreamAccount(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	lastEventIDValue := c.Request().Header.Get(LastEventIDHeader)
+	if lastEventIDValue == "" {
+		lastEventIDValue = c.QueryParam("last_event_id")
+	}
+	lastEventID, err := ParseLastEventID(lastEventIDValue)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidFormat.WithMessage("Last-Event-ID must be an event ID sent on this stream"))
+	}
+
+	ctx := c.Request().Context()
+
+	stream, err := h.streamService.OpenStream(ctx, accountID, lastEventID)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to open account stream")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to open account stream"))
+	}
+	defer stream.Close()
+
+	res := c.Response()
+
+	// The server write timeout is meant for ordinary responses and would cut the stream off
+	if err := http.NewResponseController(res).SetWriteDeadline(time.Time{}); err != nil {
+		h.Logger.Warn().Err(err).Msg("failed to clear write deadline of account stream")
+	}
+
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	// Stops nginx and similar proxies from buffering events
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	err = stream.Run(ctx, func(event *model.AccountStreamEvent) error {
+		if event.Type == model.AccountStreamEventHeartbeat {
+			if err := WriteComment(res, "heartbeat"); err != nil {
+				return err
+			}
+			res.Flush()
+			return nil
+		}
+
+		data, err := json.Marshal(event.Data)
+		if err != nil {
+			return err
+		}
+		if err := WriteEvent(res, event.ID, string(event.Type), data); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("account stream failed")
+	}
+
+	// The response has started, so there is nothing left to send
+	return nil
+}
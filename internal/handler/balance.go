@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// BalanceHandler handles historical balance requests
+type BalanceHandler struct {
+	*BaseHandler
+	snapshotService *service.BalanceSnapshotService
+}
+
+// NewBalanceHandler creates a new balance handler
+func NewBalanceHandler(base *BaseHandler, snapshotService *service.BalanceSnapshotService) *BalanceHandler {
+	return &BalanceHandler{
+		BaseHandler:     base,
+		snapshotService: snapshotService,
+	}
+}
+
+// GetBalance handles GET /accounts/{account_id}/balance
+func (h *BalanceHandler) GetBalance(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	var req model.GetAccountBalanceRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.AccountID = accountID
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.snapshotService.GetBalance(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to get account balance")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to get account balance"))
+	}
+
+	return h.RespondOK(c, response)
+}
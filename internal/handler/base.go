@@ -10,6 +10,9 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// IdempotencyKeyHeader carries the client-chosen key that makes a create request safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
 // BaseHandler provides base functionality for all handlers
 type BaseHandler struct {
 	Server *server.Server
@@ -39,8 +42,18 @@ func (h *BaseHandler) RespondError(c echo.Context, statusCode int, errorCode, me
 	})
 }
 
-// RespondWithHTTPError sends an error response using an HTTPError
+// RespondWithHTTPError sends an error response using an HTTPError, including its field errors
 func (h *BaseHandler) RespondWithHTTPError(c echo.Context, httpErr *errs.HTTPError) error {
+	if len(httpErr.Errors) > 0 {
+		return c.JSON(httpErr.Status, map[string]interface{}{
+			"error": map[string]interface{}{
+				"code":    httpErr.Code,
+				"message": httpErr.Message,
+				"errors":  httpErr.Errors,
+			},
+		})
+	}
+
 	return h.RespondError(c, httpErr.Status, httpErr.Code, httpErr.Message)
 }
 
@@ -73,6 +86,8 @@ func (h *BaseHandler) HandleValidationError(c echo.Context, err error) error {
 				message = field + " must be numeric"
 			case "nefield":
 				message = field + " must be different from " + ve[0].Param()
+			case "oneof":
+				message = field + " must be one of: " + ve[0].Param()
 			default:
 				message = field + " is invalid"
 			}
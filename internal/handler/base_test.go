@@ -0,0 +1,51 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/handler"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespondWithHTTPError_IncludesFieldErrors(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/v1/transactions/batch", nil), rec)
+
+	h := &handler.BaseHandler{}
+	err := h.RespondWithHTTPError(c, errs.ErrBatchRejected.WithErrors([]errs.FieldError{
+		{Field: "legs[1].amount", Error: "Insufficient balance in source account"},
+	}))
+	require.NoError(t, err)
+
+	var body struct {
+		Error struct {
+			Code   string            `json:"code"`
+			Errors []errs.FieldError `json:"errors"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "BATCH_REJECTED", body.Error.Code)
+	require.Len(t, body.Error.Errors, 1)
+	assert.Equal(t, "legs[1].amount", body.Error.Errors[0].Field)
+}
+
+func TestRespondWithHTTPError_OmitsEmptyFieldErrors(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/accounts/1", nil), rec)
+
+	h := &handler.BaseHandler{}
+	require.NoError(t, h.RespondWithHTTPError(c, errs.ErrAccountNotFound))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.NotContains(t, rec.Body.String(), `"errors"`)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// ETagHeader carries the version of an account in its responses
+	ETagHeader = "ETag"
+	// IfMatchHeader makes a change to an account conditional on the version the client last read
+	IfMatchHeader = "If-Match"
+)
+
+var (
+	// ErrInvalidETag is returned when an If-Match header is not an ETag this service issued
+	ErrInvalidETag = errors.New("invalid etag")
+	// ErrETagList is returned when an If-Match header lists several ETags. An account change
+	// expects the one version it was prepared against, so lists are not supported.
+	ErrETagList = errors.New("etag list not supported")
+)
+
+// FormatETag returns the strong entity tag of an account version
+func FormatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ParseIfMatch returns the account version an If-Match header expects. An empty header or *
+// matches any version and returns nil. Weak tags are rejected, as If-Match compares strongly,
+// and a comma-separated list such as "3", "4" is rejected with ErrETagList.
+func ParseIfMatch(header string) (*int64, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	// Entity tags cannot contain commas, so any comma separates list members
+	if strings.Contains(header, ",") {
+		return nil, ErrETagList
+	}
+
+	if len(header) < 2 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		return nil, ErrInvalidETag
+	}
+
+	version, err := strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil || version < 1 {
+		return nil, ErrInvalidETag
+	}
+
+	return &version, nil
+}
+
+// expectedVersion reads the If-Match header of a request that changes an account
+func (h *BaseHandler) expectedVersion(c echo.Context) (*int64, *errs.HTTPError) {
+	version, err := ParseIfMatch(c.Request().Header.Get(IfMatchHeader))
+	if errors.Is(err, ErrETagList) {
+		return nil, errs.ErrInvalidFormat.WithMessage("If-Match must be a single ETag, lists are not supported")
+	}
+	if err != nil {
+		return nil, errs.ErrInvalidFormat.WithMessage("If-Match must be an ETag returned for the account")
+	}
+
+	return version, nil
+}
+
+// setETag sends the version of the account in a response
+func (h *BaseHandler) setETag(c echo.Context, version int64) {
+	c.Response().Header().Set(ETagHeader, FormatETag(version))
+}
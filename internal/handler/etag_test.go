@@ -0,0 +1,38 @@
+package handler_test
+
+import (
+	"testing"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatETag_RoundTrip(t *testing.T) {
+	version, err := handler.ParseIfMatch(handler.FormatETag(42))
+	require.NoError(t, err)
+	require.NotNil(t, version)
+	assert.Equal(t, int64(42), *version)
+}
+
+func TestParseIfMatch_AnyVersion(t *testing.T) {
+	for _, header := range []string{"", "*", " * "} {
+		version, err := handler.ParseIfMatch(header)
+		require.NoError(t, err, header)
+		assert.Nil(t, version, header)
+	}
+}
+
+func TestParseIfMatch_Invalid(t *testing.T) {
+	for _, header := range []string{`7`, `W/"7"`, `"abc"`, `"0"`, `"`} {
+		_, err := handler.ParseIfMatch(header)
+		assert.ErrorIs(t, err, handler.ErrInvalidETag, header)
+	}
+}
+
+func TestParseIfMatch_List(t *testing.T) {
+	for _, header := range []string{`"3", "4"`, `"3","4"`, `"3", *`, `W/"3", "4"`, `"3",`} {
+		_, err := handler.ParseIfMatch(header)
+		assert.ErrorIs(t, err, handler.ErrETagList, header)
+	}
+}
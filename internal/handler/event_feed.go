@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// EventFeedHandler handles the event feed and its consumer groups
+type EventFeedHandler struct {
+	*BaseHandler
+	feedService *service.EventFeedService
+}
+
+// NewEventFeedHandler creates a new event feed handler
+func NewEventFeedHandler(base *BaseHandler, feedService *service.EventFeedService) *EventFeedHandler {
+	return &EventFeedHandler{
+		BaseHandler: base,
+		feedService: feedService,
+	}
+}
+
+// ListEvents handles GET /events
+func (h *EventFeedHandler) ListEvents(c echo.Context) error {
+	var req model.ListEventsRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	// A long poll may outlast the server write timeout, which would drop the response
+	if req.Wait > 0 {
+		writeTimeout := time.Duration(h.Server.Config.Server.WriteTimeout) * time.Second
+		deadline := time.Now().Add(time.Duration(req.Wait)*time.Second + writeTimeout)
+		if err := http.NewResponseController(c.Response()).SetWriteDeadline(deadline); err != nil {
+			h.Logger.Warn().Err(err).Msg("failed to extend write deadline of event feed")
+		}
+	}
+
+	ctx := c.Request().Context()
+
+	response, err := h.feedService.ListEvents(ctx, &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+		// The client went away while waiting
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to list events")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to list events"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// ListConsumerGroups handles GET /consumer-groups
+func (h *EventFeedHandler) ListConsumerGroups(c echo.Context) error {
+	response, err := h.feedService.ListConsumerGroups(c.Request().Context())
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to list consumer groups")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to list consumer groups"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// GetConsumerGroup handles GET /consumer-groups/{group}
+func (h *EventFeedHandler) GetConsumerGroup(c echo.Context) error {
+	name := c.Param("group")
+
+	response, err := h.feedService.GetConsumerGroup(c.Request().Context(), name)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Str("group", name).Msg("failed to get consumer group")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to get consumer group"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// CommitOffset handles PUT /consumer-groups/{group}/offset
+func (h *EventFeedHandler) CommitOffset(c echo.Context) error {
+	var req model.CommitConsumerGroupOffsetRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.Name = c.Param("group")
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.feedService.CommitOffset(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Str("group", req.Name).Msg("failed to commit consumer group offset")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to commit consumer group offset"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// DeleteConsumerGroup handles DELETE /consumer-groups/{group}
+func (h *EventFeedHandler) DeleteConsumerGroup(c echo.Context) error {
+	name := c.Param("group")
+
+	if err := h.feedService.DeleteConsumerGroup(c.Request().Context(), name); err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Str("group", name).Msg("failed to delete consumer group")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to delete consumer group"))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// FeeHandler handles fee rule HTTP requests
+type FeeHandler struct {
+	*BaseHandler
+	feeService *service.FeeService
+}
+
+// NewFeeHandler creates a new fee rule handler
+func NewFeeHandler(base *BaseHandler, feeService *service.FeeService) *FeeHandler {
+	return &FeeHandler{
+		BaseHandler: base,
+		feeService:  feeService,
+	}
+}
+
+// CreateRule handles POST /admin/fee-rules
+func (h *FeeHandler) CreateRule(c echo.Context) error {
+	var req model.CreateFeeRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.feeService.CreateRule(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to create fee rule")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to create fee rule"))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// ListRules handles GET /admin/fee-rules
+func (h *FeeHandler) ListRules(c echo.Context) error {
+	var req model.ListFeeRulesRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.feeService.ListRules(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to list fee rules")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to list fee rules"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// DeleteRule handles DELETE /admin/fee-rules/{rule_id}
+func (h *FeeHandler) DeleteRule(c echo.Context) error {
+	ruleID, err := strconv.ParseInt(c.Param("rule_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidFeeRuleID)
+	}
+
+	if err := h.feeService.DeleteRule(c.Request().Context(), ruleID); err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("fee_rule_id", ruleID).Msg("failed to delete fee rule")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to delete fee rule"))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// FXHandler handles FX quote HTTP requests
+type FXHandler struct {
+	*BaseHandler
+	fxService *service.FXService
+}
+
+// NewFXHandler creates a new FX handler
+func NewFXHandler(base *BaseHandler, fxService *service.FXService) *FXHandler {
+	return &FXHandler{
+		BaseHandler: base,
+		fxService:   fxService,
+	}
+}
+
+// CreateQuote handles POST /fx/quotes
+func (h *FXHandler) CreateQuote(c echo.Context) error {
+	var req model.CreateFXQuoteRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.fxService.CreateQuote(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		if strings.Contains(err.Error(), "invalid amount format") {
+			return h.RespondWithHTTPError(c, errs.ErrInvalidFormat.WithMessage("Invalid amount format"))
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to create fx quote")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to create FX quote"))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// GetQuote handles GET /fx/quotes/{quote_id}
+func (h *FXHandler) GetQuote(c echo.Context) error {
+	quoteID, err := strconv.ParseInt(c.Param("quote_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidFXQuoteID)
+	}
+
+	response, err := h.fxService.GetQuote(c.Request().Context(), quoteID)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("quote_id", quoteID).Msg("failed to get fx quote")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to get FX quote"))
+	}
+
+	return h.RespondOK(c, response)
+}
@@ -6,19 +6,41 @@ import (
 )
 
 type Handlers struct {
-	Health      *HealthHandler
-	OpenAPI     *OpenAPIHandler
-	Account     *AccountHandler
-	Transaction *TransactionHandler
+	Health        *HealthHandler
+	OpenAPI       *OpenAPIHandler
+	Account       *AccountHandler
+	AccountStream *AccountStreamHandler
+	Balance       *BalanceHandler
+	Statement     *StatementHandler
+	Transaction   *TransactionHandler
+	StandingOrder *StandingOrderHandler
+	Hold          *HoldHandler
+	FX            *FXHandler
+	Velocity      *VelocityHandler
+	Fee           *FeeHandler
+	Interest      *InterestHandler
+	Webhook       *WebhookHandler
+	EventFeed     *EventFeedHandler
 }
 
 func NewHandlers(s *server.Server, services *service.Services) *Handlers {
 	base := NewBaseHandler(s)
 
 	return &Handlers{
-		Health:      NewHealthHandler(s),
-		OpenAPI:     NewOpenAPIHandler(s),
-		Account:     NewAccountHandler(base, services.Account),
-		Transaction: NewTransactionHandler(base, services.Transaction),
+		Health:        NewHealthHandler(s),
+		OpenAPI:       NewOpenAPIHandler(s),
+		Account:       NewAccountHandler(base, services.Account),
+		AccountStream: NewAccountStreamHandler(base, services.AccountStream),
+		Balance:       NewBalanceHandler(base, services.Snapshot),
+		Statement:     NewStatementHandler(base, services.Statement),
+		Transaction:   NewTransactionHandler(base, services.Transaction),
+		StandingOrder: NewStandingOrderHandler(base, services.StandingOrder),
+		Hold:          NewHoldHandler(base, services.Hold),
+		FX:            NewFXHandler(base, services.FX),
+		Velocity:      NewVelocityHandler(base, services.Velocity),
+		Fee:           NewFeeHandler(base, services.Fee),
+		Interest:      NewInterestHandler(base, services.Interest),
+		Webhook:       NewWebhookHandler(base, services.Webhook),
+		EventFeed:     NewEventFeedHandler(base, services.EventFeed),
 	}
 }
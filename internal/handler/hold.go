@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// HoldHandler handles authorization hold HTTP requests
+type HoldHandler struct {
+	*BaseHandler
+	holdService *service.HoldService
+}
+
+// NewHoldHandler creates a new hold handler
+func NewHoldHandler(base *BaseHandler, holdService *service.HoldService) *HoldHandler {
+	return &HoldHandler{
+		BaseHandler: base,
+		holdService: holdService,
+	}
+}
+
+// CreateHold handles POST /accounts/{account_id}/holds
+func (h *HoldHandler) CreateHold(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	var req model.CreateHoldRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.AccountID = accountID
+	req.IdempotencyKey = c.Request().Header.Get(IdempotencyKeyHeader)
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.holdService.PlaceHold(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		if strings.Contains(err.Error(), "invalid amount format") {
+			return h.RespondWithHTTPError(c, errs.ErrInvalidFormat.WithMessage("Invalid amount format"))
+		}
+
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to place hold")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to place hold"))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// GetHold handles GET /holds/{hold_id}
+func (h *HoldHandler) GetHold(c echo.Context) error {
+	holdID, err := strconv.ParseInt(c.Param("hold_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidHoldID)
+	}
+
+	response, err := h.holdService.GetHold(c.Request().Context(), holdID)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("hold_id", holdID).Msg("failed to get hold")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to get hold"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// CaptureHold handles POST /holds/{hold_id}/capture
+func (h *HoldHandler) CaptureHold(c echo.Context) error {
+	holdID, err := strconv.ParseInt(c.Param("hold_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidHoldID)
+	}
+
+	var req model.CaptureHoldRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.HoldID = holdID
+	req.IdempotencyKey = c.Request().Header.Get(IdempotencyKeyHeader)
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.holdService.CaptureHold(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		if strings.Contains(err.Error(), "invalid amount format") {
+			return h.RespondWithHTTPError(c, errs.ErrInvalidFormat.WithMessage("Invalid amount format"))
+		}
+
+		h.Logger.Error().Err(err).Int64("hold_id", holdID).Msg("failed to capture hold")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to capture hold"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// VoidHold handles POST /holds/{hold_id}/void
+func (h *HoldHandler) VoidHold(c echo.Context) error {
+	holdID, err := strconv.ParseInt(c.Param("hold_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidHoldID)
+	}
+
+	response, err := h.holdService.VoidHold(c.Request().Context(), holdID)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("hold_id", holdID).Msg("failed to void hold")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to void hold"))
+	}
+
+	return h.RespondOK(c, response)
+}
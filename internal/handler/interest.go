@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// InterestHandler handles interest product and accrual HTTP requests
+type InterestHandler struct {
+	*BaseHandler
+	interestService *service.InterestService
+}
+
+// NewInterestHandler creates a new interest handler
+func NewInterestHandler(base *BaseHandler, interestService *service.InterestService) *InterestHandler {
+	return &InterestHandler{
+		BaseHandler:     base,
+		interestService: interestService,
+	}
+}
+
+// CreateProduct handles POST /admin/interest-products
+func (h *InterestHandler) CreateProduct(c echo.Context) error {
+	var req model.CreateInterestProductRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.interestService.CreateProduct(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to create interest product")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to create interest product"))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// ListProducts handles GET /admin/interest-products
+func (h *InterestHandler) ListProducts(c echo.Context) error {
+	response, err := h.interestService.ListProducts(c.Request().Context())
+	if err != nil {
+		h.Logger.Error().Err(err).Msg("failed to list interest products")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to list interest products"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// UpdateAccountProduct handles PUT /admin/accounts/{account_id}/interest
+func (h *InterestHandler) UpdateAccountProduct(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	var req model.UpdateAccountInterestRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.AccountID = accountID
+
+	expectedVersion, rejection := h.expectedVersion(c)
+	if rejection != nil {
+		return h.RespondWithHTTPError(c, rejection)
+	}
+	req.ExpectedVersion = expectedVersion
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.interestService.UpdateAccountProduct(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to update account interest product")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to update account interest product"))
+	}
+
+	h.setETag(c, response.Version)
+	return h.RespondOK(c, response)
+}
+
+// ListAccruals handles GET /accounts/{account_id}/interest-accruals
+func (h *InterestHandler) ListAccruals(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	var req model.ListInterestAccrualsRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.AccountID = accountID
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.interestService.ListAccruals(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to list interest accruals")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to list interest accruals"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// RunAccrual handles POST /admin/interest/accruals
+func (h *InterestHandler) RunAccrual(c echo.Context) error {
+	var req model.RunInterestAccrualRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.interestService.RunAccrual(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Str("business_date", req.BusinessDate).Msg("failed to accrue interest")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to accrue interest"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// RunCapitalization handles POST /admin/interest/capitalizations
+func (h *InterestHandler) RunCapitalization(c echo.Context) error {
+	var req model.RunInterestCapitalizationRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.interestService.RunCapitalization(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to capitalize interest")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to capitalize interest"))
+	}
+
+	return h.RespondOK(c, response)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LastEventIDHeader carries the ID of the last event a reconnecting Server-Sent Events client saw
+const LastEventIDHeader = "Last-Event-ID"
+
+// ErrInvalidLastEventID is returned when a Last-Event-ID is not an event ID this service issued
+var ErrInvalidLastEventID = errors.New("invalid last event id")
+
+// ParseLastEventID returns the event ID a client resumes from. An empty value starts a fresh
+// stream and returns nil.
+func ParseLastEventID(value string) (*int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return nil, ErrInvalidLastEventID
+	}
+
+	return &id, nil
+}
+
+// WriteEvent writes one Server-Sent Events message. Each line of data goes on its own data field,
+// which the client joins back with newlines.
+func WriteEvent(w io.Writer, id int64, event string, data []byte) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "id: %d\nevent: %s\n", id, event)
+	for _, line := range strings.Split(string(data), "\n") {
+		b.WriteString("data: ")
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteComment writes a comment line, which clients ignore but which keeps proxies from closing
+// an idle stream
+func WriteComment(w io.Writer, comment string) error {
+	_, err := io.WriteString(w, ": "+comment+"\n\n")
+	return err
+}
@@ -0,0 +1,53 @@
+package handler_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLastEventID(t *testing.T) {
+	id, err := handler.ParseLastEventID(" 42 ")
+	require.NoError(t, err)
+	require.NotNil(t, id)
+	assert.Equal(t, int64(42), *id)
+
+	id, err = handler.ParseLastEventID("0")
+	require.NoError(t, err)
+	require.NotNil(t, id)
+	assert.Equal(t, int64(0), *id)
+}
+
+func TestParseLastEventID_Fresh(t *testing.T) {
+	id, err := handler.ParseLastEventID("")
+	require.NoError(t, err)
+	assert.Nil(t, id)
+}
+
+func TestParseLastEventID_Invalid(t *testing.T) {
+	for _, value := range []string{"abc", "-1", "1.5", `"7"`} {
+		_, err := handler.ParseLastEventID(value)
+		assert.ErrorIs(t, err, handler.ErrInvalidLastEventID, value)
+	}
+}
+
+func TestWriteEvent(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, handler.WriteEvent(&b, 7, "posting", []byte(`{"entry_id":7}`)))
+	assert.Equal(t, "id: 7\nevent: posting\ndata: {\"entry_id\":7}\n\n", b.String())
+}
+
+func TestWriteEvent_MultilineData(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, handler.WriteEvent(&b, 1, "account", []byte("{\n}")))
+	assert.Equal(t, "id: 1\nevent: account\ndata: {\ndata: }\n\n", b.String())
+}
+
+func TestWriteComment(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, handler.WriteComment(&b, "heartbeat"))
+	assert.Equal(t, ": heartbeat\n\n", b.String())
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// StandingOrderHandler handles standing order HTTP requests
+type StandingOrderHandler struct {
+	*BaseHandler
+	standingOrderService *service.StandingOrderService
+}
+
+// NewStandingOrderHandler creates a new standing order handler
+func NewStandingOrderHandler(base *BaseHandler, standingOrderService *service.StandingOrderService) *StandingOrderHandler {
+	return &StandingOrderHandler{
+		BaseHandler:          base,
+		standingOrderService: standingOrderService,
+	}
+}
+
+// CreateStandingOrder handles POST /standing-orders
+func (h *StandingOrderHandler) CreateStandingOrder(c echo.Context) error {
+	var req model.CreateStandingOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.IdempotencyKey = c.Request().Header.Get(IdempotencyKeyHeader)
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.standingOrderService.CreateStandingOrder(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		if strings.Contains(err.Error(), "invalid amount format") {
+			return h.RespondWithHTTPError(c, errs.ErrInvalidFormat.WithMessage("Invalid amount format"))
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to create standing order")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to create standing order"))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// GetStandingOrder handles GET /standing-orders/{standing_order_id}
+func (h *StandingOrderHandler) GetStandingOrder(c echo.Context) error {
+	orderID, err := strconv.ParseInt(c.Param("standing_order_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidStandingOrderID)
+	}
+
+	response, err := h.standingOrderService.GetStandingOrder(c.Request().Context(), orderID)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("standing_order_id", orderID).Msg("failed to get standing order")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to get standing order"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// ListStandingOrders handles GET /accounts/{account_id}/standing-orders
+func (h *StandingOrderHandler) ListStandingOrders(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	var req model.ListStandingOrdersRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.AccountID = accountID
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.standingOrderService.ListStandingOrders(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to list standing orders")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to list standing orders"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// PauseStandingOrder handles POST /standing-orders/{standing_order_id}/pause
+func (h *StandingOrderHandler) PauseStandingOrder(c echo.Context) error {
+	return h.changeState(c, "pause", h.standingOrderService.PauseStandingOrder)
+}
+
+// ResumeStandingOrder handles POST /standing-orders/{standing_order_id}/resume
+func (h *StandingOrderHandler) ResumeStandingOrder(c echo.Context) error {
+	return h.changeState(c, "resume", h.standingOrderService.ResumeStandingOrder)
+}
+
+// CancelStandingOrder handles POST /standing-orders/{standing_order_id}/cancel
+func (h *StandingOrderHandler) CancelStandingOrder(c echo.Context) error {
+	return h.changeState(c, "cancel", h.standingOrderService.CancelStandingOrder)
+}
+
+// changeState runs one of the state transitions on the order named in the path
+func (h *StandingOrderHandler) changeState(c echo.Context, action string, change func(ctx context.Context, orderID int64) (*model.StandingOrderResponse, error)) error {
+	orderID, err := strconv.ParseInt(c.Param("standing_order_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidStandingOrderID)
+	}
+
+	response, err := change(c.Request().Context(), orderID)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("standing_order_id", orderID).Str("action", action).Msg("failed to change standing order state")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to "+action+" standing order"))
+	}
+
+	return h.RespondOK(c, response)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// StatementHandler handles account statement downloads
+type StatementHandler struct {
+	*BaseHandler
+	statementService *service.StatementService
+}
+
+// NewStatementHandler creates a new statement handler
+func NewStatementHandler(base *BaseHandler, statementService *service.StatementService) *StatementHandler {
+	return &StatementHandler{
+		BaseHandler:      base,
+		statementService: statementService,
+	}
+}
+
+// GetStatement handles GET /accounts/{account_id}/statements. The statement is streamed, so
+// an error after the first bytes are sent can only cut the download short.
+func (h *StatementHandler) GetStatement(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	var req model.GetStatementRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.AccountID = accountID
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	ctx := c.Request().Context()
+
+	statement, err := h.statementService.OpenStatement(ctx, &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to open statement")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to get statement"))
+	}
+	defer statement.Close(ctx)
+
+	res := c.Response()
+
+	// The server write timeout is meant for ordinary responses and would cut a long statement off
+	if err := http.NewResponseController(res).SetWriteDeadline(time.Time{}); err != nil {
+		h.Logger.Warn().Err(err).Msg("failed to clear write deadline of statement")
+	}
+
+	res.Header().Set(echo.HeaderContentType, statement.Format.ContentType())
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+statement.Statement.FileName(statement.Format)+`"`)
+	res.Header().Set(echo.HeaderCacheControl, "no-store")
+	res.WriteHeader(http.StatusOK)
+
+	if err := statement.WriteTo(ctx, res, res.Flush); err != nil && ctx.Err() == nil {
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to write statement")
+	}
+
+	// The response has started, so there is nothing left to send
+	return nil
+}
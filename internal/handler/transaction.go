@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/chandra-shekhar/internal-transfers/internal/errs"
@@ -30,12 +31,22 @@ func (h *TransactionHandler) CreateTransaction(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return h.HandleBindError(c, err)
 	}
+	req.IdempotencyKey = c.Request().Header.Get(IdempotencyKeyHeader)
+
+	// The body binder ignores the query string of a POST
+	switch c.QueryParam("mode") {
+	case "", "sync":
+	case "async":
+		req.Async = true
+	default:
+		return h.RespondWithHTTPError(c, errs.ErrValidationError.WithMessage("mode must be one of: sync async"))
+	}
 
 	if err := c.Validate(req); err != nil {
 		return h.HandleValidationError(c, err)
 	}
 
-	_, err := h.transactionService.CreateTransaction(c.Request().Context(), &req)
+	response, err := h.transactionService.CreateTransaction(c.Request().Context(), &req)
 	if err != nil {
 		// Check for HTTPError first
 		if httpErr, ok := errs.IsHTTPError(err); ok {
@@ -51,6 +62,190 @@ func (h *TransactionHandler) CreateTransaction(c echo.Context) error {
 		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to process transaction"))
 	}
 
+	// Scheduled and asynchronous transfers return the record so the caller can track it
+	if response.Status == model.TransactionStatusScheduled || response.Status == model.TransactionStatusPending {
+		return c.JSON(http.StatusAccepted, response)
+	}
+
 	// Return empty response on success as per requirement
 	return c.NoContent(http.StatusCreated)
 }
+
+// GetTransaction handles GET /transactions/{transaction_id}
+func (h *TransactionHandler) GetTransaction(c echo.Context) error {
+	transactionID, err := strconv.ParseInt(c.Param("transaction_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidTransactionID)
+	}
+
+	response, err := h.transactionService.GetTransaction(c.Request().Context(), transactionID)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("transaction_id", transactionID).Msg("failed to get transaction")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to get transaction"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// SearchTransactions handles GET /transactions
+func (h *TransactionHandler) SearchTransactions(c echo.Context) error {
+	var req model.SearchTransactionsRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.Metadata = model.ParseMetadataQuery(c.QueryParams())
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.transactionService.SearchTransactions(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to search transactions")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to search transactions"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// ListScheduledTransactions handles GET /transactions/scheduled
+func (h *TransactionHandler) ListScheduledTransactions(c echo.Context) error {
+	var req model.ListScheduledTransactionsRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.transactionService.ListScheduledTransactions(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to list scheduled transactions")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to list scheduled transactions"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// CancelScheduledTransaction handles POST /transactions/{transaction_id}/cancel
+func (h *TransactionHandler) CancelScheduledTransaction(c echo.Context) error {
+	transactionID, err := strconv.ParseInt(c.Param("transaction_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidTransactionID)
+	}
+
+	response, err := h.transactionService.CancelScheduledTransaction(c.Request().Context(), transactionID)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("transaction_id", transactionID).Msg("failed to cancel scheduled transaction")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to cancel scheduled transaction"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// CreateBatchTransaction handles POST /transactions/batch
+func (h *TransactionHandler) CreateBatchTransaction(c echo.Context) error {
+	var req model.CreateBatchTransactionRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.IdempotencyKey = c.Request().Header.Get(IdempotencyKeyHeader)
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.transactionService.CreateBatchTransaction(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int("legs", len(req.Legs)).Msg("failed to create batch transaction")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to process batch transaction"))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// CreateReversal handles POST /transactions/{transaction_id}/reversals
+func (h *TransactionHandler) CreateReversal(c echo.Context) error {
+	transactionID, err := strconv.ParseInt(c.Param("transaction_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidTransactionID)
+	}
+
+	var req model.CreateReversalRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.TransactionID = transactionID
+	req.IdempotencyKey = c.Request().Header.Get(IdempotencyKeyHeader)
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.transactionService.ReverseTransaction(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		if strings.Contains(err.Error(), "invalid amount format") {
+			return h.RespondWithHTTPError(c, errs.ErrInvalidFormat.WithMessage("Invalid amount format"))
+		}
+
+		h.Logger.Error().Err(err).Int64("transaction_id", transactionID).Msg("failed to reverse transaction")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to reverse transaction"))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// ListAccountTransactions handles GET /accounts/{account_id}/transactions
+func (h *TransactionHandler) ListAccountTransactions(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	var req model.ListAccountTransactionsRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.AccountID = accountID
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.transactionService.ListAccountTransactions(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to list account transactions")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to list account transactions"))
+	}
+
+	return h.RespondOK(c, response)
+}
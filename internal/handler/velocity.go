@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// VelocityHandler handles velocity limit HTTP requests
+type VelocityHandler struct {
+	*BaseHandler
+	velocityService *service.VelocityService
+}
+
+// NewVelocityHandler creates a new velocity limit handler
+func NewVelocityHandler(base *BaseHandler, velocityService *service.VelocityService) *VelocityHandler {
+	return &VelocityHandler{
+		BaseHandler:     base,
+		velocityService: velocityService,
+	}
+}
+
+// CreateLimit handles POST /admin/velocity-limits
+func (h *VelocityHandler) CreateLimit(c echo.Context) error {
+	var req model.CreateVelocityLimitRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.velocityService.CreateLimit(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		if strings.Contains(err.Error(), "invalid amount format") {
+			return h.RespondWithHTTPError(c, errs.ErrInvalidFormat.WithMessage("Invalid max_value format"))
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to create velocity limit")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to create velocity limit"))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// ListLimits handles GET /admin/velocity-limits
+func (h *VelocityHandler) ListLimits(c echo.Context) error {
+	var req model.ListVelocityLimitsRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.velocityService.ListLimits(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to list velocity limits")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to list velocity limits"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// DeleteLimit handles DELETE /admin/velocity-limits/{limit_id}
+func (h *VelocityHandler) DeleteLimit(c echo.Context) error {
+	limitID, err := strconv.ParseInt(c.Param("limit_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidVelocityLimitID)
+	}
+
+	if err := h.velocityService.DeleteLimit(c.Request().Context(), limitID); err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("velocity_limit_id", limitID).Msg("failed to delete velocity limit")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to delete velocity limit"))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// UpdateAccountGroup handles PUT /admin/accounts/{account_id}/group
+func (h *VelocityHandler) UpdateAccountGroup(c echo.Context) error {
+	accountID, err := strconv.ParseInt(c.Param("account_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidAccountID)
+	}
+
+	var req model.UpdateAccountGroupRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.AccountID = accountID
+
+	expectedVersion, rejection := h.expectedVersion(c)
+	if rejection != nil {
+		return h.RespondWithHTTPError(c, rejection)
+	}
+	req.ExpectedVersion = expectedVersion
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.velocityService.UpdateAccountGroup(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to update account group")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to update account group"))
+	}
+
+	h.setETag(c, response.Version)
+	return h.RespondOK(c, response)
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// WebhookHandler handles webhook subscription HTTP requests
+type WebhookHandler struct {
+	*BaseHandler
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(base *BaseHandler, webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		BaseHandler:    base,
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook handles POST /webhooks
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	var req model.CreateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.webhookService.CreateWebhook(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to create webhook")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to create webhook"))
+	}
+
+	return c.JSON(http.StatusCreated, response)
+}
+
+// ListWebhooks handles GET /webhooks
+func (h *WebhookHandler) ListWebhooks(c echo.Context) error {
+	response, err := h.webhookService.ListWebhooks(c.Request().Context())
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Msg("failed to list webhooks")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to list webhooks"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// GetWebhook handles GET /webhooks/{webhook_id}
+func (h *WebhookHandler) GetWebhook(c echo.Context) error {
+	webhookID, err := strconv.ParseInt(c.Param("webhook_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidWebhookID)
+	}
+
+	response, err := h.webhookService.GetWebhook(c.Request().Context(), webhookID)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("webhook_id", webhookID).Msg("failed to get webhook")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to get webhook"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// UpdateWebhook handles PATCH /webhooks/{webhook_id}
+func (h *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	webhookID, err := strconv.ParseInt(c.Param("webhook_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidWebhookID)
+	}
+
+	var req model.UpdateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.ID = webhookID
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.webhookService.UpdateWebhook(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("webhook_id", webhookID).Msg("failed to update webhook")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to update webhook"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// DeleteWebhook handles DELETE /webhooks/{webhook_id}
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	webhookID, err := strconv.ParseInt(c.Param("webhook_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidWebhookID)
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request().Context(), webhookID); err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("webhook_id", webhookID).Msg("failed to delete webhook")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to delete webhook"))
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RotateSecret handles POST /webhooks/{webhook_id}/rotate-secret
+func (h *WebhookHandler) RotateSecret(c echo.Context) error {
+	webhookID, err := strconv.ParseInt(c.Param("webhook_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidWebhookID)
+	}
+
+	var req model.RotateWebhookSecretRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.ID = webhookID
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.webhookService.RotateSecret(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("webhook_id", webhookID).Msg("failed to rotate webhook secret")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to rotate webhook secret"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// ListDeadLetters handles GET /webhooks/{webhook_id}/dead-letters
+func (h *WebhookHandler) ListDeadLetters(c echo.Context) error {
+	webhookID, err := strconv.ParseInt(c.Param("webhook_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidWebhookID)
+	}
+
+	var req model.ListWebhookDeadLettersRequest
+	if err := c.Bind(&req); err != nil {
+		return h.HandleBindError(c, err)
+	}
+	req.WebhookID = webhookID
+
+	if err := c.Validate(req); err != nil {
+		return h.HandleValidationError(c, err)
+	}
+
+	response, err := h.webhookService.ListDeadLetters(c.Request().Context(), &req)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("webhook_id", webhookID).Msg("failed to list webhook dead letters")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to list webhook dead letters"))
+	}
+
+	return h.RespondOK(c, response)
+}
+
+// ReplayDeadLetter handles POST /webhooks/{webhook_id}/dead-letters/{dead_letter_id}/replay
+func (h *WebhookHandler) ReplayDeadLetter(c echo.Context) error {
+	webhookID, err := strconv.ParseInt(c.Param("webhook_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidWebhookID)
+	}
+
+	deadLetterID, err := strconv.ParseInt(c.Param("dead_letter_id"), 10, 64)
+	if err != nil {
+		return h.RespondWithHTTPError(c, errs.ErrInvalidDeadLetterID)
+	}
+
+	response, err := h.webhookService.ReplayDeadLetter(c.Request().Context(), webhookID, deadLetterID)
+	if err != nil {
+		if httpErr, ok := errs.IsHTTPError(err); ok {
+			return h.RespondWithHTTPError(c, httpErr)
+		}
+
+		h.Logger.Error().Err(err).Int64("webhook_id", webhookID).Int64("dead_letter_id", deadLetterID).Msg("failed to replay webhook dead letter")
+		return h.RespondWithHTTPError(c, errs.ErrInternalError.WithMessage("Failed to replay webhook dead letter"))
+	}
+
+	return c.JSON(http.StatusAccepted, response)
+}
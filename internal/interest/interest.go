@@ -0,0 +1,83 @@
+// Package interest accrues interest on balances under the ACT/365 and 30/360 day-count
+// conventions
+package interest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccrualScale is the number of decimals daily accruals are kept at. They are only rounded to
+// the precision of the currency when they are capitalized.
+const AccrualScale = 10
+
+// DayCount is the convention that turns a period into a fraction of a year
+type DayCount string
+
+const (
+	// Actual365 counts calendar days over a fixed 365 day year
+	Actual365 DayCount = "act_365"
+	// Thirty360 counts every month as 30 days over a 360 day year (bond basis)
+	Thirty360 DayCount = "30_360"
+)
+
+// ParseDayCount returns the convention with the given name
+func ParseDayCount(name string) (DayCount, error) {
+	switch DayCount(name) {
+	case Actual365, Thirty360:
+		return DayCount(name), nil
+	default:
+		return "", fmt.Errorf("unknown day count convention %q", name)
+	}
+}
+
+// Days is the number of days from one date to another under the convention
+func (c DayCount) Days(from, to time.Time) int64 {
+	if c == Thirty360 {
+		y1, m1, d1 := from.Date()
+		y2, m2, d2 := to.Date()
+		if d1 == 31 {
+			d1 = 30
+		}
+		if d2 == 31 && d1 == 30 {
+			d2 = 30
+		}
+		return int64(360*(y2-y1) + 30*(int(m2)-int(m1)) + (d2 - d1))
+	}
+
+	return int64(Date(to).Sub(Date(from)).Hours() / 24)
+}
+
+// Basis is the number of days in a year under the convention
+func (c DayCount) Basis() int64 {
+	if c == Thirty360 {
+		return 360
+	}
+	return 365
+}
+
+// Daily is the interest balance earns at annualRate over the business day date, that is from
+// date to the next day. Under 30/360 the last day of a month may earn nothing, or the last day
+// of February several days, so every month earns exactly 30 days.
+func Daily(balance, annualRate decimal.Decimal, convention DayCount, date time.Time) decimal.Decimal {
+	days := convention.Days(date, date.AddDate(0, 0, 1))
+
+	return balance.Mul(annualRate).
+		Mul(decimal.NewFromInt(days)).
+		Div(decimal.NewFromInt(convention.Basis())).
+		Round(AccrualScale)
+}
+
+// Date truncates t to midnight UTC, the start of its business date
+func Date(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// MonthStart is midnight UTC on the first day of the month of t
+func MonthStart(t time.Time) time.Time {
+	year, month, _ := t.UTC().Date()
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
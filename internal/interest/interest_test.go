@@ -0,0 +1,74 @@
+package interest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/interest"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(value string) time.Time {
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+func TestDays_Actual365(t *testing.T) {
+	assert.Equal(t, int64(1), interest.Actual365.Days(date("2026-02-28"), date("2026-03-01")))
+	assert.Equal(t, int64(29), interest.Actual365.Days(date("2028-02-01"), date("2028-03-01")))
+	assert.Equal(t, int64(365), interest.Actual365.Basis())
+}
+
+func TestDays_Thirty360(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     int64
+	}{
+		{"2026-01-15", "2026-01-16", 1},
+		{"2026-01-30", "2026-01-31", 0},
+		{"2026-01-31", "2026-02-01", 1},
+		{"2026-02-28", "2026-03-01", 3},
+		{"2026-01-01", "2027-01-01", 360},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"_"+tt.to, func(t *testing.T) {
+			assert.Equal(t, tt.want, interest.Thirty360.Days(date(tt.from), date(tt.to)))
+		})
+	}
+}
+
+func TestDaily_MonthSumsToThirtyDaysUnderThirty360(t *testing.T) {
+	balance := decimal.NewFromInt(36000)
+	rate := decimal.RequireFromString("0.05")
+
+	for _, month := range []string{"2026-01-01", "2026-02-01", "2028-02-01", "2026-04-01"} {
+		start := date(month)
+		total := decimal.Zero
+		for day := start; day.Month() == start.Month(); day = day.AddDate(0, 0, 1) {
+			total = total.Add(interest.Daily(balance, rate, interest.Thirty360, day))
+		}
+
+		assert.Equal(t, "150", total.String(), month)
+	}
+}
+
+func TestDaily_Actual365(t *testing.T) {
+	accrued := interest.Daily(decimal.NewFromInt(1000), decimal.RequireFromString("0.0365"), interest.Actual365, date("2026-03-10"))
+
+	assert.Equal(t, "0.1", accrued.String())
+}
+
+func TestParseDayCount(t *testing.T) {
+	convention, err := interest.ParseDayCount("30_360")
+	require.NoError(t, err)
+	assert.Equal(t, interest.Thirty360, convention)
+
+	_, err = interest.ParseDayCount("act_360")
+	assert.Error(t, err)
+}
@@ -6,22 +6,141 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// AccountStatus controls which money movements an account accepts
+type AccountStatus string
+
+const (
+	AccountStatusActive AccountStatus = "active"
+	// AccountStatusFrozenDebit accepts credits but no debits
+	AccountStatusFrozenDebit AccountStatus = "frozen_debit"
+	// AccountStatusFrozenAll accepts neither debits nor credits
+	AccountStatusFrozenAll AccountStatus = "frozen_all"
+	// AccountStatusClosed is final
+	AccountStatusClosed AccountStatus = "closed"
+)
+
 // Account represents a bank account
 type Account struct {
-	ID        int64           `json:"account_id" db:"id"`
-	Balance   decimal.Decimal `json:"balance" db:"balance"`
-	CreatedAt time.Time       `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+	ID int64 `json:"account_id" db:"id"`
+	// Currency is the ISO 4217 code every amount on the account is in
+	Currency string `json:"currency" db:"currency"`
+	// Balance is the ledger balance, the money actually on the account
+	Balance decimal.Decimal `json:"balance" db:"balance"`
+	// HeldAmount is reserved by active holds and cannot be spent
+	HeldAmount decimal.Decimal `json:"held_amount" db:"held_amount"`
+	// OverdraftLimit is how far below zero the balance may go
+	OverdraftLimit decimal.Decimal `json:"overdraft_limit" db:"overdraft_limit"`
+	// MaxBalance caps the balance credits may reach; nil leaves only the currency's maximum
+	MaxBalance *decimal.Decimal `json:"max_balance" db:"max_balance"`
+	Status     AccountStatus    `json:"status" db:"status"`
+	// StatusReason is why the account was last frozen, unfrozen or closed
+	StatusReason *string    `json:"status_reason" db:"status_reason"`
+	ClosedAt     *time.Time `json:"closed_at" db:"closed_at"`
+	// AccountGroup puts the account under the velocity limits of its group
+	AccountGroup *string `json:"account_group" db:"account_group"`
+	// InterestProductID is the interest product the account earns interest under
+	InterestProductID *int64 `json:"interest_product_id" db:"interest_product_id"`
+	// Version is bumped by every update of the account, balance postings included
+	Version   int64     `json:"version" db:"version"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AcceptsDebits reports whether money may leave the account
+func (a *Account) AcceptsDebits() bool {
+	return a.Status == AccountStatusActive
+}
+
+// AcceptsCredits reports whether money may arrive on the account
+func (a *Account) AcceptsCredits() bool {
+	return a.Status == AccountStatusActive || a.Status == AccountStatusFrozenDebit
+}
+
+// AvailableBalance is the part of the balance that is not held
+func (a *Account) AvailableBalance() decimal.Decimal {
+	return a.Balance.Sub(a.HeldAmount)
+}
+
+// SpendableBalance is what a debit may take: the available balance plus the overdraft limit
+func (a *Account) SpendableBalance() decimal.Decimal {
+	return a.AvailableBalance().Add(a.OverdraftLimit)
 }
 
 // CreateAccountRequest represents the request to create a new account
 type CreateAccountRequest struct {
 	AccountID      int64  `json:"account_id" validate:"required,min=1"`
 	InitialBalance string `json:"initial_balance" validate:"required,numeric"`
+	// Currency is an ISO 4217 code and defaults to the configured currency
+	Currency string `json:"currency" validate:"omitempty,len=3"`
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-" validate:"max=255"`
 }
 
 // AccountResponse represents the response for account queries
 type AccountResponse struct {
 	AccountID int64  `json:"account_id"`
-	Balance   string `json:"balance"`
+	Currency  string `json:"currency"`
+	// Balance equals LedgerBalance and is kept for existing clients
+	Balance          string `json:"balance"`
+	LedgerBalance    string `json:"ledger_balance"`
+	AvailableBalance string `json:"available_balance"`
+	OverdraftLimit   string `json:"overdraft_limit"`
+	// MaxBalance is omitted when the account has no maximum of its own
+	MaxBalance   *string       `json:"max_balance,omitempty"`
+	Status       AccountStatus `json:"status"`
+	StatusReason *string       `json:"status_reason,omitempty"`
+	ClosedAt     *time.Time    `json:"closed_at,omitempty"`
+	AccountGroup *string       `json:"account_group,omitempty"`
+	// InterestProductID is omitted when the account earns no interest
+	InterestProductID *int64 `json:"interest_product_id,omitempty"`
+	// Version is also sent as the ETag of the account
+	Version int64 `json:"version"`
+}
+
+// FreezeAccountRequest blocks debits, or all movements, on an account
+type FreezeAccountRequest struct {
+	// AccountID is taken from the path
+	AccountID int64 `json:"-"`
+	// Scope is "debit" to block debits only and "all" to block credits as well
+	Scope  string `json:"scope" validate:"required,oneof=debit all"`
+	Reason string `json:"reason" validate:"max=1000"`
+	// ExpectedVersion is taken from the If-Match header; nil applies the change to any version
+	ExpectedVersion *int64 `json:"-"`
+}
+
+// UnfreezeAccountRequest makes a frozen account active again
+type UnfreezeAccountRequest struct {
+	// AccountID is taken from the path
+	AccountID int64  `json:"-"`
+	Reason    string `json:"reason" validate:"max=1000"`
+	// ExpectedVersion is taken from the If-Match header; nil applies the change to any version
+	ExpectedVersion *int64 `json:"-"`
+}
+
+// CloseAccountRequest closes an account. A positive balance is swept to SweepAccountID.
+type CloseAccountRequest struct {
+	// AccountID is taken from the path
+	AccountID      int64  `json:"-"`
+	SweepAccountID *int64 `json:"sweep_account_id,omitempty" validate:"omitempty,min=1"`
+	Reason         string `json:"reason" validate:"max=1000"`
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-" validate:"max=255"`
+	// ExpectedVersion is taken from the If-Match header; nil applies the change to any version
+	ExpectedVersion *int64 `json:"-"`
+}
+
+// IdempotencyPayload is what a retry with the same Idempotency-Key must repeat: the body, the
+// account from the path and the version from If-Match
+func (r *CloseAccountRequest) IdempotencyPayload() interface{} {
+	return struct {
+		AccountID       int64  `json:"account_id"`
+		ExpectedVersion *int64 `json:"expected_version"`
+		*CloseAccountRequest
+	}{r.AccountID, r.ExpectedVersion, r}
+}
+
+// CloseAccountResponse is the closed account and the transfer that swept its balance, if any
+type CloseAccountResponse struct {
+	*AccountResponse
+	SweepTransaction *TransactionResponse `json:"sweep_transaction,omitempty"`
 }
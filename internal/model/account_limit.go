@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccountLimitChange is the audit record of one change to an account's balance limits
+type AccountLimitChange struct {
+	ID                     int64            `json:"id" db:"id"`
+	AccountID              int64            `json:"account_id" db:"account_id"`
+	PreviousOverdraftLimit decimal.Decimal  `json:"previous_overdraft_limit" db:"previous_overdraft_limit"`
+	OverdraftLimit         decimal.Decimal  `json:"overdraft_limit" db:"overdraft_limit"`
+	PreviousMaxBalance     *decimal.Decimal `json:"previous_max_balance" db:"previous_max_balance"`
+	MaxBalance             *decimal.Decimal `json:"max_balance" db:"max_balance"`
+	ChangedBy              string           `json:"changed_by" db:"changed_by"`
+	Reason                 *string          `json:"reason" db:"reason"`
+	CreatedAt              time.Time        `json:"created_at" db:"created_at"`
+}
+
+// UpdateAccountLimitsRequest replaces the balance limits of an account
+type UpdateAccountLimitsRequest struct {
+	// AccountID is taken from the path
+	AccountID      int64  `json:"-"`
+	OverdraftLimit string `json:"overdraft_limit" validate:"required,numeric"`
+	// MaxBalance is optional; leaving it out removes the account's maximum
+	MaxBalance *string `json:"max_balance" validate:"omitempty,numeric"`
+	// ChangedBy identifies who made the change for the audit trail
+	ChangedBy string `json:"changed_by" validate:"required,max=255"`
+	Reason    string `json:"reason" validate:"max=1000"`
+	// ExpectedVersion is taken from the If-Match header; nil applies the change to any version
+	ExpectedVersion *int64 `json:"-"`
+}
+
+// AccountLimitChangeResponse represents a limit change in API responses
+type AccountLimitChangeResponse struct {
+	ID                     int64     `json:"id"`
+	AccountID              int64     `json:"account_id"`
+	PreviousOverdraftLimit string    `json:"previous_overdraft_limit"`
+	OverdraftLimit         string    `json:"overdraft_limit"`
+	PreviousMaxBalance     *string   `json:"previous_max_balance,omitempty"`
+	MaxBalance             *string   `json:"max_balance,omitempty"`
+	ChangedBy              string    `json:"changed_by"`
+	Reason                 *string   `json:"reason,omitempty"`
+	CreatedAt              time.Time `json:"created_at"`
+}
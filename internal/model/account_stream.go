@@ -0,0 +1,35 @@
+package model
+
+// AccountStreamEventType names an event pushed on an account stream
+type AccountStreamEventType string
+
+const (
+	// AccountStreamEventAccount is a snapshot of the account, sent when a stream starts fresh
+	AccountStreamEventAccount AccountStreamEventType = "account"
+	// AccountStreamEventPosting is a committed posting that changed the account balance
+	AccountStreamEventPosting AccountStreamEventType = "posting"
+	// AccountStreamEventHeartbeat keeps an idle stream open and carries no data
+	AccountStreamEventHeartbeat AccountStreamEventType = "heartbeat"
+)
+
+// AccountStreamEvent is one event of an account stream. ID is the last ledger entry of the
+// account the event reflects, so a client that resumes from it misses nothing.
+type AccountStreamEvent struct {
+	ID   int64
+	Type AccountStreamEventType
+	Data interface{}
+}
+
+// AccountPosting is a transaction as it was posted to one account's ledger
+type AccountPosting struct {
+	AccountTransaction
+	EntryID int64 `json:"entry_id" db:"entry_id"`
+}
+
+// AccountPostingResponse is the data of a posting event
+type AccountPostingResponse struct {
+	EntryID     int64                      `json:"entry_id"`
+	AccountID   int64                      `json:"account_id"`
+	Balance     string                     `json:"balance"`
+	Transaction AccountTransactionResponse `json:"transaction"`
+}
@@ -0,0 +1,74 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccount_AvailableBalanceExcludesHolds(t *testing.T) {
+	account := &model.Account{
+		Balance:    decimal.RequireFromString("100.50000"),
+		HeldAmount: decimal.RequireFromString("40.25000"),
+	}
+
+	assert.True(t, account.AvailableBalance().Equal(decimal.RequireFromString("60.25")))
+	assert.True(t, account.Balance.Equal(decimal.RequireFromString("100.5")))
+}
+
+func TestAccount_SpendableBalanceIncludesOverdraft(t *testing.T) {
+	account := &model.Account{
+		Balance:        decimal.RequireFromString("-20.00000"),
+		HeldAmount:     decimal.RequireFromString("5.00000"),
+		OverdraftLimit: decimal.RequireFromString("100.00000"),
+	}
+
+	assert.True(t, account.AvailableBalance().Equal(decimal.RequireFromString("-25")))
+	assert.True(t, account.SpendableBalance().Equal(decimal.RequireFromString("75")))
+}
+
+func TestAccount_StatusControlsMovements(t *testing.T) {
+	tests := []struct {
+		status         model.AccountStatus
+		acceptsDebits  bool
+		acceptsCredits bool
+	}{
+		{model.AccountStatusActive, true, true},
+		{model.AccountStatusFrozenDebit, false, true},
+		{model.AccountStatusFrozenAll, false, false},
+		{model.AccountStatusClosed, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			account := &model.Account{Status: tt.status}
+
+			assert.Equal(t, tt.acceptsDebits, account.AcceptsDebits())
+			assert.Equal(t, tt.acceptsCredits, account.AcceptsCredits())
+		})
+	}
+}
+
+func TestCloseAccountRequest_IdempotencyPayload(t *testing.T) {
+	sweep, version, staleVersion := int64(9), int64(4), int64(3)
+	first := &model.CloseAccountRequest{AccountID: 1, SweepAccountID: &sweep, Reason: "customer request", IdempotencyKey: "close-1", ExpectedVersion: &version}
+
+	firstPayload, err := json.Marshal(first.IdempotencyPayload())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"account_id":1,"expected_version":4,"sweep_account_id":9,"reason":"customer request"}`, string(firstPayload))
+
+	otherAccount := *first
+	otherAccount.AccountID = 2
+	otherVersion := *first
+	otherVersion.ExpectedVersion = &staleVersion
+
+	for _, other := range []*model.CloseAccountRequest{&otherAccount, &otherVersion} {
+		otherPayload, err := json.Marshal(other.IdempotencyPayload())
+		require.NoError(t, err)
+		assert.NotEqual(t, string(firstPayload), string(otherPayload))
+	}
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// GetAccountBalanceRequest asks for the balance an account held at a point in time
+type GetAccountBalanceRequest struct {
+	// AccountID is taken from the path
+	AccountID int64 `json:"-"`
+	// AsOf is an RFC 3339 timestamp, now when left out
+	AsOf string `query:"as_of"`
+}
+
+// AccountBalanceResponse is the balance of an account at a point in time
+type AccountBalanceResponse struct {
+	AccountID int64     `json:"account_id"`
+	Currency  string    `json:"currency"`
+	Balance   string    `json:"balance"`
+	AsOf      time.Time `json:"as_of"`
+}
@@ -31,3 +31,16 @@ type PaginatedResponse[T interface{}] struct {
 	Total      int `json:"total"`
 	TotalPages int `json:"totalPages"`
 }
+
+// CursorPaginatedResponse is a page of results addressed by an opaque cursor
+type CursorPaginatedResponse[T interface{}] struct {
+	Data       []T    `json:"data"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+}
+
+// ListResponse is a complete, unpaginated list of results
+type ListResponse[T interface{}] struct {
+	Data []T `json:"data"`
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// KeysetCursor is the position of the last row of a page ordered by a timestamp and id,
+// such as (created_at, id)
+type KeysetCursor struct {
+	Timestamp time.Time
+	ID        int64
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c KeysetCursor) Encode() string {
+	raw := strconv.FormatInt(c.Timestamp.UnixNano(), 10) + ":" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeKeysetCursor parses a cursor produced by Encode
+func DecodeKeysetCursor(s string) (*KeysetCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &KeysetCursor{
+		Timestamp: time.Unix(0, nanos).UTC(),
+		ID:        id,
+	}, nil
+}
+
+// FeedCursor is a position in the event feed. Position 0 is before the first event.
+type FeedCursor int64
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c FeedCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte("feed:" + strconv.FormatInt(int64(c), 10)))
+}
+
+// DecodeFeedCursor parses a cursor produced by FeedCursor.Encode
+func DecodeFeedCursor(s string) (FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	position, ok := strings.CutPrefix(string(raw), "feed:")
+	if !ok {
+		return 0, ErrInvalidCursor
+	}
+
+	value, err := strconv.ParseInt(position, 10, 64)
+	if err != nil || value < 0 {
+		return 0, ErrInvalidCursor
+	}
+
+	return FeedCursor(value), nil
+}
@@ -0,0 +1,45 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysetCursor_RoundTrip(t *testing.T) {
+	cursor := model.KeysetCursor{
+		Timestamp: time.Date(2026, 9, 30, 23, 59, 59, 123456000, time.UTC),
+		ID:        42,
+	}
+
+	decoded, err := model.DecodeKeysetCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.True(t, cursor.Timestamp.Equal(decoded.Timestamp))
+	assert.Equal(t, cursor.ID, decoded.ID)
+}
+
+func TestDecodeKeysetCursor_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "not base64!", "MTIz", "YTpi"} {
+		_, err := model.DecodeKeysetCursor(raw)
+		assert.ErrorIs(t, err, model.ErrInvalidCursor, raw)
+	}
+}
+
+func TestFeedCursor_RoundTrip(t *testing.T) {
+	for _, position := range []model.FeedCursor{0, 1, 9007199254740993} {
+		decoded, err := model.DecodeFeedCursor(position.Encode())
+		require.NoError(t, err)
+		assert.Equal(t, position, decoded)
+	}
+}
+
+func TestDecodeFeedCursor_Invalid(t *testing.T) {
+	keyset := model.KeysetCursor{Timestamp: time.Now(), ID: 1}.Encode()
+	for _, raw := range []string{"", "not base64!", "MTIz", keyset, "ZmVlZDotMQ", "ZmVlZDph"} {
+		_, err := model.DecodeFeedCursor(raw)
+		assert.ErrorIs(t, err, model.ErrInvalidCursor, raw)
+	}
+}
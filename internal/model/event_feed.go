@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/event"
+)
+
+// FeedEvent is a published event at its position in the event feed. Positions are assigned in
+// the order events are published, so a reader never sees a later position before an earlier one.
+type FeedEvent struct {
+	Position int64 `json:"position" db:"position"`
+	event.Event
+}
+
+// ConsumerGroup is a named reader of the event feed and the position it has committed
+type ConsumerGroup struct {
+	Name      string    `json:"name" db:"name"`
+	Position  int64     `json:"position" db:"position"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ListEventsRequest reads the event feed after a cursor, or after the offset a consumer group
+// committed. Wait holds the request open until an event arrives or the wait runs out.
+type ListEventsRequest struct {
+	After string `query:"after"`
+	Group string `query:"group" validate:"omitempty,max=100"`
+	Limit int    `query:"limit" validate:"omitempty,min=1,max=1000"`
+	// Wait is the longest time in seconds to wait for an event when there is none yet
+	Wait int `query:"wait" validate:"omitempty,min=0,max=30"`
+}
+
+// CommitConsumerGroupOffsetRequest records how far a consumer group has processed the feed
+type CommitConsumerGroupOffsetRequest struct {
+	// Name is taken from the path
+	Name   string `json:"-" validate:"required,max=100"`
+	Cursor string `json:"cursor" validate:"required"`
+}
+
+// FeedEventResponse is an event in the feed. Committing its cursor marks it and every event
+// before it as processed.
+type FeedEventResponse struct {
+	Cursor string `json:"cursor"`
+	event.Event
+}
+
+// EventFeedResponse is a page of the event feed. NextCursor is set even when the page is empty,
+// so a consumer can always ask for what follows.
+type EventFeedResponse struct {
+	Data       []FeedEventResponse `json:"data"`
+	Limit      int                 `json:"limit"`
+	NextCursor string              `json:"nextCursor"`
+	HasMore    bool                `json:"hasMore"`
+}
+
+// ConsumerGroupResponse represents a consumer group in API responses
+type ConsumerGroupResponse struct {
+	Name      string    `json:"name"`
+	Cursor    string    `json:"cursor"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/fee"
+	"github.com/shopspring/decimal"
+)
+
+// FeeRuleKind is how a fee rule prices a transfer
+type FeeRuleKind string
+
+const (
+	FeeRuleFlat       FeeRuleKind = "flat"
+	FeeRulePercentage FeeRuleKind = "percentage"
+	FeeRuleTiered     FeeRuleKind = "tiered"
+	// FeeRuleWaiver exempts transfers between one pair of accounts from every other rule
+	FeeRuleWaiver FeeRuleKind = "waiver"
+)
+
+// FeePayer is the side of a transfer a fee is taken from
+type FeePayer string
+
+const (
+	FeePayerSender   FeePayer = "sender"
+	FeePayerReceiver FeePayer = "receiver"
+)
+
+// FeeRule prices the transfers in one currency, optionally only those from a source account,
+// to a destination account, or between a pair of them
+type FeeRule struct {
+	ID                   int64            `json:"id" db:"id"`
+	Name                 string           `json:"name" db:"name"`
+	Kind                 FeeRuleKind      `json:"kind" db:"kind"`
+	Currency             string           `json:"currency" db:"currency"`
+	FlatAmount           *decimal.Decimal `json:"flat_amount" db:"flat_amount"`
+	Rate                 *decimal.Decimal `json:"rate" db:"rate"`
+	MinFee               *decimal.Decimal `json:"min_fee" db:"min_fee"`
+	MaxFee               *decimal.Decimal `json:"max_fee" db:"max_fee"`
+	Tiers                []fee.Tier       `json:"tiers" db:"tiers"`
+	SourceAccountID      *int64           `json:"source_account_id" db:"source_account_id"`
+	DestinationAccountID *int64           `json:"destination_account_id" db:"destination_account_id"`
+	Payer                FeePayer         `json:"payer" db:"payer"`
+	RevenueAccountID     *int64           `json:"revenue_account_id" db:"revenue_account_id"`
+	// Priority picks between rules that match the same transfer, the lowest wins
+	Priority  int       `json:"priority" db:"priority"`
+	Active    bool      `json:"active" db:"active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Schedule is the pricing of a rule that charges a fee
+func (r *FeeRule) Schedule() fee.Schedule {
+	schedule := fee.Schedule{
+		Kind:  fee.Kind(r.Kind),
+		Min:   r.MinFee,
+		Max:   r.MaxFee,
+		Tiers: r.Tiers,
+	}
+	if r.FlatAmount != nil {
+		schedule.Flat = *r.FlatAmount
+	}
+	if r.Rate != nil {
+		schedule.Rate = *r.Rate
+	}
+
+	return schedule
+}
+
+// TransactionFee is the fee assessed on a transfer. A waived transfer records the waiver
+// that exempted it and no fee transaction.
+type TransactionFee struct {
+	RuleID           int64           `json:"rule_id"`
+	Amount           decimal.Decimal `json:"amount"`
+	Payer            FeePayer        `json:"payer"`
+	Waived           bool            `json:"waived"`
+	RevenueAccountID *int64          `json:"revenue_account_id,omitempty"`
+	// TransactionID is the child transaction that moved the fee to the revenue account
+	TransactionID *int64 `json:"transaction_id,omitempty"`
+}
+
+// FeeTier is one band of a tiered fee rule
+type FeeTier struct {
+	// UpTo is left out on the last tier to cover every larger amount
+	UpTo *string `json:"up_to,omitempty" validate:"omitempty,numeric"`
+	Flat string  `json:"flat" validate:"omitempty,numeric"`
+	Rate string  `json:"rate" validate:"omitempty,numeric"`
+}
+
+// CreateFeeRuleRequest represents the request to add a fee rule. The fields used depend on
+// the kind: flat_amount for flat, rate with optional min_fee and max_fee for percentage,
+// tiers for tiered, and both accounts for a waiver.
+type CreateFeeRuleRequest struct {
+	Name                 string      `json:"name" validate:"required,max=100"`
+	Kind                 FeeRuleKind `json:"kind" validate:"required,oneof=flat percentage tiered waiver"`
+	Currency             string      `json:"currency" validate:"required,len=3,uppercase"`
+	FlatAmount           *string     `json:"flat_amount,omitempty" validate:"omitempty,numeric"`
+	Rate                 *string     `json:"rate,omitempty" validate:"omitempty,numeric"`
+	MinFee               *string     `json:"min_fee,omitempty" validate:"omitempty,numeric"`
+	MaxFee               *string     `json:"max_fee,omitempty" validate:"omitempty,numeric"`
+	Tiers                []FeeTier   `json:"tiers,omitempty" validate:"omitempty,max=50,dive"`
+	SourceAccountID      *int64      `json:"source_account_id,omitempty" validate:"omitempty,min=1"`
+	DestinationAccountID *int64      `json:"destination_account_id,omitempty" validate:"omitempty,min=1"`
+	// Payer defaults to the sender
+	Payer            FeePayer `json:"payer,omitempty" validate:"omitempty,oneof=sender receiver"`
+	RevenueAccountID *int64   `json:"revenue_account_id,omitempty" validate:"omitempty,min=1"`
+	Priority         int      `json:"priority"`
+}
+
+// ListFeeRulesRequest filters the fee rules listing
+type ListFeeRulesRequest struct {
+	Currency string `query:"currency" validate:"omitempty,len=3"`
+	// IncludeInactive also lists the rules that were deleted
+	IncludeInactive bool `query:"include_inactive"`
+}
+
+// FeeRuleResponse represents a fee rule in API responses
+type FeeRuleResponse struct {
+	ID                   int64       `json:"id"`
+	Name                 string      `json:"name"`
+	Kind                 FeeRuleKind `json:"kind"`
+	Currency             string      `json:"currency"`
+	FlatAmount           *string     `json:"flat_amount,omitempty"`
+	Rate                 *string     `json:"rate,omitempty"`
+	MinFee               *string     `json:"min_fee,omitempty"`
+	MaxFee               *string     `json:"max_fee,omitempty"`
+	Tiers                []FeeTier   `json:"tiers,omitempty"`
+	SourceAccountID      *int64      `json:"source_account_id,omitempty"`
+	DestinationAccountID *int64      `json:"destination_account_id,omitempty"`
+	Payer                FeePayer    `json:"payer"`
+	RevenueAccountID     *int64      `json:"revenue_account_id,omitempty"`
+	Priority             int         `json:"priority"`
+	Active               bool        `json:"active"`
+	CreatedAt            time.Time   `json:"created_at"`
+}
+
+// TransactionFeeResponse represents the fee of a transfer in API responses
+type TransactionFeeResponse struct {
+	Amount           string   `json:"amount"`
+	Currency         string   `json:"currency"`
+	PaidBy           FeePayer `json:"paid_by"`
+	Waived           bool     `json:"waived"`
+	RuleID           int64    `json:"rule_id"`
+	RevenueAccountID *int64   `json:"revenue_account_id,omitempty"`
+	TransactionID    *int64   `json:"transaction_id,omitempty"`
+}
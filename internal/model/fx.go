@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FXConversion is the exchange locked into a cross-currency transaction when it settles
+type FXConversion struct {
+	DestinationAmount   decimal.Decimal `json:"destination_amount" db:"destination_amount"`
+	DestinationCurrency string          `json:"destination_currency" db:"destination_currency"`
+	Rate                decimal.Decimal `json:"rate" db:"fx_rate"`
+	Spread              decimal.Decimal `json:"spread" db:"fx_spread"`
+	RateTimestamp       time.Time       `json:"rate_timestamp" db:"fx_rate_timestamp"`
+	// Remainder is what rounding the destination amount down left over. It accrues to
+	// PnLAccountID, which is credited whenever its remainders add up to a whole minor unit.
+	Remainder    decimal.Decimal `json:"remainder" db:"fx_remainder"`
+	PnLAccountID int64           `json:"pnl_account_id" db:"fx_pnl_account_id"`
+}
+
+// EffectiveRate is the rate the client got once the spread was taken off
+func (c *FXConversion) EffectiveRate() decimal.Decimal {
+	return c.Rate.Mul(decimal.NewFromInt(1).Sub(c.Spread))
+}
+
+// FXQuote is a rate offered for converting an amount, valid until it expires or is used
+type FXQuote struct {
+	ID                  int64           `json:"id" db:"id"`
+	SourceCurrency      string          `json:"source_currency" db:"source_currency"`
+	DestinationCurrency string          `json:"destination_currency" db:"destination_currency"`
+	Amount              decimal.Decimal `json:"amount" db:"amount"`
+	ConvertedAmount     decimal.Decimal `json:"converted_amount" db:"converted_amount"`
+	Rate                decimal.Decimal `json:"rate" db:"rate"`
+	Spread              decimal.Decimal `json:"spread" db:"spread"`
+	RateTimestamp       time.Time       `json:"rate_timestamp" db:"rate_timestamp"`
+	ExpiresAt           time.Time       `json:"expires_at" db:"expires_at"`
+	TransactionID       *int64          `json:"transaction_id,omitempty" db:"transaction_id"`
+	CreatedAt           time.Time       `json:"created_at" db:"created_at"`
+}
+
+// CreateFXQuoteRequest represents the request to quote a transfer between accounts of different currencies
+type CreateFXQuoteRequest struct {
+	SourceAccountID      int64  `json:"source_account_id" validate:"required,min=1"`
+	DestinationAccountID int64  `json:"destination_account_id" validate:"required,min=1,nefield=SourceAccountID"`
+	Amount               string `json:"amount" validate:"required,numeric"`
+}
+
+// FXQuoteResponse represents the response for quote operations
+type FXQuoteResponse struct {
+	ID                  int64     `json:"id"`
+	SourceCurrency      string    `json:"source_currency"`
+	DestinationCurrency string    `json:"destination_currency"`
+	Amount              string    `json:"amount"`
+	ConvertedAmount     string    `json:"converted_amount"`
+	Rate                string    `json:"rate"`
+	Spread              string    `json:"spread"`
+	EffectiveRate       string    `json:"effective_rate"`
+	RateTimestamp       time.Time `json:"rate_timestamp"`
+	ExpiresAt           time.Time `json:"expires_at"`
+	TransactionID       *int64    `json:"transaction_id,omitempty"`
+}
+
+// FXConversionResponse represents the conversion of a cross-currency transaction
+type FXConversionResponse struct {
+	QuoteID             *int64    `json:"quote_id,omitempty"`
+	DestinationAmount   string    `json:"destination_amount"`
+	DestinationCurrency string    `json:"destination_currency"`
+	Rate                string    `json:"rate"`
+	Spread              string    `json:"spread"`
+	EffectiveRate       string    `json:"effective_rate"`
+	RateTimestamp       time.Time `json:"rate_timestamp"`
+	Remainder           string    `json:"remainder"`
+}
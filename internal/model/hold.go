@@ -0,0 +1,87 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// HoldStatus represents the status of a hold
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusVoided   HoldStatus = "voided"
+	HoldStatusExpired  HoldStatus = "expired"
+)
+
+// Hold reserves funds on an account without moving them
+type Hold struct {
+	ID                   int64           `json:"id" db:"id"`
+	AccountID            int64           `json:"account_id" db:"account_id"`
+	Amount               decimal.Decimal `json:"amount" db:"amount"`
+	Currency             string          `json:"currency" db:"currency"`
+	CapturedAmount       decimal.Decimal `json:"captured_amount" db:"captured_amount"`
+	Status               HoldStatus      `json:"status" db:"status"`
+	Reference            *string         `json:"reference,omitempty" db:"reference"`
+	CaptureTransactionID *int64          `json:"capture_transaction_id,omitempty" db:"capture_transaction_id"`
+	ExpiresAt            time.Time       `json:"expires_at" db:"expires_at"`
+	ReleasedAt           *time.Time      `json:"released_at,omitempty" db:"released_at"`
+	CreatedAt            time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// CreateHoldRequest represents the request to reserve funds on an account
+type CreateHoldRequest struct {
+	AccountID int64  `json:"-"`
+	Amount    string `json:"amount" validate:"required,numeric"`
+	Reference string `json:"reference" validate:"max=255"`
+	// ExpiresAt defaults to the configured hold lifetime
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-" validate:"max=255"`
+}
+
+// IdempotencyPayload is what a retry with the same Idempotency-Key must repeat: the body and
+// the account from the path
+func (r *CreateHoldRequest) IdempotencyPayload() interface{} {
+	return struct {
+		AccountID int64 `json:"account_id"`
+		*CreateHoldRequest
+	}{r.AccountID, r}
+}
+
+// CaptureHoldRequest represents the request to turn a hold into a transfer
+type CaptureHoldRequest struct {
+	HoldID               int64 `json:"-"`
+	DestinationAccountID int64 `json:"destination_account_id" validate:"required,min=1"`
+	// Amount defaults to the whole hold. Whatever is not captured is released.
+	Amount string `json:"amount" validate:"omitempty,numeric"`
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-" validate:"max=255"`
+}
+
+// IdempotencyPayload is what a retry with the same Idempotency-Key must repeat: the body and
+// the hold from the path
+func (r *CaptureHoldRequest) IdempotencyPayload() interface{} {
+	return struct {
+		HoldID int64 `json:"hold_id"`
+		*CaptureHoldRequest
+	}{r.HoldID, r}
+}
+
+// HoldResponse represents the response for hold operations
+type HoldResponse struct {
+	ID                   int64      `json:"id"`
+	AccountID            int64      `json:"account_id"`
+	Amount               string     `json:"amount"`
+	Currency             string     `json:"currency"`
+	CapturedAmount       string     `json:"captured_amount"`
+	Status               HoldStatus `json:"status"`
+	Reference            *string    `json:"reference,omitempty"`
+	CaptureTransactionID *int64     `json:"capture_transaction_id,omitempty"`
+	ExpiresAt            time.Time  `json:"expires_at"`
+	ReleasedAt           *time.Time `json:"released_at,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+}
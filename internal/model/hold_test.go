@@ -0,0 +1,38 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHoldRequest_IdempotencyPayload(t *testing.T) {
+	first := &model.CreateHoldRequest{AccountID: 1, Amount: "25.00", Reference: "order-7", IdempotencyKey: "hold-1"}
+	second := *first
+	second.AccountID = 2
+
+	firstPayload, err := json.Marshal(first.IdempotencyPayload())
+	require.NoError(t, err)
+	secondPayload, err := json.Marshal(second.IdempotencyPayload())
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"account_id":1,"amount":"25.00","reference":"order-7"}`, string(firstPayload))
+	assert.NotEqual(t, string(firstPayload), string(secondPayload))
+}
+
+func TestCaptureHoldRequest_IdempotencyPayload(t *testing.T) {
+	first := &model.CaptureHoldRequest{HoldID: 1, DestinationAccountID: 9, Amount: "10.00", IdempotencyKey: "capture-1"}
+	second := *first
+	second.HoldID = 2
+
+	firstPayload, err := json.Marshal(first.IdempotencyPayload())
+	require.NoError(t, err)
+	secondPayload, err := json.Marshal(second.IdempotencyPayload())
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"hold_id":1,"destination_account_id":9,"amount":"10.00"}`, string(firstPayload))
+	assert.NotEqual(t, string(firstPayload), string(secondPayload))
+}
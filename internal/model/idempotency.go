@@ -0,0 +1,17 @@
+package model
+
+import (
+	"time"
+)
+
+// IdempotencyRecord represents the stored outcome of a request sent with an Idempotency-Key
+type IdempotencyRecord struct {
+	Scope        string    `json:"scope" db:"scope"`
+	Key          string    `json:"idempotency_key" db:"idempotency_key"`
+	RequestHash  string    `json:"request_hash" db:"request_hash"`
+	StatusCode   *int      `json:"status_code,omitempty" db:"status_code"`
+	ResponseBody []byte    `json:"response_body,omitempty" db:"response_body"`
+	ErrorCode    *string   `json:"error_code,omitempty" db:"error_code"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at" db:"expires_at"`
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/interest"
+	"github.com/shopspring/decimal"
+)
+
+// InterestProduct pays interest at an annual rate on the positive balances of the accounts
+// assigned to it
+type InterestProduct struct {
+	ID         int64             `json:"id" db:"id"`
+	Name       string            `json:"name" db:"name"`
+	Currency   string            `json:"currency" db:"currency"`
+	AnnualRate decimal.Decimal   `json:"annual_rate" db:"annual_rate"`
+	DayCount   interest.DayCount `json:"day_count" db:"day_count"`
+	// ExpenseAccountID is the account interest is paid out of
+	ExpenseAccountID int64     `json:"expense_account_id" db:"expense_account_id"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+// InterestAccrual is the interest an account earned on one business date. It stays unposted
+// until capitalization pays it into the account with an interest transaction.
+type InterestAccrual struct {
+	ID           int64     `json:"id" db:"id"`
+	AccountID    int64     `json:"account_id" db:"account_id"`
+	ProductID    int64     `json:"product_id" db:"product_id"`
+	BusinessDate time.Time `json:"business_date" db:"business_date"`
+	// Balance is the end of day balance the interest was accrued on
+	Balance    decimal.Decimal   `json:"balance" db:"balance"`
+	AnnualRate decimal.Decimal   `json:"annual_rate" db:"annual_rate"`
+	DayCount   interest.DayCount `json:"day_count" db:"day_count"`
+	// Amount is kept at interest.AccrualScale decimals
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	TransactionID *int64          `json:"transaction_id" db:"transaction_id"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
+
+// InterestCandidate is an account to accrue interest for, with its balance at the end of a
+// business date
+type InterestCandidate struct {
+	AccountID int64
+	Product   InterestProduct
+	Balance   decimal.Decimal
+}
+
+// CreateInterestProductRequest represents the request to add an interest product
+type CreateInterestProductRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Currency string `json:"currency" validate:"required,len=3,uppercase"`
+	// AnnualRate is a fraction, 0.025 for 2.5% a year
+	AnnualRate       string `json:"annual_rate" validate:"required,numeric"`
+	DayCount         string `json:"day_count" validate:"required,oneof=act_365 30_360"`
+	ExpenseAccountID int64  `json:"expense_account_id" validate:"required,min=1"`
+}
+
+// UpdateAccountInterestRequest assigns an account to an interest product
+type UpdateAccountInterestRequest struct {
+	// AccountID is taken from the path
+	AccountID int64 `json:"-"`
+	// ProductID is left out to stop the account from earning interest
+	ProductID *int64 `json:"product_id" validate:"omitempty,min=1"`
+	// ExpectedVersion is taken from the If-Match header; nil applies the change to any version
+	ExpectedVersion *int64 `json:"-"`
+}
+
+// RunInterestAccrualRequest accrues interest for one business date
+type RunInterestAccrualRequest struct {
+	BusinessDate string `json:"business_date" validate:"required,datetime=2006-01-02"`
+}
+
+// RunInterestCapitalizationRequest pays out the accruals of business dates before a date
+type RunInterestCapitalizationRequest struct {
+	// Before defaults to the first day of the current month
+	Before string `json:"before" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// ListInterestAccrualsRequest represents the query of an account's accruals
+type ListInterestAccrualsRequest struct {
+	AccountID int64  `json:"-"`
+	From      string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To        string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// InterestProductResponse represents an interest product in API responses
+type InterestProductResponse struct {
+	ID               int64             `json:"id"`
+	Name             string            `json:"name"`
+	Currency         string            `json:"currency"`
+	AnnualRate       string            `json:"annual_rate"`
+	DayCount         interest.DayCount `json:"day_count"`
+	ExpenseAccountID int64             `json:"expense_account_id"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
+
+// InterestAccrualResponse represents an accrual in API responses
+type InterestAccrualResponse struct {
+	BusinessDate  string            `json:"business_date"`
+	ProductID     int64             `json:"product_id"`
+	Balance       string            `json:"balance"`
+	AnnualRate    string            `json:"annual_rate"`
+	DayCount      interest.DayCount `json:"day_count"`
+	Amount        string            `json:"amount"`
+	TransactionID *int64            `json:"transaction_id,omitempty"`
+}
+
+// InterestAccrualListResponse lists accruals together with what is still to be capitalized
+type InterestAccrualListResponse struct {
+	Data []*InterestAccrualResponse `json:"data"`
+	// Unposted is the accrued interest of the account that has not been capitalized yet,
+	// including what earlier capitalizations carried below a whole minor unit
+	Unposted string `json:"unposted"`
+}
+
+// InterestAccrualRunResponse reports an accrual run
+type InterestAccrualRunResponse struct {
+	BusinessDate string `json:"business_date"`
+	// Accrued counts the new accruals; accounts accrued by an earlier run are skipped
+	Accrued int `json:"accrued"`
+	Skipped int `json:"skipped"`
+}
+
+// InterestCapitalizationRunResponse reports a capitalization run
+type InterestCapitalizationRunResponse struct {
+	Before       string  `json:"before"`
+	Transactions []int64 `json:"transactions"`
+	// Failed counts the accounts whose interest could not be paid; their accruals stay unposted
+	Failed int `json:"failed"`
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// LedgerDirection is the side of a posting. Debits reduce a balance, credits increase it.
+type LedgerDirection string
+
+const (
+	LedgerDirectionDebit  LedgerDirection = "debit"
+	LedgerDirectionCredit LedgerDirection = "credit"
+)
+
+// LedgerEntryType describes why a posting was made
+type LedgerEntryType string
+
+const (
+	LedgerEntryTypeOpening  LedgerEntryType = "opening"
+	LedgerEntryTypeTransfer LedgerEntryType = "transfer"
+	// LedgerEntryTypeFX is a leg of a cross-currency transfer, which does not net to zero
+	LedgerEntryTypeFX LedgerEntryType = "fx"
+	// LedgerEntryTypeFee is a leg of the fee charged for a transfer
+	LedgerEntryTypeFee LedgerEntryType = "fee"
+	// LedgerEntryTypeInterest is a leg of capitalized interest
+	LedgerEntryTypeInterest LedgerEntryType = "interest"
+	// LedgerEntryTypeReconciliation records drift found by reconciliation without moving the balance
+	LedgerEntryTypeReconciliation LedgerEntryType = "reconciliation"
+)
+
+// LedgerEntry represents a single posting against an account balance
+type LedgerEntry struct {
+	ID            int64           `json:"id" db:"id"`
+	AccountID     int64           `json:"account_id" db:"account_id"`
+	TransactionID *int64          `json:"transaction_id,omitempty" db:"transaction_id"`
+	Direction     LedgerDirection `json:"direction" db:"direction"`
+	EntryType     LedgerEntryType `json:"entry_type" db:"entry_type"`
+	Amount        decimal.Decimal `json:"amount" db:"amount"`
+	BalanceAfter  decimal.Decimal `json:"balance_after" db:"balance_after"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
+
+// SignedAmount returns the amount as a change to the account balance
+func (e *LedgerEntry) SignedAmount() decimal.Decimal {
+	if e.Direction == LedgerDirectionDebit {
+		return e.Amount.Neg()
+	}
+	return e.Amount
+}
+
+// LedgerChannel is the Postgres channel postings are announced on. Notifications are sent
+// inside the posting transaction, so they are only delivered once it commits.
+const LedgerChannel = "ledger_postings"
+
+// LedgerNotification is the payload of a notification on LedgerChannel
+type LedgerNotification struct {
+	AccountID int64 `json:"account_id"`
+	EntryID   int64 `json:"entry_id"`
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReconciliationCheck identifies the invariant a reconciliation issue violates
+type ReconciliationCheck string
+
+const (
+	ReconciliationCheckConservation     ReconciliationCheck = "balance_conservation"
+	ReconciliationCheckNegativeBalance  ReconciliationCheck = "negative_balance"
+	ReconciliationCheckMissingCompleted ReconciliationCheck = "completed_at_missing"
+	ReconciliationCheckLedgerDrift      ReconciliationCheck = "ledger_drift"
+)
+
+// ReconciliationIssue describes a single invariant violation found by a reconciliation run
+type ReconciliationIssue struct {
+	Check         ReconciliationCheck `json:"check"`
+	Currency      string              `json:"currency,omitempty"`
+	AccountID     *int64              `json:"account_id,omitempty"`
+	TransactionID *int64              `json:"transaction_id,omitempty"`
+	Expected      string              `json:"expected,omitempty"`
+	Actual        string              `json:"actual,omitempty"`
+	Difference    string              `json:"difference,omitempty"`
+	Fixed         bool                `json:"fixed"`
+}
+
+// ReconciliationReport is the result of a reconciliation run
+type ReconciliationReport struct {
+	StartedAt       time.Time             `json:"started_at"`
+	FinishedAt      time.Time             `json:"finished_at"`
+	AccountsChecked int64                 `json:"accounts_checked"`
+	Totals          []ReconciliationTotal `json:"totals"`
+	LedgerChecked   bool                  `json:"ledger_checked"`
+	FixRequested    bool                  `json:"fix_requested"`
+	Issues          []ReconciliationIssue `json:"issues"`
+}
+
+// ReconciliationTotal is the sum of the balances in one currency and the money funded into it.
+// Amounts in different currencies are never added together.
+type ReconciliationTotal struct {
+	Currency     string `json:"currency"`
+	TotalBalance string `json:"total_balance"`
+	TotalFunded  string `json:"total_funded"`
+}
+
+// Clean reports whether every invariant holds, counting fixed issues as resolved
+func (r *ReconciliationReport) Clean() bool {
+	for _, issue := range r.Issues {
+		if !issue.Fixed {
+			return false
+		}
+	}
+	return true
+}
+
+// LedgerDrift is an account whose balance differs from the sum of its postings
+type LedgerDrift struct {
+	AccountID     int64           `json:"account_id" db:"account_id"`
+	Balance       decimal.Decimal `json:"balance" db:"balance"`
+	LedgerBalance decimal.Decimal `json:"ledger_balance" db:"ledger_balance"`
+}
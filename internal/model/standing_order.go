@@ -0,0 +1,108 @@
+package model
+
+import (
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/schedule"
+	"github.com/shopspring/decimal"
+)
+
+// StandingOrderStatus represents the status of a standing order
+type StandingOrderStatus string
+
+const (
+	StandingOrderStatusActive    StandingOrderStatus = "active"
+	StandingOrderStatusPaused    StandingOrderStatus = "paused"
+	StandingOrderStatusSuspended StandingOrderStatus = "suspended"
+	StandingOrderStatusCompleted StandingOrderStatus = "completed"
+	StandingOrderStatusCancelled StandingOrderStatus = "cancelled"
+)
+
+// InsufficientFundsPolicy decides what happens to an occurrence the source cannot pay for
+type InsufficientFundsPolicy string
+
+const (
+	// InsufficientFundsSkip drops the occurrence and waits for the next one
+	InsufficientFundsSkip InsufficientFundsPolicy = "skip"
+	// InsufficientFundsRetry tries the occurrence again up to MaxRetries times, then skips it
+	InsufficientFundsRetry InsufficientFundsPolicy = "retry"
+	// InsufficientFundsSuspend stops the order until it is resumed
+	InsufficientFundsSuspend InsufficientFundsPolicy = "suspend"
+)
+
+// StandingOrder is a recurring transfer between two accounts
+type StandingOrder struct {
+	ID                      int64                   `json:"id" db:"id"`
+	SourceAccountID         int64                   `json:"source_account_id" db:"source_account_id"`
+	DestinationAccountID    int64                   `json:"destination_account_id" db:"destination_account_id"`
+	Amount                  decimal.Decimal         `json:"amount" db:"amount"`
+	Currency                string                  `json:"currency" db:"currency"`
+	Frequency               schedule.Frequency      `json:"frequency" db:"frequency"`
+	ScheduleDay             *int                    `json:"schedule_day,omitempty" db:"schedule_day"`
+	CronExpression          *string                 `json:"cron_expression,omitempty" db:"cron_expression"`
+	StartAt                 time.Time               `json:"start_at" db:"start_at"`
+	EndAt                   *time.Time              `json:"end_at,omitempty" db:"end_at"`
+	MaxOccurrences          *int                    `json:"max_occurrences,omitempty" db:"max_occurrences"`
+	Occurrences             int                     `json:"occurrences" db:"occurrences"`
+	InsufficientFundsPolicy InsufficientFundsPolicy `json:"insufficient_funds_policy" db:"insufficient_funds_policy"`
+	MaxRetries              int                     `json:"max_retries" db:"max_retries"`
+	RetryCount              int                     `json:"retry_count" db:"retry_count"`
+	Status                  StandingOrderStatus     `json:"status" db:"status"`
+	NextRunAt               *time.Time              `json:"next_run_at,omitempty" db:"next_run_at"`
+	LastRunAt               *time.Time              `json:"last_run_at,omitempty" db:"last_run_at"`
+	LastFailureCode         *string                 `json:"last_failure_code,omitempty" db:"last_failure_code"`
+	LastFailureReason       *string                 `json:"last_failure_reason,omitempty" db:"last_failure_reason"`
+	CreatedAt               time.Time               `json:"created_at" db:"created_at"`
+	UpdatedAt               time.Time               `json:"updated_at" db:"updated_at"`
+}
+
+// CreateStandingOrderRequest represents the request to create a standing order
+type CreateStandingOrderRequest struct {
+	SourceAccountID      int64  `json:"source_account_id" validate:"required,min=1"`
+	DestinationAccountID int64  `json:"destination_account_id" validate:"required,min=1,nefield=SourceAccountID"`
+	Amount               string `json:"amount" validate:"required,numeric"`
+	Frequency            string `json:"frequency" validate:"required,oneof=daily weekly monthly cron"`
+	// Day is the weekday (0 = Sunday) of weekly orders or the day of the month of monthly orders
+	Day  *int   `json:"day,omitempty"`
+	Cron string `json:"cron,omitempty" validate:"max=255"`
+	// StartAt defaults to now. Daily, weekly and monthly orders run at its time of day.
+	StartAt             *time.Time `json:"start_at,omitempty"`
+	EndAt               *time.Time `json:"end_at,omitempty"`
+	MaxOccurrences      *int       `json:"max_occurrences,omitempty" validate:"omitempty,min=1"`
+	OnInsufficientFunds string     `json:"on_insufficient_funds" validate:"omitempty,oneof=skip retry suspend"`
+	MaxRetries          int        `json:"max_retries" validate:"min=0,max=100"`
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-" validate:"max=255"`
+}
+
+// ListStandingOrdersRequest represents the query of an account's standing orders
+type ListStandingOrdersRequest struct {
+	AccountID int64  `json:"-"`
+	Cursor    string `query:"cursor"`
+	Limit     int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// StandingOrderResponse represents the response for standing order queries
+type StandingOrderResponse struct {
+	ID                   int64                   `json:"id"`
+	SourceAccountID      int64                   `json:"source_account_id"`
+	DestinationAccountID int64                   `json:"destination_account_id"`
+	Amount               string                  `json:"amount"`
+	Currency             string                  `json:"currency"`
+	Frequency            schedule.Frequency      `json:"frequency"`
+	Day                  *int                    `json:"day,omitempty"`
+	Cron                 *string                 `json:"cron,omitempty"`
+	StartAt              time.Time               `json:"start_at"`
+	EndAt                *time.Time              `json:"end_at,omitempty"`
+	MaxOccurrences       *int                    `json:"max_occurrences,omitempty"`
+	Occurrences          int                     `json:"occurrences"`
+	OnInsufficientFunds  InsufficientFundsPolicy `json:"on_insufficient_funds"`
+	MaxRetries           int                     `json:"max_retries"`
+	RetryCount           int                     `json:"retry_count"`
+	Status               StandingOrderStatus     `json:"status"`
+	NextRunAt            *time.Time              `json:"next_run_at,omitempty"`
+	LastRunAt            *time.Time              `json:"last_run_at,omitempty"`
+	LastFailureCode      *string                 `json:"last_failure_code,omitempty"`
+	LastFailureReason    *string                 `json:"last_failure_reason,omitempty"`
+	CreatedAt            time.Time               `json:"created_at"`
+}
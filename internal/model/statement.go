@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// GetStatementRequest asks for the statement of an account over a period
+type GetStatementRequest struct {
+	// AccountID is taken from the path
+	AccountID int64 `json:"-"`
+	// From and To are RFC 3339 timestamps or dates. A date starts at midnight UTC, and a date
+	// in To includes the whole day.
+	From   string `query:"from" validate:"required"`
+	To     string `query:"to" validate:"required"`
+	Format string `query:"format" validate:"omitempty,oneof=csv ofx camt053 beancount"`
+}
+
+// StatementEntry is a ledger entry with the details of the transaction it posted, which are
+// nil for entries without one
+type StatementEntry struct {
+	LedgerEntry
+	SourceAccountID      *int64  `db:"source_account_id"`
+	DestinationAccountID *int64  `db:"destination_account_id"`
+	Reference            *string `db:"reference"`
+	Description          *string `db:"description"`
+}
+
+// CounterpartyAccountID returns the other account of the transaction, or nil when the entry
+// has no transaction or posted to an account that is neither side of it
+func (e *StatementEntry) CounterpartyAccountID() *int64 {
+	if e.SourceAccountID == nil || e.DestinationAccountID == nil {
+		return nil
+	}
+	switch e.AccountID {
+	case *e.SourceAccountID:
+		return e.DestinationAccountID
+	case *e.DestinationAccountID:
+		return e.SourceAccountID
+	}
+	return nil
+}
+
+// StatementEntryFilter selects a page of an account's ledger entries in posting order
+type StatementEntryFilter struct {
+	AccountID int64
+	From      time.Time
+	To        time.Time
+	After     *KeysetCursor
+	Limit     int
+}
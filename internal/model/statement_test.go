@@ -0,0 +1,35 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementEntry_CounterpartyAccountID(t *testing.T) {
+	source, destination := int64(1), int64(2)
+
+	tests := []struct {
+		name      string
+		accountID int64
+		source    *int64
+		expected  *int64
+	}{
+		{"source side", 1, &source, &destination},
+		{"destination side", 2, &source, &source},
+		{"neither side", 3, &source, nil},
+		{"no transaction", 1, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &model.StatementEntry{LedgerEntry: model.LedgerEntry{AccountID: tt.accountID}, SourceAccountID: tt.source}
+			if tt.source != nil {
+				entry.DestinationAccountID = &destination
+			}
+
+			assert.Equal(t, tt.expected, entry.CounterpartyAccountID())
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package model
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -13,6 +17,26 @@ const (
 	TransactionStatusPending   TransactionStatus = "pending"
 	TransactionStatusCompleted TransactionStatus = "completed"
 	TransactionStatusFailed    TransactionStatus = "failed"
+	// Future-dated transfers wait as scheduled until the scheduler runs or they are cancelled
+	TransactionStatusScheduled TransactionStatus = "scheduled"
+	TransactionStatusCancelled TransactionStatus = "cancelled"
+	// A completed transfer moves to these once money has been sent back by reversals
+	TransactionStatusReversed          TransactionStatus = "reversed"
+	TransactionStatusPartiallyReversed TransactionStatus = "partially_reversed"
+)
+
+// TransactionKind describes why a transaction was made
+type TransactionKind string
+
+const (
+	TransactionKindTransfer TransactionKind = "transfer"
+	TransactionKindReversal TransactionKind = "reversal"
+	// TransactionKindSweep moves the balance of an account that is being closed
+	TransactionKindSweep TransactionKind = "sweep"
+	// TransactionKindFee moves the fee of a transfer to a revenue account, linked to the transfer as its parent
+	TransactionKindFee TransactionKind = "fee"
+	// TransactionKindInterest pays capitalized interest from an interest expense account
+	TransactionKindInterest TransactionKind = "interest"
 )
 
 // Transaction represents a money transfer between accounts
@@ -21,9 +45,34 @@ type Transaction struct {
 	SourceAccountID      int64             `json:"source_account_id" db:"source_account_id"`
 	DestinationAccountID int64             `json:"destination_account_id" db:"destination_account_id"`
 	Amount               decimal.Decimal   `json:"amount" db:"amount"`
+	Currency             string            `json:"currency" db:"currency"`
 	Status               TransactionStatus `json:"status" db:"status"`
+	Kind                 TransactionKind   `json:"kind" db:"kind"`
+	ParentTransactionID  *int64            `json:"parent_transaction_id,omitempty" db:"parent_transaction_id"`
+	ReversedAmount       decimal.Decimal   `json:"reversed_amount" db:"reversed_amount"`
+	Reason               *string           `json:"reason,omitempty" db:"reason"`
+	ExecuteAt            *time.Time        `json:"execute_at,omitempty" db:"execute_at"`
+	FailureCode          *string           `json:"failure_code,omitempty" db:"failure_code"`
+	FailureReason        *string           `json:"failure_reason,omitempty" db:"failure_reason"`
+	StandingOrderID      *int64            `json:"standing_order_id,omitempty" db:"standing_order_id"`
 	CreatedAt            time.Time         `json:"created_at" db:"created_at"`
 	CompletedAt          *time.Time        `json:"completed_at,omitempty" db:"completed_at"`
+	// Reference, Description, ExternalID and Metadata are supplied by the caller and stored as given
+	Reference   *string                `json:"reference,omitempty" db:"reference"`
+	Description *string                `json:"description,omitempty" db:"description"`
+	ExternalID  *string                `json:"external_id,omitempty" db:"external_id"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty" db:"metadata"`
+	// FXRequested allows the transfer to convert between currencies, at FXQuoteID's rate when set
+	FXRequested bool   `json:"fx_requested" db:"fx_requested"`
+	FXQuoteID   *int64 `json:"fx_quote_id,omitempty" db:"fx_quote_id"`
+	// FX is set once a cross-currency transfer settles
+	FX *FXConversion `json:"fx,omitempty"`
+	// Fee is set once a transfer that a fee rule matched settles
+	Fee *TransactionFee `json:"fee,omitempty"`
+	// WaiveFees settles the transfer without assessing fee rules, as for hold captures
+	WaiveFees bool `json:"-"`
+	// ExpectedSourceVersion rejects the transfer if the source account changed since the caller read it
+	ExpectedSourceVersion *int64 `json:"-" db:"expected_source_version"`
 }
 
 // CreateTransactionRequest represents the request to create a new transaction
@@ -31,14 +80,231 @@ type CreateTransactionRequest struct {
 	SourceAccountID      int64  `json:"source_account_id" validate:"required,min=1"`
 	DestinationAccountID int64  `json:"destination_account_id" validate:"required,min=1,nefield=SourceAccountID"`
 	Amount               string `json:"amount" validate:"required,numeric"`
+	// ExecuteAt schedules the transfer for later. A time that is not in the future executes immediately.
+	ExecuteAt *time.Time `json:"execute_at,omitempty"`
+	// Convert allows a transfer between accounts of different currencies at the current rate
+	Convert bool `json:"convert"`
+	// FXQuoteID converts at the rate of a quote fetched earlier and implies Convert
+	FXQuoteID *int64 `json:"fx_quote_id,omitempty" validate:"omitempty,min=1"`
+	// ExpectedSourceVersion settles the transfer only while the source account is at this version
+	ExpectedSourceVersion *int64 `json:"expected_source_version,omitempty" validate:"omitempty,min=1"`
+	// Reference is a caller-chosen label such as an invoice number, searchable on its own
+	Reference   string `json:"reference,omitempty" validate:"max=255"`
+	Description string `json:"description,omitempty" validate:"max=1000"`
+	// ExternalID is the transfer's ID in an upstream system and may be used once per source account
+	ExternalID string `json:"external_id,omitempty" validate:"max=255"`
+	// Metadata is a free-form object of up to 50 keys
+	Metadata map[string]interface{} `json:"metadata,omitempty" validate:"omitempty,max=50,dive,keys,min=1,max=40,endkeys"`
+	// Async is set by ?mode=async. The transfer is recorded as pending and settled by a worker.
+	Async bool `json:"-"`
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-" validate:"max=255"`
+}
+
+// IdempotencyPayload is what a retry with the same Idempotency-Key must repeat: the body and
+// the mode, so an async request is not replayed to a synchronous retry. Synchronous requests
+// hash as their body alone.
+func (r *CreateTransactionRequest) IdempotencyPayload() interface{} {
+	return struct {
+		Async bool `json:"async,omitempty"`
+		*CreateTransactionRequest
+	}{r.Async, r}
+}
+
+// TransferLeg is one transfer of a batch
+type TransferLeg struct {
+	SourceAccountID      int64  `json:"source_account_id" validate:"required,min=1"`
+	DestinationAccountID int64  `json:"destination_account_id" validate:"required,min=1,nefield=SourceAccountID"`
+	Amount               string `json:"amount" validate:"required,numeric"`
+}
+
+// CreateBatchTransactionRequest represents the request to make several transfers that
+// succeed or fail together
+type CreateBatchTransactionRequest struct {
+	Legs []TransferLeg `json:"legs" validate:"required,min=1,max=1000,dive"`
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-" validate:"max=255"`
+}
+
+// BatchTransactionResponse represents the response for batch creation, one transaction per leg
+type BatchTransactionResponse struct {
+	Transactions []*TransactionResponse `json:"transactions"`
+}
+
+// CreateReversalRequest represents the request to send money of a transfer back
+type CreateReversalRequest struct {
+	TransactionID int64 `json:"-"`
+	// Amount defaults to the part of the transfer that has not been reversed yet
+	Amount string `json:"amount" validate:"omitempty,numeric"`
+	Reason string `json:"reason" validate:"max=500"`
+	// IdempotencyKey is taken from the Idempotency-Key header
+	IdempotencyKey string `json:"-" validate:"max=255"`
+}
+
+// IdempotencyPayload is what a retry with the same Idempotency-Key must repeat: the body and
+// the transaction from the path, so a key reused on another transaction is rejected
+func (r *CreateReversalRequest) IdempotencyPayload() interface{} {
+	return struct {
+		TransactionID int64 `json:"transaction_id"`
+		*CreateReversalRequest
+	}{r.TransactionID, r}
 }
 
 // TransactionResponse represents the response for transaction creation
 type TransactionResponse struct {
-	ID                   int64             `json:"id"`
-	SourceAccountID      int64             `json:"source_account_id"`
-	DestinationAccountID int64             `json:"destination_account_id"`
-	Amount               string            `json:"amount"`
-	Status               TransactionStatus `json:"status"`
-	CreatedAt            time.Time         `json:"created_at"`
+	ID                   int64                   `json:"id"`
+	SourceAccountID      int64                   `json:"source_account_id"`
+	DestinationAccountID int64                   `json:"destination_account_id"`
+	Amount               string                  `json:"amount"`
+	Currency             string                  `json:"currency"`
+	Status               TransactionStatus       `json:"status"`
+	Kind                 TransactionKind         `json:"kind"`
+	ParentTransactionID  *int64                  `json:"parent_transaction_id,omitempty"`
+	ReversedAmount       string                  `json:"reversed_amount,omitempty"`
+	Reason               *string                 `json:"reason,omitempty"`
+	ExecuteAt            *time.Time              `json:"execute_at,omitempty"`
+	FailureCode          *string                 `json:"failure_code,omitempty"`
+	FailureReason        *string                 `json:"failure_reason,omitempty"`
+	StandingOrderID      *int64                  `json:"standing_order_id,omitempty"`
+	Reference            *string                 `json:"reference,omitempty"`
+	Description          *string                 `json:"description,omitempty"`
+	ExternalID           *string                 `json:"external_id,omitempty"`
+	Metadata             map[string]interface{}  `json:"metadata,omitempty"`
+	FX                   *FXConversionResponse   `json:"fx,omitempty"`
+	Fee                  *TransactionFeeResponse `json:"fee,omitempty"`
+	CreatedAt            time.Time               `json:"created_at"`
+}
+
+// TransactionDirection is the side of a transaction seen from one account
+type TransactionDirection string
+
+const (
+	TransactionDirectionIn  TransactionDirection = "in"
+	TransactionDirectionOut TransactionDirection = "out"
+)
+
+// ListAccountTransactionsRequest represents the query of an account statement
+type ListAccountTransactionsRequest struct {
+	AccountID int64  `json:"-"`
+	Cursor    string `query:"cursor"`
+	Limit     int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	From      string `query:"from"`
+	To        string `query:"to"`
+	Direction string `query:"direction" validate:"omitempty,oneof=in out"`
+	Status    string `query:"status" validate:"omitempty,oneof=pending completed failed reversed partially_reversed scheduled cancelled"`
+	MinAmount string `query:"min_amount" validate:"omitempty,numeric"`
+	MaxAmount string `query:"max_amount" validate:"omitempty,numeric"`
+}
+
+// AccountTransactionFilter narrows the transactions of an account. Nil fields are not filtered on.
+type AccountTransactionFilter struct {
+	AccountID int64
+	From      *time.Time
+	To        *time.Time
+	Direction *TransactionDirection
+	Status    *TransactionStatus
+	MinAmount *decimal.Decimal
+	MaxAmount *decimal.Decimal
+	After     *KeysetCursor
+	Limit     int
+}
+
+// ListScheduledTransactionsRequest represents the query of future-dated transfers
+type ListScheduledTransactionsRequest struct {
+	AccountID int64  `query:"account_id" validate:"omitempty,min=1"`
+	Status    string `query:"status" validate:"omitempty,oneof=scheduled completed failed cancelled"`
+	Cursor    string `query:"cursor"`
+	Limit     int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// ScheduledTransactionFilter narrows future-dated transfers, ordered by (execute_at, id)
+type ScheduledTransactionFilter struct {
+	AccountID *int64
+	Status    TransactionStatus
+	After     *KeysetCursor
+	Limit     int
+}
+
+// SearchTransactionsRequest represents the query of transactions by the details their callers supplied.
+// Metadata is read from metadata.<key>=<value> query parameters.
+type SearchTransactionsRequest struct {
+	Reference  string            `query:"reference" validate:"max=255"`
+	ExternalID string            `query:"external_id" validate:"max=255"`
+	AccountID  int64             `query:"account_id" validate:"omitempty,min=1"`
+	Metadata   map[string]string `json:"-" validate:"max=50"`
+	Cursor     string            `query:"cursor"`
+	Limit      int               `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// TransactionSearchFilter narrows a transaction search, newest first. Empty fields are not filtered on.
+type TransactionSearchFilter struct {
+	Reference  string
+	ExternalID string
+	AccountID  int64
+	// Metadata matches transactions whose metadata holds every key with one of the
+	// MetadataValues of its value
+	Metadata map[string]string
+	After    *KeysetCursor
+	Limit    int
+}
+
+// MetadataQueryPrefix marks the query parameters that filter on a metadata key
+const MetadataQueryPrefix = "metadata."
+
+// ParseMetadataQuery collects the metadata.<key>=<value> parameters of a query.
+// A key given more than once matches its first value.
+func ParseMetadataQuery(values url.Values) map[string]string {
+	metadata := make(map[string]string)
+	for name, vals := range values {
+		key := strings.TrimPrefix(name, MetadataQueryPrefix)
+		if key == name || key == "" || len(vals) == 0 {
+			continue
+		}
+		metadata[key] = vals[0]
+	}
+	return metadata
+}
+
+// MetadataValues returns the metadata values a query parameter value matches: the string
+// itself and, when it spells one, the JSON number or boolean. This way metadata.invoice=123
+// finds both {"invoice": "123"} and {"invoice": 123}. Objects, arrays and null are not matched.
+func MetadataValues(value string) []interface{} {
+	values := []interface{}{value}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil || decoder.More() {
+		return values
+	}
+
+	switch decoded.(type) {
+	case json.Number, bool:
+		values = append(values, decoded)
+	}
+	return values
+}
+
+// AccountTransaction is a transaction together with the balance it left on one account
+type AccountTransaction struct {
+	Transaction
+	RunningBalance *decimal.Decimal `json:"running_balance" db:"running_balance"`
+}
+
+// AccountTransactionResponse represents a line of an account statement
+type AccountTransactionResponse struct {
+	ID                    int64                `json:"id"`
+	Direction             TransactionDirection `json:"direction"`
+	CounterpartyAccountID int64                `json:"counterparty_account_id"`
+	Amount                string               `json:"amount"`
+	Currency              string               `json:"currency"`
+	Status                TransactionStatus    `json:"status"`
+	Kind                  TransactionKind      `json:"kind"`
+	ParentTransactionID   *int64               `json:"parent_transaction_id,omitempty"`
+	Reference             *string              `json:"reference,omitempty"`
+	Description           *string              `json:"description,omitempty"`
+	ExternalID            *string              `json:"external_id,omitempty"`
+	RunningBalance        *string              `json:"running_balance"`
+	CreatedAt             time.Time            `json:"created_at"`
+	CompletedAt           *time.Time           `json:"completed_at,omitempty"`
 }
@@ -0,0 +1,104 @@
+package model_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetadataQuery(t *testing.T) {
+	values, err := url.ParseQuery("reference=INV-1&metadata.invoice_id=981&metadata.region=eu&metadata.region=us&metadata.=x&limit=10")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"invoice_id": "981", "region": "eu"}, model.ParseMetadataQuery(values))
+}
+
+func TestParseMetadataQuery_KeyWithDot(t *testing.T) {
+	values, err := url.ParseQuery("metadata.order.id=7")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"order.id": "7"}, model.ParseMetadataQuery(values))
+}
+
+func TestParseMetadataQuery_None(t *testing.T) {
+	values, err := url.ParseQuery("reference=INV-1&metadata=x")
+	require.NoError(t, err)
+
+	assert.Empty(t, model.ParseMetadataQuery(values))
+}
+
+func TestCreateReversalRequest_IdempotencyPayload(t *testing.T) {
+	first := &model.CreateReversalRequest{TransactionID: 1, Amount: "10.00", Reason: "refund", IdempotencyKey: "refund-1"}
+	second := *first
+	second.TransactionID = 2
+
+	firstPayload, err := json.Marshal(first.IdempotencyPayload())
+	require.NoError(t, err)
+	secondPayload, err := json.Marshal(second.IdempotencyPayload())
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"transaction_id":1,"amount":"10.00","reason":"refund"}`, string(firstPayload))
+	assert.NotEqual(t, string(firstPayload), string(secondPayload))
+}
+
+func TestCreateTransactionRequest_IdempotencyPayload(t *testing.T) {
+	sync := &model.CreateTransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00", IdempotencyKey: "transfer-1"}
+	async := *sync
+	async.Async = true
+
+	syncPayload, err := json.Marshal(sync.IdempotencyPayload())
+	require.NoError(t, err)
+	body, err := json.Marshal(sync)
+	require.NoError(t, err)
+	asyncPayload, err := json.Marshal(async.IdempotencyPayload())
+	require.NoError(t, err)
+
+	assert.Equal(t, string(body), string(syncPayload))
+	assert.NotEqual(t, string(syncPayload), string(asyncPayload))
+}
+
+func TestMetadataValues(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []interface{}
+	}{
+		{"eu", []interface{}{"eu"}},
+		{"123", []interface{}{"123", json.Number("123")}},
+		{"12.50", []interface{}{"12.50", json.Number("12.50")}},
+		{"true", []interface{}{"true", true}},
+		{"null", []interface{}{"null"}},
+		{`{"a":1}`, []interface{}{`{"a":1}`}},
+		{"1 2", []interface{}{"1 2"}},
+		{"", []interface{}{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.expected, model.MetadataValues(tt.value))
+		})
+	}
+}
+
+func TestMetadataValues_NonStringDocuments(t *testing.T) {
+	// A search for metadata.invoice=123&metadata.paid=true also contains the number and the
+	// boolean, so metadata stored as {"invoice": 123, "paid": true} matches
+	filter := map[string]string{"invoice": "123", "paid": "true"}
+	expected := map[string][]string{
+		"invoice": {`{"invoice":"123"}`, `{"invoice":123}`},
+		"paid":    {`{"paid":"true"}`, `{"paid":true}`},
+	}
+
+	for key, value := range filter {
+		var documents []string
+		for _, candidate := range model.MetadataValues(value) {
+			document, err := json.Marshal(map[string]interface{}{key: candidate})
+			require.NoError(t, err)
+			documents = append(documents, string(document))
+		}
+		assert.Equal(t, expected[key], documents, key)
+	}
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// VelocityLimitKind is what a velocity limit restricts
+type VelocityLimitKind string
+
+const (
+	// VelocityLimitOutgoingAmount caps the total sent within the window
+	VelocityLimitOutgoingAmount VelocityLimitKind = "outgoing_amount"
+	// VelocityLimitOutgoingCount caps the number of transfers sent within the window
+	VelocityLimitOutgoingCount VelocityLimitKind = "outgoing_count"
+	// VelocityLimitSingleAmount caps the amount of any one transfer
+	VelocityLimitSingleAmount VelocityLimitKind = "single_amount"
+)
+
+// VelocityLimit restricts the outgoing transfers of one account, or of all accounts in a group
+// together, over a rolling window. Only transfers in the currency of the limit count against it.
+type VelocityLimit struct {
+	ID           int64             `json:"id" db:"id"`
+	AccountID    *int64            `json:"account_id" db:"account_id"`
+	AccountGroup *string           `json:"account_group" db:"account_group"`
+	Currency     string            `json:"currency" db:"currency"`
+	Kind         VelocityLimitKind `json:"kind" db:"kind"`
+	// WindowSeconds is the length of the rolling window; nil for single transfer limits
+	WindowSeconds *int            `json:"window_seconds" db:"window_seconds"`
+	MaxValue      decimal.Decimal `json:"max_value" db:"max_value"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+}
+
+// Window is the length of the rolling window
+func (l *VelocityLimit) Window() time.Duration {
+	if l.WindowSeconds == nil {
+		return 0
+	}
+	return time.Duration(*l.WindowSeconds) * time.Second
+}
+
+// AppliesTo reports whether the limit covers transfers from account. Transfers are in the
+// currency of their source account, so a limit in another currency does not cover them.
+func (l *VelocityLimit) AppliesTo(account *Account) bool {
+	if l.Currency != account.Currency {
+		return false
+	}
+	if l.AccountID != nil {
+		return *l.AccountID == account.ID
+	}
+	return account.AccountGroup != nil && l.AccountGroup != nil && *account.AccountGroup == *l.AccountGroup
+}
+
+// String describes the limit for error messages, e.g. "outgoing_amount limit 3 (1000 USD per 24h0m0s, account 123)"
+func (l *VelocityLimit) String() string {
+	scope := ""
+	if l.AccountID != nil {
+		scope = fmt.Sprintf("account %d", *l.AccountID)
+	} else if l.AccountGroup != nil {
+		scope = "group " + *l.AccountGroup
+	}
+
+	// A count limit counts transfers in the currency rather than an amount of it
+	value := l.MaxValue.String() + " " + l.Currency
+	if l.Kind == VelocityLimitOutgoingCount {
+		value += " transfers"
+	}
+
+	if l.WindowSeconds == nil {
+		return fmt.Sprintf("%s limit %d (%s, %s)", l.Kind, l.ID, value, scope)
+	}
+	return fmt.Sprintf("%s limit %d (%s per %s, %s)", l.Kind, l.ID, value, l.Window(), scope)
+}
+
+// CreateVelocityLimitRequest represents the request to add a velocity limit. Exactly one of
+// AccountID and AccountGroup must be set. Currency is an ISO 4217 code that defaults to the
+// currency of the account; group limits need it, as their accounts may hold different currencies.
+type CreateVelocityLimitRequest struct {
+	AccountID    *int64            `json:"account_id,omitempty" validate:"omitempty,min=1"`
+	AccountGroup *string           `json:"account_group,omitempty" validate:"omitempty,min=1,max=100"`
+	Currency     string            `json:"currency,omitempty" validate:"omitempty,len=3"`
+	Kind         VelocityLimitKind `json:"kind" validate:"required,oneof=outgoing_amount outgoing_count single_amount"`
+	// WindowSeconds is required for rolling limits and must be left out for single transfer limits
+	WindowSeconds *int   `json:"window_seconds,omitempty" validate:"omitempty,min=1"`
+	MaxValue      string `json:"max_value" validate:"required,numeric"`
+}
+
+// ListVelocityLimitsRequest filters the velocity limits listing
+type ListVelocityLimitsRequest struct {
+	AccountID    int64  `query:"account_id" validate:"omitempty,min=1"`
+	AccountGroup string `query:"account_group" validate:"omitempty,max=100"`
+}
+
+// VelocityLimitResponse represents a velocity limit in API responses
+type VelocityLimitResponse struct {
+	ID            int64             `json:"id"`
+	AccountID     *int64            `json:"account_id,omitempty"`
+	AccountGroup  *string           `json:"account_group,omitempty"`
+	Currency      string            `json:"currency"`
+	Kind          VelocityLimitKind `json:"kind"`
+	WindowSeconds *int              `json:"window_seconds,omitempty"`
+	MaxValue      string            `json:"max_value"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// UpdateAccountGroupRequest moves an account into a velocity limit group
+type UpdateAccountGroupRequest struct {
+	// AccountID is taken from the path
+	AccountID int64 `json:"-"`
+	// AccountGroup is left out to remove the account from its group
+	AccountGroup *string `json:"account_group" validate:"omitempty,min=1,max=100"`
+	// ExpectedVersion is taken from the If-Match header; nil applies the change to any version
+	ExpectedVersion *int64 `json:"-"`
+}
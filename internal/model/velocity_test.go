@@ -0,0 +1,46 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVelocityLimit_AppliesTo(t *testing.T) {
+	accountID, group, otherGroup := int64(1), "retail", "business"
+
+	tests := []struct {
+		name     string
+		limit    model.VelocityLimit
+		account  model.Account
+		expected bool
+	}{
+		{"account limit", model.VelocityLimit{AccountID: &accountID, Currency: "USD"}, model.Account{ID: 1, Currency: "USD"}, true},
+		{"other account", model.VelocityLimit{AccountID: &accountID, Currency: "USD"}, model.Account{ID: 2, Currency: "USD"}, false},
+		{"group limit", model.VelocityLimit{AccountGroup: &group, Currency: "USD"}, model.Account{ID: 2, Currency: "USD", AccountGroup: &group}, true},
+		{"other group", model.VelocityLimit{AccountGroup: &group, Currency: "USD"}, model.Account{ID: 2, Currency: "USD", AccountGroup: &otherGroup}, false},
+		{"no group", model.VelocityLimit{AccountGroup: &group, Currency: "USD"}, model.Account{ID: 2, Currency: "USD"}, false},
+		{"group account in another currency", model.VelocityLimit{AccountGroup: &group, Currency: "USD"}, model.Account{ID: 3, Currency: "JPY", AccountGroup: &group}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.limit.AppliesTo(&tt.account))
+		})
+	}
+}
+
+func TestVelocityLimit_String(t *testing.T) {
+	accountID, group, window := int64(123), "retail", 86400
+
+	amount := &model.VelocityLimit{ID: 3, AccountID: &accountID, Currency: "USD", Kind: model.VelocityLimitOutgoingAmount, WindowSeconds: &window, MaxValue: decimal.NewFromInt(1000)}
+	assert.Equal(t, "outgoing_amount limit 3 (1000 USD per 24h0m0s, account 123)", amount.String())
+
+	count := &model.VelocityLimit{ID: 4, AccountGroup: &group, Currency: "JPY", Kind: model.VelocityLimitOutgoingCount, WindowSeconds: &window, MaxValue: decimal.NewFromInt(5)}
+	assert.Equal(t, "outgoing_count limit 4 (5 JPY transfers per 24h0m0s, group retail)", count.String())
+
+	single := &model.VelocityLimit{ID: 5, AccountID: &accountID, Currency: "USD", Kind: model.VelocityLimitSingleAmount, MaxValue: decimal.NewFromInt(250)}
+	assert.Equal(t, "single_amount limit 5 (250 USD, account 123)", single.String())
+}
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookDeliveryStatus is where a delivery is in its lifecycle
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending is waiting for its next attempt
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered was acknowledged by the receiver with a 2xx status
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+)
+
+// Webhook is an endpoint subscribed to event types
+type Webhook struct {
+	ID          int64    `json:"id" db:"id"`
+	URL         string   `json:"url" db:"url"`
+	EventTypes  []string `json:"event_types" db:"event_types"`
+	Description *string  `json:"description" db:"description"`
+	Active      bool     `json:"active" db:"active"`
+	Secret      string   `json:"-" db:"secret"`
+	// PreviousSecret is the secret replaced by the last rotation, valid until PreviousSecretExpiresAt
+	PreviousSecret          *string    `json:"-" db:"previous_secret"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at" db:"previous_secret_expires_at"`
+	CreatedAt               time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDelivery is one event on its way to one webhook
+type WebhookDelivery struct {
+	ID             int64                 `json:"id" db:"id"`
+	WebhookID      int64                 `json:"webhook_id" db:"webhook_id"`
+	EventID        int64                 `json:"event_id" db:"event_id"`
+	EventType      string                `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" db:"next_attempt_at"`
+	LastStatusCode *int                  `json:"last_status_code" db:"last_status_code"`
+	LastError      *string               `json:"last_error" db:"last_error"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at" db:"delivered_at"`
+}
+
+// WebhookTarget is a claimed delivery with where to send it and the secrets to sign it with
+type WebhookTarget struct {
+	Delivery *WebhookDelivery
+	URL      string
+	// Secrets are the current secret, followed by the previous one while it has not expired
+	Secrets []string
+}
+
+// WebhookDeadLetter is a delivery that failed every attempt
+type WebhookDeadLetter struct {
+	ID             int64           `json:"id" db:"id"`
+	WebhookID      int64           `json:"webhook_id" db:"webhook_id"`
+	EventID        int64           `json:"event_id" db:"event_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	LastStatusCode *int            `json:"last_status_code" db:"last_status_code"`
+	LastError      *string         `json:"last_error" db:"last_error"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	ReplayedAt     *time.Time      `json:"replayed_at" db:"replayed_at"`
+}
+
+// CreateWebhookRequest represents the request to subscribe an endpoint to event types
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,http_url,max=2048"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1,max=20,dive,required"`
+	Description string   `json:"description,omitempty" validate:"max=255"`
+	// Active defaults to true
+	Active *bool `json:"active,omitempty"`
+}
+
+// UpdateWebhookRequest changes the fields it carries and leaves the others as they are
+type UpdateWebhookRequest struct {
+	// ID is taken from the path
+	ID          int64    `json:"-"`
+	URL         *string  `json:"url,omitempty" validate:"omitempty,http_url,max=2048"`
+	EventTypes  []string `json:"event_types,omitempty" validate:"omitempty,min=1,max=20,dive,required"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=255"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// RotateWebhookSecretRequest replaces the secret of a webhook
+type RotateWebhookSecretRequest struct {
+	// ID is taken from the path
+	ID int64 `json:"-"`
+	// GracePeriodSeconds is how long the old secret keeps signing deliveries; the configured
+	// default applies when it is left out, 0 retires the old secret at once
+	GracePeriodSeconds *int `json:"grace_period_seconds,omitempty" validate:"omitempty,min=0,max=604800"`
+}
+
+// ListWebhookDeadLettersRequest pages through the dead letters of a webhook, newest first
+type ListWebhookDeadLettersRequest struct {
+	// WebhookID is taken from the path
+	WebhookID int64  `json:"-"`
+	Cursor    string `query:"cursor"`
+	Limit     int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// WebhookDeadLetterFilter narrows the dead letters of a webhook, ordered by (created_at, id) descending
+type WebhookDeadLetterFilter struct {
+	WebhookID int64
+	Before    *KeysetCursor
+	Limit     int
+}
+
+// WebhookResponse represents a webhook in API responses. The secret is only returned when
+// the webhook is created and when it is rotated.
+type WebhookResponse struct {
+	ID                      int64      `json:"id"`
+	URL                     string     `json:"url"`
+	EventTypes              []string   `json:"event_types"`
+	Description             *string    `json:"description,omitempty"`
+	Active                  bool       `json:"active"`
+	Secret                  string     `json:"secret,omitempty"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse represents a delivery in API responses
+type WebhookDeliveryResponse struct {
+	ID             int64                 `json:"id"`
+	WebhookID      int64                 `json:"webhook_id"`
+	EventID        int64                 `json:"event_id"`
+	EventType      string                `json:"event_type"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at"`
+	LastStatusCode *int                  `json:"last_status_code,omitempty"`
+	LastError      *string               `json:"last_error,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+}
+
+// WebhookDeadLetterResponse represents a dead letter in API responses
+type WebhookDeadLetterResponse struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode *int            `json:"last_status_code,omitempty"`
+	LastError      *string         `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	ReplayedAt     *time.Time      `json:"replayed_at,omitempty"`
+}
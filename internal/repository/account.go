@@ -21,21 +21,45 @@ func NewAccountRepository(s *server.Server) AccountRepository {
 	}
 }
 
-func (r *accountRepository) Create(ctx context.Context, accountID int64, initialBalance decimal.Decimal) (*model.Account, error) {
-	query := `
-		INSERT INTO accounts (id, balance, created_at, updated_at)
-		VALUES ($1, $2, NOW(), NOW())
-		RETURNING id, balance, created_at, updated_at
-	`
+// accountColumns is the column list every account query selects, in scanAccount order
+const accountColumns = `id, currency, balance, held_amount, overdraft_limit, max_balance, status,
+	status_reason, closed_at, account_group, interest_product_id, version, created_at, updated_at`
 
-	var account model.Account
-	err := r.db.QueryRow(ctx, query, accountID, initialBalance).Scan(
+// scanAccount scans a row selected with accountColumns
+func scanAccount(row pgx.Row, account *model.Account) error {
+	return row.Scan(
 		&account.ID,
+		&account.Currency,
 		&account.Balance,
+		&account.HeldAmount,
+		&account.OverdraftLimit,
+		&account.MaxBalance,
+		&account.Status,
+		&account.StatusReason,
+		&account.ClosedAt,
+		&account.AccountGroup,
+		&account.InterestProductID,
+		&account.Version,
 		&account.CreatedAt,
 		&account.UpdatedAt,
 	)
+}
+
+// Create inserts a new account with a zero balance. The initial balance is posted through the ledger.
+// An existing ID is reported without aborting the transaction.
+func (r *accountRepository) Create(ctx context.Context, tx pgx.Tx, accountID int64, currency string) (*model.Account, error) {
+	query := `
+		INSERT INTO accounts (id, currency, balance, created_at, updated_at)
+		VALUES ($1, $2, 0, NOW(), NOW())
+		ON CONFLICT (id) DO NOTHING
+		RETURNING ` + accountColumns
+
+	var account model.Account
+	err := scanAccount(tx.QueryRow(ctx, query, accountID, currency), &account)
 	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("account already exists")
+		}
 		return nil, fmt.Errorf("failed to create account: %w", err)
 	}
 
@@ -44,18 +68,13 @@ func (r *accountRepository) Create(ctx context.Context, accountID int64, initial
 
 func (r *accountRepository) GetByID(ctx context.Context, id int64) (*model.Account, error) {
 	query := `
-		SELECT id, balance, created_at, updated_at
+		SELECT ` + accountColumns + `
 		FROM accounts
 		WHERE id = $1
 	`
 
 	var account model.Account
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&account.ID,
-		&account.Balance,
-		&account.CreatedAt,
-		&account.UpdatedAt,
-	)
+	err := scanAccount(r.db.QueryRow(ctx, query, id), &account)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, fmt.Errorf("account not found")
@@ -66,17 +85,71 @@ func (r *accountRepository) GetByID(ctx context.Context, id int64) (*model.Accou
 	return &account, nil
 }
 
-func (r *accountRepository) UpdateBalance(ctx context.Context, tx pgx.Tx, id int64, newBalance decimal.Decimal) error {
+// GetByIDForUpdate uses row lock to prevent concurrent updates
+func (r *accountRepository) GetByIDForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*model.Account, error) {
+	query := `
+		SELECT ` + accountColumns + `
+		FROM accounts
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var account model.Account
+	err := scanAccount(tx.QueryRow(ctx, query, id), &account)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to get account for update: %w", err)
+	}
+
+	return &account, nil
+}
+
+// GetByIDsForUpdate locks every existing account in ids in ascending ID order, so callers
+// locking overlapping sets cannot deadlock. Missing IDs are simply absent from the result.
+func (r *accountRepository) GetByIDsForUpdate(ctx context.Context, tx pgx.Tx, ids []int64) ([]*model.Account, error) {
+	query := `
+		SELECT ` + accountColumns + `
+		FROM accounts
+		WHERE id = ANY($1)
+		ORDER BY id
+		FOR UPDATE
+	`
+
+	rows, err := tx.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts for update: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*model.Account
+	for rows.Next() {
+		var account model.Account
+		if err := scanAccount(rows, &account); err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, &account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// AdjustHeld adds delta, which may be negative, to the amount reserved by holds on an account
+func (r *accountRepository) AdjustHeld(ctx context.Context, tx pgx.Tx, accountID int64, delta decimal.Decimal) error {
 	query := `
 		UPDATE accounts
-		SET balance = $2
+		SET held_amount = held_amount + $2, updated_at = NOW()
 		WHERE id = $1
 	`
 
-	result, err := tx.Exec(ctx, query, id, newBalance)
+	result, err := tx.Exec(ctx, query, accountID, delta)
 	if err != nil {
-		// Return the error directly so it can be checked for constraint violations
-		return err
+		return fmt.Errorf("failed to adjust held amount: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
@@ -86,27 +159,93 @@ func (r *accountRepository) UpdateBalance(ctx context.Context, tx pgx.Tx, id int
 	return nil
 }
 
-// GetByIDForUpdate uses row lock to prevent concurrent updates
-func (r *accountRepository) GetByIDForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*model.Account, error) {
+// UpdateLimits sets the overdraft limit and maximum balance of an account. A nil maxBalance
+// removes the account's maximum.
+func (r *accountRepository) UpdateLimits(ctx context.Context, tx pgx.Tx, accountID int64, overdraftLimit decimal.Decimal, maxBalance *decimal.Decimal) (*model.Account, error) {
 	query := `
-		SELECT id, balance, created_at, updated_at
-		FROM accounts
+		UPDATE accounts
+		SET overdraft_limit = $2, max_balance = $3, updated_at = NOW()
 		WHERE id = $1
-		FOR UPDATE
-	`
+		RETURNING ` + accountColumns
 
 	var account model.Account
-	err := tx.QueryRow(ctx, query, id).Scan(
-		&account.ID,
-		&account.Balance,
-		&account.CreatedAt,
-		&account.UpdatedAt,
-	)
+	err := scanAccount(tx.QueryRow(ctx, query, accountID, overdraftLimit, maxBalance), &account)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, fmt.Errorf("account not found")
 		}
-		return nil, fmt.Errorf("failed to get account for update: %w", err)
+		return nil, fmt.Errorf("failed to update account limits: %w", err)
+	}
+
+	return &account, nil
+}
+
+// UpdateStatus moves an account to status, recording why. Closing also stamps closed_at.
+func (r *accountRepository) UpdateStatus(ctx context.Context, tx pgx.Tx, accountID int64, status model.AccountStatus, reason *string) (*model.Account, error) {
+	query := `
+		UPDATE accounts
+		SET status = $2,
+			status_reason = $3,
+			closed_at = CASE WHEN $2 = 'closed' THEN NOW() ELSE closed_at END,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + accountColumns
+
+	var account model.Account
+	err := scanAccount(tx.QueryRow(ctx, query, accountID, status, reason), &account)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to update account status: %w", err)
+	}
+
+	return &account, nil
+}
+
+// UpdateGroup moves an account into a velocity limit group, or out of its group when group is nil.
+// With expectedVersion set, an account at another version is left unchanged.
+func (r *accountRepository) UpdateGroup(ctx context.Context, accountID int64, group *string, expectedVersion *int64) (*model.Account, error) {
+	query := `
+		UPDATE accounts
+		SET account_group = $2, updated_at = NOW()
+		WHERE id = $1 AND ($3::BIGINT IS NULL OR version = $3)
+		RETURNING ` + accountColumns
+
+	var account model.Account
+	err := scanAccount(r.db.QueryRow(ctx, query, accountID, group, expectedVersion), &account)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			if expectedVersion != nil {
+				return nil, fmt.Errorf("account not found or version changed")
+			}
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to update account group: %w", err)
+	}
+
+	return &account, nil
+}
+
+// UpdateInterestProduct assigns the account to an interest product, or removes it from one.
+// With expectedVersion set, an account at another version is left unchanged.
+func (r *accountRepository) UpdateInterestProduct(ctx context.Context, accountID int64, productID *int64, expectedVersion *int64) (*model.Account, error) {
+	query := `
+		UPDATE accounts
+		SET interest_product_id = $2, updated_at = NOW()
+		WHERE id = $1 AND ($3::BIGINT IS NULL OR version = $3)
+		RETURNING ` + accountColumns
+
+	var account model.Account
+	err := scanAccount(r.db.QueryRow(ctx, query, accountID, productID, expectedVersion), &account)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			if expectedVersion != nil {
+				return nil, fmt.Errorf("account not found or version changed")
+			}
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to update account interest product: %w", err)
 	}
 
 	return &account, nil
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type accountLimitRepository struct {
+	db database.DB
+}
+
+func NewAccountLimitRepository(s *server.Server) AccountLimitRepository {
+	return &accountLimitRepository{
+		db: s.DB,
+	}
+}
+
+// accountLimitChangeColumns is the column list every limit change query selects, in scanAccountLimitChange order
+const accountLimitChangeColumns = `id, account_id, previous_overdraft_limit, overdraft_limit, previous_max_balance, max_balance,
+	changed_by, reason, created_at`
+
+// scanAccountLimitChange scans a row selected with accountLimitChangeColumns
+func scanAccountLimitChange(row pgx.Row, change *model.AccountLimitChange) error {
+	return row.Scan(
+		&change.ID,
+		&change.AccountID,
+		&change.PreviousOverdraftLimit,
+		&change.OverdraftLimit,
+		&change.PreviousMaxBalance,
+		&change.MaxBalance,
+		&change.ChangedBy,
+		&change.Reason,
+		&change.CreatedAt,
+	)
+}
+
+// RecordChange appends a limit change to the audit trail inside tx, so it commits with the change itself
+func (r *accountLimitRepository) RecordChange(ctx context.Context, tx pgx.Tx, change *model.AccountLimitChange) error {
+	query := `
+		INSERT INTO account_limit_changes (account_id, previous_overdraft_limit, overdraft_limit,
+			previous_max_balance, max_balance, changed_by, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING ` + accountLimitChangeColumns
+
+	err := scanAccountLimitChange(tx.QueryRow(ctx, query,
+		change.AccountID,
+		change.PreviousOverdraftLimit,
+		change.OverdraftLimit,
+		change.PreviousMaxBalance,
+		change.MaxBalance,
+		change.ChangedBy,
+		change.Reason,
+	), change)
+	if err != nil {
+		return fmt.Errorf("failed to record account limit change: %w", err)
+	}
+
+	return nil
+}
+
+// ListChanges returns every limit change of an account, newest first
+func (r *accountLimitRepository) ListChanges(ctx context.Context, accountID int64) ([]*model.AccountLimitChange, error) {
+	query := `
+		SELECT ` + accountLimitChangeColumns + `
+		FROM account_limit_changes
+		WHERE account_id = $1
+		ORDER BY id DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list account limit changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := make([]*model.AccountLimitChange, 0)
+	for rows.Next() {
+		var change model.AccountLimitChange
+		if err := scanAccountLimitChange(rows, &change); err != nil {
+			return nil, fmt.Errorf("failed to scan account limit change: %w", err)
+		}
+		changes = append(changes, &change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating account limit changes: %w", err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+// signedAmount is a ledger entry as a change to the account balance
+const signedAmount = `CASE direction WHEN 'debit' THEN -amount ELSE amount END`
+
+type balanceSnapshotRepository struct {
+	db database.DB
+}
+
+func NewBalanceSnapshotRepository(s *server.Server) BalanceSnapshotRepository {
+	return &balanceSnapshotRepository{
+		db: s.DB,
+	}
+}
+
+// GetLastRunDate returns the latest day snapshots were taken for, or nil before the first run
+func (r *balanceSnapshotRepository) GetLastRunDate(ctx context.Context) (*time.Time, error) {
+	var date *time.Time
+	if err := r.db.QueryRow(ctx, `SELECT MAX(snapshot_date) FROM balance_snapshot_runs`).Scan(&date); err != nil {
+		return nil, fmt.Errorf("failed to get last balance snapshot run: %w", err)
+	}
+
+	return date, nil
+}
+
+// GetFirstPostingTime returns when the ledger received its first entry, or nil when it is empty
+func (r *balanceSnapshotRepository) GetFirstPostingTime(ctx context.Context) (*time.Time, error) {
+	var postedAt *time.Time
+	if err := r.db.QueryRow(ctx, `SELECT MIN(created_at) FROM ledger_entries`).Scan(&postedAt); err != nil {
+		return nil, fmt.Errorf("failed to get first ledger entry: %w", err)
+	}
+
+	return postedAt, nil
+}
+
+// TakeDay writes the closing balance of every account that moved on the day, from its previous
+// snapshot and the day's postings, and returns how many accounts it wrote. The days before must
+// have been taken already. A day another run has claimed fails with "balance snapshot already taken".
+func (r *balanceSnapshotRepository) TakeDay(ctx context.Context, tx pgx.Tx, date time.Time) (int64, error) {
+	closingAt := date.AddDate(0, 0, 1)
+
+	// Claiming the day first makes a concurrent run wait here and then back off
+	claim := `
+		INSERT INTO balance_snapshot_runs (snapshot_date, accounts, created_at)
+		VALUES ($1, 0, NOW())
+		ON CONFLICT (snapshot_date) DO NOTHING
+	`
+
+	result, err := tx.Exec(ctx, claim, date)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim balance snapshot day: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return 0, fmt.Errorf("balance snapshot already taken")
+	}
+
+	query := `
+		INSERT INTO balance_snapshots (account_id, snapshot_date, closing_at, balance, created_at)
+		SELECT account_id, $1, $3,
+			COALESCE((
+				SELECT s.balance
+				FROM balance_snapshots s
+				WHERE s.account_id = ledger_entries.account_id AND s.snapshot_date < $1
+				ORDER BY s.snapshot_date DESC
+				LIMIT 1
+			), 0) + SUM(` + signedAmount + `),
+			NOW()
+		FROM ledger_entries
+		WHERE created_at >= $2 AND created_at < $3
+		GROUP BY account_id
+	`
+
+	result, err = tx.Exec(ctx, query, date, date, closingAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write balance snapshots: %w", err)
+	}
+	accounts := result.RowsAffected()
+
+	if _, err := tx.Exec(ctx, `UPDATE balance_snapshot_runs SET accounts = $2 WHERE snapshot_date = $1`, date, accounts); err != nil {
+		return 0, fmt.Errorf("failed to record balance snapshot run: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// GetBalanceAsOf returns the balance of an account at a point in time: its latest snapshot that
+// closed by then plus the postings made since, up to and including asOf
+func (r *balanceSnapshotRepository) GetBalanceAsOf(ctx context.Context, accountID int64, asOf time.Time) (decimal.Decimal, error) {
+	balance, err := getBalanceAt(ctx, r.db, accountID, asOf, "<=")
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get balance as of %s: %w", asOf.Format(time.RFC3339), err)
+	}
+
+	return balance, nil
+}
+
+// GetBalanceBefore returns the balance of an account from the postings made before a point in
+// time, leaving out those made at it, as read by the transaction
+func (r *balanceSnapshotRepository) GetBalanceBefore(ctx context.Context, tx pgx.Tx, accountID int64, before time.Time) (decimal.Decimal, error) {
+	balance, err := getBalanceAt(ctx, tx, accountID, before, "<")
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get balance before %s: %w", before.Format(time.RFC3339), err)
+	}
+
+	return balance, nil
+}
+
+// getBalanceAt adds the postings after the latest snapshot that closed by at, compared to at
+// with op, to the snapshot balance
+func getBalanceAt(ctx context.Context, q database.TxQuerier, accountID int64, at time.Time, op string) (decimal.Decimal, error) {
+	query := `
+		WITH snapshot AS (
+			SELECT balance, closing_at
+			FROM balance_snapshots
+			WHERE account_id = $1 AND closing_at <= $2
+			ORDER BY snapshot_date DESC
+			LIMIT 1
+		)
+		SELECT COALESCE((SELECT balance FROM snapshot), 0) + COALESCE(SUM(` + signedAmount + `), 0)
+		FROM ledger_entries
+		WHERE account_id = $1
+			AND created_at >= COALESCE((SELECT closing_at FROM snapshot), '-infinity')
+			AND created_at ` + op + ` $2
+	`
+
+	var balance decimal.Decimal
+	err := q.QueryRow(ctx, query, accountID, at).Scan(&balance)
+	return balance, err
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+// eventFeedLockKey is the advisory lock that serializes appends to the feed
+const eventFeedLockKey int64 = 0x6576656e7473
+
+type eventFeedRepository struct {
+	db database.DB
+}
+
+func NewEventFeedRepository(s *server.Server) EventFeedRepository {
+	return &eventFeedRepository{
+		db: s.DB,
+	}
+}
+
+// feedEventColumns is the column list every feed query selects, in scanFeedEvent order
+const feedEventColumns = `position, event_id, event_type, aggregate_type, aggregate_id, payload, created_at`
+
+// scanFeedEvent scans a row selected with feedEventColumns
+func scanFeedEvent(row pgx.Row, e *model.FeedEvent) error {
+	return row.Scan(
+		&e.Position,
+		&e.ID,
+		&e.Type,
+		&e.AggregateType,
+		&e.AggregateID,
+		&e.Data,
+		&e.CreatedAt,
+	)
+}
+
+// consumerGroupColumns is the column list every consumer group query selects, in scanConsumerGroup order
+const consumerGroupColumns = `name, position, created_at, updated_at`
+
+// scanConsumerGroup scans a row selected with consumerGroupColumns
+func scanConsumerGroup(row pgx.Row, group *model.ConsumerGroup) error {
+	return row.Scan(
+		&group.Name,
+		&group.Position,
+		&group.CreatedAt,
+		&group.UpdatedAt,
+	)
+}
+
+// Append adds published outbox events to the feed in ID order. The advisory lock is held until
+// tx commits, so a later append takes higher positions and also commits later: a reader that
+// sees a position has already been able to see every position below it.
+func (r *eventFeedRepository) Append(ctx context.Context, tx pgx.Tx, eventIDs []int64) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, eventFeedLockKey); err != nil {
+		return fmt.Errorf("failed to lock event feed: %w", err)
+	}
+
+	query := `
+		INSERT INTO events (event_id, event_type, aggregate_type, aggregate_id, payload, created_at, published_at)
+		SELECT id, event_type, aggregate_type, aggregate_id, payload, created_at, NOW()
+		FROM outbox
+		WHERE id = ANY($1)
+		ORDER BY id
+		ON CONFLICT (event_id) DO NOTHING
+	`
+
+	if _, err := tx.Exec(ctx, query, eventIDs); err != nil {
+		return fmt.Errorf("failed to append events to feed: %w", err)
+	}
+
+	return nil
+}
+
+// ListAfter returns up to limit events after a position, in position order
+func (r *eventFeedRepository) ListAfter(ctx context.Context, position int64, limit int) ([]*model.FeedEvent, error) {
+	query := `
+		SELECT ` + feedEventColumns + `
+		FROM events
+		WHERE position > $1
+		ORDER BY position
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, position, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]*model.FeedEvent, 0)
+	for rows.Next() {
+		var e model.FeedEvent
+		if err := scanFeedEvent(rows, &e); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, &e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetLastPosition returns the position of the latest event, or 0 when the feed is empty
+func (r *eventFeedRepository) GetLastPosition(ctx context.Context) (int64, error) {
+	var position int64
+	if err := r.db.QueryRow(ctx, `SELECT COALESCE(MAX(position), 0) FROM events`).Scan(&position); err != nil {
+		return 0, fmt.Errorf("failed to get last event position: %w", err)
+	}
+
+	return position, nil
+}
+
+func (r *eventFeedRepository) GetConsumerGroup(ctx context.Context, name string) (*model.ConsumerGroup, error) {
+	query := `SELECT ` + consumerGroupColumns + ` FROM event_consumer_groups WHERE name = $1`
+
+	var group model.ConsumerGroup
+	if err := scanConsumerGroup(r.db.QueryRow(ctx, query, name), &group); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("consumer group not found")
+		}
+		return nil, fmt.Errorf("failed to get consumer group: %w", err)
+	}
+
+	return &group, nil
+}
+
+// ListConsumerGroups returns every consumer group by name
+func (r *eventFeedRepository) ListConsumerGroups(ctx context.Context) ([]*model.ConsumerGroup, error) {
+	query := `SELECT ` + consumerGroupColumns + ` FROM event_consumer_groups ORDER BY name`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consumer groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := make([]*model.ConsumerGroup, 0)
+	for rows.Next() {
+		var group model.ConsumerGroup
+		if err := scanConsumerGroup(rows, &group); err != nil {
+			return nil, fmt.Errorf("failed to scan consumer group: %w", err)
+		}
+		groups = append(groups, &group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating consumer groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// CommitOffset moves a consumer group to a position, creating the group on its first commit.
+// An offset never moves backwards; committing the current position again changes nothing.
+func (r *eventFeedRepository) CommitOffset(ctx context.Context, name string, position int64) (*model.ConsumerGroup, error) {
+	query := `
+		INSERT INTO event_consumer_groups (name, position, created_at, updated_at)
+		VALUES ($1, $2, NOW(), NOW())
+		ON CONFLICT (name) DO UPDATE
+		SET position = EXCLUDED.position
+		WHERE event_consumer_groups.position <= EXCLUDED.position
+		RETURNING ` + consumerGroupColumns
+
+	var group model.ConsumerGroup
+	if err := scanConsumerGroup(r.db.QueryRow(ctx, query, name, position), &group); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("consumer group offset is ahead")
+		}
+		return nil, fmt.Errorf("failed to commit consumer group offset: %w", err)
+	}
+
+	return &group, nil
+}
+
+func (r *eventFeedRepository) DeleteConsumerGroup(ctx context.Context, name string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM event_consumer_groups WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete consumer group: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("consumer group not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type feeRuleRepository struct {
+	db database.DB
+}
+
+func NewFeeRuleRepository(s *server.Server) FeeRuleRepository {
+	return &feeRuleRepository{
+		db: s.DB,
+	}
+}
+
+// feeRuleColumns is the column list every fee rule query selects, in scanFeeRule order
+const feeRuleColumns = `id, name, kind, currency, flat_amount, rate, min_fee, max_fee, tiers,
+	source_account_id, destination_account_id, payer, revenue_account_id, priority, active, created_at`
+
+// scanFeeRule scans a row selected with feeRuleColumns
+func scanFeeRule(row pgx.Row, rule *model.FeeRule) error {
+	return row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.Kind,
+		&rule.Currency,
+		&rule.FlatAmount,
+		&rule.Rate,
+		&rule.MinFee,
+		&rule.MaxFee,
+		&rule.Tiers,
+		&rule.SourceAccountID,
+		&rule.DestinationAccountID,
+		&rule.Payer,
+		&rule.RevenueAccountID,
+		&rule.Priority,
+		&rule.Active,
+		&rule.CreatedAt,
+	)
+}
+
+func (r *feeRuleRepository) Create(ctx context.Context, rule *model.FeeRule) error {
+	query := `
+		INSERT INTO fee_rules (name, kind, currency, flat_amount, rate, min_fee, max_fee, tiers,
+			source_account_id, destination_account_id, payer, revenue_account_id, priority, active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, TRUE, NOW())
+		RETURNING ` + feeRuleColumns
+
+	// Only tiered rules store tiers, the others leave the column NULL
+	var tiers interface{}
+	if len(rule.Tiers) > 0 {
+		tiers = rule.Tiers
+	}
+
+	err := scanFeeRule(r.db.QueryRow(ctx, query,
+		rule.Name,
+		rule.Kind,
+		rule.Currency,
+		rule.FlatAmount,
+		rule.Rate,
+		rule.MinFee,
+		rule.MaxFee,
+		tiers,
+		rule.SourceAccountID,
+		rule.DestinationAccountID,
+		rule.Payer,
+		rule.RevenueAccountID,
+		rule.Priority,
+	), rule)
+	if err != nil {
+		return fmt.Errorf("failed to create fee rule: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the fee rules matching the filter in the order they are evaluated
+func (r *feeRuleRepository) List(ctx context.Context, filter *model.ListFeeRulesRequest) ([]*model.FeeRule, error) {
+	query := `
+		SELECT ` + feeRuleColumns + `
+		FROM fee_rules
+		WHERE ($1::VARCHAR = '' OR currency = $1)
+			AND ($2 OR active)
+		ORDER BY currency, priority, id
+	`
+
+	rows, err := r.db.Query(ctx, query, filter.Currency, filter.IncludeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fee rules: %w", err)
+	}
+
+	return collectFeeRules(rows)
+}
+
+// Deactivate stops a rule from matching new transfers. Rules are kept because settled
+// transfers refer to the rule that priced them.
+func (r *feeRuleRepository) Deactivate(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `UPDATE fee_rules SET active = FALSE WHERE id = $1 AND active`, id)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate fee rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("fee rule not found")
+	}
+
+	return nil
+}
+
+// ListMatching returns the active rules that apply to a transfer between the two accounts,
+// in the currency of the source, lowest priority first
+func (r *feeRuleRepository) ListMatching(ctx context.Context, tx pgx.Tx, sourceAccountID, destinationAccountID int64) ([]*model.FeeRule, error) {
+	query := `
+		SELECT ` + feeRuleColumns + `
+		FROM fee_rules
+		WHERE active
+			AND currency = (SELECT currency FROM accounts WHERE id = $1)
+			AND (source_account_id IS NULL OR source_account_id = $1)
+			AND (destination_account_id IS NULL OR destination_account_id = $2)
+		ORDER BY priority, id
+	`
+
+	rows, err := tx.Query(ctx, query, sourceAccountID, destinationAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list matching fee rules: %w", err)
+	}
+
+	return collectFeeRules(rows)
+}
+
+func collectFeeRules(rows pgx.Rows) ([]*model.FeeRule, error) {
+	defer rows.Close()
+
+	rules := make([]*model.FeeRule, 0)
+	for rows.Next() {
+		var rule model.FeeRule
+		if err := scanFeeRule(rows, &rule); err != nil {
+			return nil, fmt.Errorf("failed to scan fee rule: %w", err)
+		}
+		rules = append(rules, &rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating fee rules: %w", err)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/fx"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+type fxRepository struct {
+	db database.DB
+}
+
+func NewFXRepository(s *server.Server) FXRepository {
+	return &fxRepository{
+		db: s.DB,
+	}
+}
+
+// fxQuoteColumns is the column list every quote query selects, in scanFXQuote order
+const fxQuoteColumns = `id, source_currency, destination_currency, amount, converted_amount, rate, spread,
+	rate_timestamp, expires_at, transaction_id, created_at`
+
+// scanFXQuote scans a row selected with fxQuoteColumns
+func scanFXQuote(row pgx.Row, quote *model.FXQuote) error {
+	return row.Scan(
+		&quote.ID,
+		&quote.SourceCurrency,
+		&quote.DestinationCurrency,
+		&quote.Amount,
+		&quote.ConvertedAmount,
+		&quote.Rate,
+		&quote.Spread,
+		&quote.RateTimestamp,
+		&quote.ExpiresAt,
+		&quote.TransactionID,
+		&quote.CreatedAt,
+	)
+}
+
+// Rate returns the latest rate of the pair already in effect, which makes the rates table
+// an fx.RateProvider
+func (r *fxRepository) Rate(ctx context.Context, base, quote string) (*fx.Rate, error) {
+	query := `
+		SELECT base_currency, quote_currency, rate, effective_at
+		FROM fx_rates
+		WHERE base_currency = $1 AND quote_currency = $2 AND effective_at <= NOW()
+		ORDER BY effective_at DESC
+		LIMIT 1
+	`
+
+	var rate fx.Rate
+	err := r.db.QueryRow(ctx, query, base, quote).Scan(&rate.Base, &rate.Quote, &rate.Value, &rate.Timestamp)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fx.ErrRateNotFound
+		}
+		return nil, fmt.Errorf("failed to get fx rate: %w", err)
+	}
+
+	return &rate, nil
+}
+
+func (r *fxRepository) CreateQuote(ctx context.Context, quote *model.FXQuote) error {
+	query := `
+		INSERT INTO fx_quotes (source_currency, destination_currency, amount, converted_amount, rate, spread,
+			rate_timestamp, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		RETURNING ` + fxQuoteColumns
+
+	err := scanFXQuote(r.db.QueryRow(ctx, query,
+		quote.SourceCurrency,
+		quote.DestinationCurrency,
+		quote.Amount,
+		quote.ConvertedAmount,
+		quote.Rate,
+		quote.Spread,
+		quote.RateTimestamp,
+		quote.ExpiresAt,
+	), quote)
+	if err != nil {
+		return fmt.Errorf("failed to create fx quote: %w", err)
+	}
+
+	return nil
+}
+
+func (r *fxRepository) GetQuote(ctx context.Context, id int64) (*model.FXQuote, error) {
+	query := `
+		SELECT ` + fxQuoteColumns + `
+		FROM fx_quotes
+		WHERE id = $1
+	`
+
+	var quote model.FXQuote
+	err := scanFXQuote(r.db.QueryRow(ctx, query, id), &quote)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("fx quote not found")
+		}
+		return nil, fmt.Errorf("failed to get fx quote: %w", err)
+	}
+
+	return &quote, nil
+}
+
+// GetQuoteForUpdate locks a quote so it can only be executed once
+func (r *fxRepository) GetQuoteForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*model.FXQuote, error) {
+	query := `
+		SELECT ` + fxQuoteColumns + `
+		FROM fx_quotes
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var quote model.FXQuote
+	err := scanFXQuote(tx.QueryRow(ctx, query, id), &quote)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("fx quote not found")
+		}
+		return nil, fmt.Errorf("failed to get fx quote for update: %w", err)
+	}
+
+	return &quote, nil
+}
+
+// MarkQuoteUsed links a quote to the transaction that executed it
+func (r *fxRepository) MarkQuoteUsed(ctx context.Context, tx pgx.Tx, id, transactionID int64) error {
+	query := `
+		UPDATE fx_quotes
+		SET transaction_id = $2
+		WHERE id = $1 AND transaction_id IS NULL
+	`
+
+	result, err := tx.Exec(ctx, query, id, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to mark fx quote used: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("fx quote not found")
+	}
+
+	return nil
+}
+
+// AccrueRemainder adds a rounding remainder to what a P&L account is owed and returns the whole
+// units at precision decimals that are now due to it. The rest stays pending for later remainders.
+func (r *fxRepository) AccrueRemainder(ctx context.Context, tx pgx.Tx, accountID int64, remainder decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	query := `
+		INSERT INTO fx_remainders (account_id, pending, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (account_id) DO UPDATE
+		SET pending = fx_remainders.pending + EXCLUDED.pending, updated_at = NOW()
+		RETURNING pending
+	`
+
+	var pending decimal.Decimal
+	if err := tx.QueryRow(ctx, query, accountID, remainder).Scan(&pending); err != nil {
+		return decimal.Zero, fmt.Errorf("failed to accrue fx remainder: %w", err)
+	}
+
+	due := pending.RoundFloor(precision)
+	if !due.IsPositive() {
+		return decimal.Zero, nil
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE fx_remainders SET pending = pending - $2 WHERE account_id = $1`, accountID, due); err != nil {
+		return decimal.Zero, fmt.Errorf("failed to release fx remainder: %w", err)
+	}
+
+	return due, nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type holdRepository struct {
+	db database.DB
+}
+
+func NewHoldRepository(s *server.Server) HoldRepository {
+	return &holdRepository{
+		db: s.DB,
+	}
+}
+
+// holdColumns is the column list every hold query selects, in scanHold order
+const holdColumns = `id, account_id, amount, currency, captured_amount, status, reference, capture_transaction_id,
+	expires_at, released_at, created_at, updated_at`
+
+// scanHold scans a row selected with holdColumns
+func scanHold(row pgx.Row, hold *model.Hold) error {
+	return row.Scan(
+		&hold.ID,
+		&hold.AccountID,
+		&hold.Amount,
+		&hold.Currency,
+		&hold.CapturedAmount,
+		&hold.Status,
+		&hold.Reference,
+		&hold.CaptureTransactionID,
+		&hold.ExpiresAt,
+		&hold.ReleasedAt,
+		&hold.CreatedAt,
+		&hold.UpdatedAt,
+	)
+}
+
+// Create inserts an active hold in the currency of its account
+func (r *holdRepository) Create(ctx context.Context, tx pgx.Tx, hold *model.Hold) error {
+	query := `
+		INSERT INTO holds (account_id, amount, currency, status, reference, expires_at, created_at, updated_at)
+		VALUES ($1, $2, (SELECT currency FROM accounts WHERE id = $1), $3, $4, $5, NOW(), NOW())
+		RETURNING ` + holdColumns
+
+	err := scanHold(tx.QueryRow(ctx, query,
+		hold.AccountID,
+		hold.Amount,
+		model.HoldStatusActive,
+		hold.Reference,
+		hold.ExpiresAt,
+	), hold)
+	if err != nil {
+		return fmt.Errorf("failed to create hold: %w", err)
+	}
+
+	return nil
+}
+
+func (r *holdRepository) GetByID(ctx context.Context, id int64) (*model.Hold, error) {
+	query := `
+		SELECT ` + holdColumns + `
+		FROM holds
+		WHERE id = $1
+	`
+
+	var hold model.Hold
+	err := scanHold(r.db.QueryRow(ctx, query, id), &hold)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("hold not found")
+		}
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+
+	return &hold, nil
+}
+
+// GetByIDForUpdate uses row lock so a hold is captured, voided or expired only once
+func (r *holdRepository) GetByIDForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*model.Hold, error) {
+	query := `
+		SELECT ` + holdColumns + `
+		FROM holds
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var hold model.Hold
+	err := scanHold(tx.QueryRow(ctx, query, id), &hold)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("hold not found")
+		}
+		return nil, fmt.Errorf("failed to get hold for update: %w", err)
+	}
+
+	return &hold, nil
+}
+
+// ClaimExpired locks the next active hold past its expiry. Holds locked elsewhere are
+// skipped. Returns nil when none has expired.
+func (r *holdRepository) ClaimExpired(ctx context.Context, tx pgx.Tx) (*model.Hold, error) {
+	query := `
+		SELECT ` + holdColumns + `
+		FROM holds
+		WHERE status = $1 AND expires_at <= NOW()
+		ORDER BY expires_at, id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var hold model.Hold
+	err := scanHold(tx.QueryRow(ctx, query, model.HoldStatusActive), &hold)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim expired hold: %w", err)
+	}
+
+	return &hold, nil
+}
+
+// Release closes an active hold with its final status, captured amount and capture transaction
+func (r *holdRepository) Release(ctx context.Context, tx pgx.Tx, hold *model.Hold) error {
+	query := `
+		UPDATE holds
+		SET status = $2, captured_amount = $3, capture_transaction_id = $4, released_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = $5
+		RETURNING released_at, updated_at
+	`
+
+	err := tx.QueryRow(ctx, query,
+		hold.ID,
+		hold.Status,
+		hold.CapturedAmount,
+		hold.CaptureTransactionID,
+		model.HoldStatusActive,
+	).Scan(&hold.ReleasedAt, &hold.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("hold not found")
+		}
+		return fmt.Errorf("failed to release hold: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type idempotencyRepository struct {
+	db database.DB
+}
+
+func NewIdempotencyRepository(s *server.Server) IdempotencyRepository {
+	return &idempotencyRepository{
+		db: s.DB,
+	}
+}
+
+// Claim reserves the key for the calling transaction. Expired keys are reclaimed.
+// It returns false when an unexpired record already exists for the key.
+// A concurrent claim of the same key blocks until the first transaction finishes.
+func (r *idempotencyRepository) Claim(ctx context.Context, tx pgx.Tx, record *model.IdempotencyRecord) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (scope, idempotency_key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, NOW(), $4)
+		ON CONFLICT (scope, idempotency_key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			response_body = NULL,
+			error_code = NULL,
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= NOW()
+	`
+
+	result, err := tx.Exec(ctx, query, record.Scope, record.Key, record.RequestHash, record.ExpiresAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+func (r *idempotencyRepository) Get(ctx context.Context, tx pgx.Tx, scope, key string) (*model.IdempotencyRecord, error) {
+	query := `
+		SELECT scope, idempotency_key, request_hash, status_code, response_body, error_code, created_at, expires_at
+		FROM idempotency_keys
+		WHERE scope = $1 AND idempotency_key = $2
+	`
+
+	var record model.IdempotencyRecord
+	err := tx.QueryRow(ctx, query, scope, key).Scan(
+		&record.Scope,
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&record.ResponseBody,
+		&record.ErrorCode,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("idempotency key not found")
+		}
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	return &record, nil
+}
+
+// SaveOutcome stores the response of the request that claimed the key
+func (r *idempotencyRepository) SaveOutcome(ctx context.Context, tx pgx.Tx, record *model.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, response_body = $4, error_code = $5
+		WHERE scope = $1 AND idempotency_key = $2
+	`
+
+	result, err := tx.Exec(ctx, query, record.Scope, record.Key, record.StatusCode, record.ResponseBody, record.ErrorCode)
+	if err != nil {
+		return fmt.Errorf("failed to save idempotency outcome: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("idempotency key not found")
+	}
+
+	return nil
+}
+
+// DeleteExpired removes keys whose retention period has passed
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE expires_at <= NOW()
+	`
+
+	result, err := r.db.Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
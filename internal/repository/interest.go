@@ -0,0 +1,373 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+type interestRepository struct {
+	db database.DB
+}
+
+func NewInterestRepository(s *server.Server) InterestRepository {
+	return &interestRepository{
+		db: s.DB,
+	}
+}
+
+// interestProductColumns is the column list every interest product query selects, in scanInterestProduct order
+const interestProductColumns = `id, name, currency, annual_rate, day_count, expense_account_id, created_at`
+
+// scanInterestProduct scans a row selected with interestProductColumns
+func scanInterestProduct(row pgx.Row, product *model.InterestProduct) error {
+	return row.Scan(
+		&product.ID,
+		&product.Name,
+		&product.Currency,
+		&product.AnnualRate,
+		&product.DayCount,
+		&product.ExpenseAccountID,
+		&product.CreatedAt,
+	)
+}
+
+// interestAccrualColumns is the column list every accrual query selects, in scanInterestAccrual order
+const interestAccrualColumns = `id, account_id, product_id, business_date, balance, annual_rate, day_count, amount,
+	transaction_id, created_at`
+
+// scanInterestAccrual scans a row selected with interestAccrualColumns
+func scanInterestAccrual(row pgx.Row, accrual *model.InterestAccrual) error {
+	return row.Scan(
+		&accrual.ID,
+		&accrual.AccountID,
+		&accrual.ProductID,
+		&accrual.BusinessDate,
+		&accrual.Balance,
+		&accrual.AnnualRate,
+		&accrual.DayCount,
+		&accrual.Amount,
+		&accrual.TransactionID,
+		&accrual.CreatedAt,
+	)
+}
+
+func (r *interestRepository) CreateProduct(ctx context.Context, product *model.InterestProduct) error {
+	query := `
+		INSERT INTO interest_products (name, currency, annual_rate, day_count, expense_account_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING ` + interestProductColumns
+
+	err := scanInterestProduct(r.db.QueryRow(ctx, query,
+		product.Name,
+		product.Currency,
+		product.AnnualRate,
+		product.DayCount,
+		product.ExpenseAccountID,
+	), product)
+	if err != nil {
+		return fmt.Errorf("failed to create interest product: %w", err)
+	}
+
+	return nil
+}
+
+func (r *interestRepository) GetProduct(ctx context.Context, id int64) (*model.InterestProduct, error) {
+	query := `
+		SELECT ` + interestProductColumns + `
+		FROM interest_products
+		WHERE id = $1
+	`
+
+	var product model.InterestProduct
+	err := scanInterestProduct(r.db.QueryRow(ctx, query, id), &product)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("interest product not found")
+		}
+		return nil, fmt.Errorf("failed to get interest product: %w", err)
+	}
+
+	return &product, nil
+}
+
+func (r *interestRepository) ListProducts(ctx context.Context) ([]*model.InterestProduct, error) {
+	query := `
+		SELECT ` + interestProductColumns + `
+		FROM interest_products
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interest products: %w", err)
+	}
+	defer rows.Close()
+
+	products := make([]*model.InterestProduct, 0)
+	for rows.Next() {
+		var product model.InterestProduct
+		if err := scanInterestProduct(rows, &product); err != nil {
+			return nil, fmt.Errorf("failed to scan interest product: %w", err)
+		}
+		products = append(products, &product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating interest products: %w", err)
+	}
+
+	return products, nil
+}
+
+// ListCandidates returns every open account with an interest product that existed before the
+// end of the business date, with the balance of its last posting before then
+func (r *interestRepository) ListCandidates(ctx context.Context, endOfDay time.Time) ([]*model.InterestCandidate, error) {
+	query := `
+		SELECT a.id, COALESCE(le.balance_after, 0),
+			p.id, p.name, p.currency, p.annual_rate, p.day_count, p.expense_account_id, p.created_at
+		FROM accounts a
+		JOIN interest_products p ON p.id = a.interest_product_id
+		LEFT JOIN LATERAL (
+			SELECT balance_after
+			FROM ledger_entries
+			WHERE account_id = a.id AND created_at < $1
+			ORDER BY id DESC
+			LIMIT 1
+		) le ON TRUE
+		WHERE a.status <> $2 AND a.created_at < $1
+		ORDER BY a.id
+	`
+
+	rows, err := r.db.Query(ctx, query, endOfDay, model.AccountStatusClosed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interest candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []*model.InterestCandidate
+	for rows.Next() {
+		var candidate model.InterestCandidate
+		err := rows.Scan(
+			&candidate.AccountID,
+			&candidate.Balance,
+			&candidate.Product.ID,
+			&candidate.Product.Name,
+			&candidate.Product.Currency,
+			&candidate.Product.AnnualRate,
+			&candidate.Product.DayCount,
+			&candidate.Product.ExpenseAccountID,
+			&candidate.Product.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan interest candidate: %w", err)
+		}
+		candidates = append(candidates, &candidate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating interest candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// CreateAccrual records an accrual unless the account already has one for the business date.
+// It reports whether the accrual was created.
+func (r *interestRepository) CreateAccrual(ctx context.Context, accrual *model.InterestAccrual) (bool, error) {
+	query := `
+		INSERT INTO interest_accruals (account_id, product_id, business_date, balance, annual_rate, day_count, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (account_id, business_date) DO NOTHING
+		RETURNING ` + interestAccrualColumns
+
+	err := scanInterestAccrual(r.db.QueryRow(ctx, query,
+		accrual.AccountID,
+		accrual.ProductID,
+		accrual.BusinessDate,
+		accrual.Balance,
+		accrual.AnnualRate,
+		accrual.DayCount,
+		accrual.Amount,
+	), accrual)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create interest accrual: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetLastAccrualDate returns the latest business date any account accrued interest for, or nil
+// when nothing has accrued yet
+func (r *interestRepository) GetLastAccrualDate(ctx context.Context) (*time.Time, error) {
+	query := `SELECT MAX(business_date) FROM interest_accruals`
+
+	var businessDate *time.Time
+	if err := r.db.QueryRow(ctx, query).Scan(&businessDate); err != nil {
+		return nil, fmt.Errorf("failed to get last accrual date: %w", err)
+	}
+
+	return businessDate, nil
+}
+
+// ListCapitalizable returns the open accounts with unposted accruals before the given date
+func (r *interestRepository) ListCapitalizable(ctx context.Context, before time.Time) ([]int64, error) {
+	query := `
+		SELECT DISTINCT ia.account_id
+		FROM interest_accruals ia
+		JOIN accounts a ON a.id = ia.account_id
+		WHERE ia.transaction_id IS NULL AND ia.business_date < $1 AND a.status <> $2
+		ORDER BY ia.account_id
+	`
+
+	rows, err := r.db.Query(ctx, query, before, model.AccountStatusClosed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list capitalizable accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accountIDs []int64
+	for rows.Next() {
+		var accountID int64
+		if err := rows.Scan(&accountID); err != nil {
+			return nil, fmt.Errorf("failed to scan account id: %w", err)
+		}
+		accountIDs = append(accountIDs, accountID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating capitalizable accounts: %w", err)
+	}
+
+	return accountIDs, nil
+}
+
+// LockUnposted locks the unposted accruals of an account before the given date, so two
+// capitalization runs cannot pay them twice
+func (r *interestRepository) LockUnposted(ctx context.Context, tx pgx.Tx, accountID int64, before time.Time) ([]*model.InterestAccrual, error) {
+	query := `
+		SELECT ` + interestAccrualColumns + `
+		FROM interest_accruals
+		WHERE account_id = $1 AND transaction_id IS NULL AND business_date < $2
+		ORDER BY business_date
+		FOR UPDATE
+	`
+
+	rows, err := tx.Query(ctx, query, accountID, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock unposted accruals: %w", err)
+	}
+
+	return collectInterestAccruals(rows)
+}
+
+// MarkPosted links accruals to the interest transaction that capitalized them
+func (r *interestRepository) MarkPosted(ctx context.Context, tx pgx.Tx, ids []int64, transactionID int64) error {
+	query := `
+		UPDATE interest_accruals
+		SET transaction_id = $2
+		WHERE id = ANY($1) AND transaction_id IS NULL
+	`
+
+	result, err := tx.Exec(ctx, query, ids, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to mark accruals posted: %w", err)
+	}
+
+	if result.RowsAffected() != int64(len(ids)) {
+		return fmt.Errorf("marked %d of %d accruals posted", result.RowsAffected(), len(ids))
+	}
+
+	return nil
+}
+
+// AccrueCarry adds capitalized interest to what an account is carrying and returns the whole
+// units at precision decimals that are now due to it. The rest stays carried to the next run.
+func (r *interestRepository) AccrueCarry(ctx context.Context, tx pgx.Tx, accountID int64, amount decimal.Decimal, precision int32) (decimal.Decimal, error) {
+	query := `
+		INSERT INTO interest_carries (account_id, pending, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (account_id) DO UPDATE
+		SET pending = interest_carries.pending + EXCLUDED.pending, updated_at = NOW()
+		RETURNING pending
+	`
+
+	var pending decimal.Decimal
+	if err := tx.QueryRow(ctx, query, accountID, amount).Scan(&pending); err != nil {
+		return decimal.Zero, fmt.Errorf("failed to accrue interest carry: %w", err)
+	}
+
+	due := pending.RoundFloor(precision)
+	if !due.IsPositive() {
+		return decimal.Zero, nil
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE interest_carries SET pending = pending - $2 WHERE account_id = $1`, accountID, due); err != nil {
+		return decimal.Zero, fmt.Errorf("failed to release interest carry: %w", err)
+	}
+
+	return due, nil
+}
+
+// GetCarry returns the capitalized interest an account carries below a whole minor unit
+func (r *interestRepository) GetCarry(ctx context.Context, accountID int64) (decimal.Decimal, error) {
+	query := `SELECT pending FROM interest_carries WHERE account_id = $1`
+
+	var pending decimal.Decimal
+	if err := r.db.QueryRow(ctx, query, accountID).Scan(&pending); err != nil {
+		if err == pgx.ErrNoRows {
+			return decimal.Zero, nil
+		}
+		return decimal.Zero, fmt.Errorf("failed to get interest carry: %w", err)
+	}
+
+	return pending, nil
+}
+
+// ListAccruals returns the accruals of an account between two business dates, both included
+// when set, oldest first
+func (r *interestRepository) ListAccruals(ctx context.Context, accountID int64, from, to *time.Time) ([]*model.InterestAccrual, error) {
+	query := `
+		SELECT ` + interestAccrualColumns + `
+		FROM interest_accruals
+		WHERE account_id = $1
+			AND ($2::DATE IS NULL OR business_date >= $2)
+			AND ($3::DATE IS NULL OR business_date <= $3)
+		ORDER BY business_date
+	`
+
+	rows, err := r.db.Query(ctx, query, accountID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interest accruals: %w", err)
+	}
+
+	return collectInterestAccruals(rows)
+}
+
+func collectInterestAccruals(rows pgx.Rows) ([]*model.InterestAccrual, error) {
+	defer rows.Close()
+
+	accruals := make([]*model.InterestAccrual, 0)
+	for rows.Next() {
+		var accrual model.InterestAccrual
+		if err := scanInterestAccrual(rows, &accrual); err != nil {
+			return nil, fmt.Errorf("failed to scan interest accrual: %w", err)
+		}
+		accruals = append(accruals, &accrual)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating interest accruals: %w", err)
+	}
+
+	return accruals, nil
+}
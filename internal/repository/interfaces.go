@@ -2,23 +2,189 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/chandra-shekhar/internal-transfers/internal/event"
+	"github.com/chandra-shekhar/internal-transfers/internal/fx"
 	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/velocity"
 	"github.com/jackc/pgx/v5"
 	"github.com/shopspring/decimal"
 )
 
 // AccountRepository defines the interface for account-related database operations
 type AccountRepository interface {
-	Create(ctx context.Context, accountID int64, initialBalance decimal.Decimal) (*model.Account, error)
+	Create(ctx context.Context, tx pgx.Tx, accountID int64, currency string) (*model.Account, error)
 	GetByID(ctx context.Context, id int64) (*model.Account, error)
 	GetByIDForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*model.Account, error)
-	UpdateBalance(ctx context.Context, tx pgx.Tx, id int64, newBalance decimal.Decimal) error
+	GetByIDsForUpdate(ctx context.Context, tx pgx.Tx, ids []int64) ([]*model.Account, error)
+	AdjustHeld(ctx context.Context, tx pgx.Tx, accountID int64, delta decimal.Decimal) error
+	UpdateLimits(ctx context.Context, tx pgx.Tx, accountID int64, overdraftLimit decimal.Decimal, maxBalance *decimal.Decimal) (*model.Account, error)
+	UpdateStatus(ctx context.Context, tx pgx.Tx, accountID int64, status model.AccountStatus, reason *string) (*model.Account, error)
+	UpdateGroup(ctx context.Context, accountID int64, group *string, expectedVersion *int64) (*model.Account, error)
+	UpdateInterestProduct(ctx context.Context, accountID int64, productID *int64, expectedVersion *int64) (*model.Account, error)
+}
+
+// AccountLimitRepository defines the interface for the audit trail of account limit changes
+type AccountLimitRepository interface {
+	RecordChange(ctx context.Context, tx pgx.Tx, change *model.AccountLimitChange) error
+	ListChanges(ctx context.Context, accountID int64) ([]*model.AccountLimitChange, error)
 }
 
 // TransactionRepository defines the interface for transaction-related database operations
 type TransactionRepository interface {
 	Create(ctx context.Context, tx pgx.Tx, transaction *model.Transaction) error
 	UpdateStatus(ctx context.Context, tx pgx.Tx, id int64, status model.TransactionStatus) error
+	MarkFailed(ctx context.Context, tx pgx.Tx, id int64, code, reason string) error
+	RecordConversion(ctx context.Context, tx pgx.Tx, id int64, conversion *model.FXConversion) error
+	RecordFee(ctx context.Context, tx pgx.Tx, id int64, fee *model.TransactionFee) error
 	GetByID(ctx context.Context, id int64) (*model.Transaction, error)
+	GetByIDForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*model.Transaction, error)
+	AddReversedAmount(ctx context.Context, tx pgx.Tx, id int64, amount decimal.Decimal) (*model.Transaction, error)
+	ListByAccount(ctx context.Context, filter *model.AccountTransactionFilter) ([]*model.AccountTransaction, error)
+	ListPostings(ctx context.Context, accountID, afterEntryID int64, limit int) ([]*model.AccountPosting, error)
+	ClaimDueScheduled(ctx context.Context, tx pgx.Tx) (*model.Transaction, error)
+	ClaimPending(ctx context.Context, tx pgx.Tx) (*model.Transaction, error)
+	ListScheduled(ctx context.Context, filter *model.ScheduledTransactionFilter) ([]*model.Transaction, error)
+	Search(ctx context.Context, filter *model.TransactionSearchFilter) ([]*model.Transaction, error)
+}
+
+// LedgerRepository defines the interface for ledger postings, the only way balances change
+type LedgerRepository interface {
+	Post(ctx context.Context, tx pgx.Tx, entry *model.LedgerEntry) error
+	RecordCorrection(ctx context.Context, tx pgx.Tx, entry *model.LedgerEntry) error
+	Notify(ctx context.Context, tx pgx.Tx, entries ...*model.LedgerEntry) error
+	ListStatementEntries(ctx context.Context, tx pgx.Tx, filter *model.StatementEntryFilter) ([]*model.StatementEntry, error)
+	GetLastEntryID(ctx context.Context, accountID int64) (int64, error)
+}
+
+// ReconciliationRepository defines the interface for the aggregate reads behind balance reconciliation
+type ReconciliationRepository interface {
+	SumBalances(ctx context.Context) (int64, map[string]decimal.Decimal, error)
+	SumFunding(ctx context.Context) (map[string]decimal.Decimal, error)
+	GetBalancesBelowOverdraft(ctx context.Context) ([]*model.Account, error)
+	GetCompletedWithoutTimestamp(ctx context.Context) ([]int64, error)
+	HasLedgerPostings(ctx context.Context) (bool, error)
+	GetLedgerDrift(ctx context.Context) ([]*model.LedgerDrift, error)
+	GetLedgerBalance(ctx context.Context, tx pgx.Tx, accountID int64) (decimal.Decimal, error)
+}
+
+// IdempotencyRepository defines the interface for idempotency key storage
+type IdempotencyRepository interface {
+	Claim(ctx context.Context, tx pgx.Tx, record *model.IdempotencyRecord) (bool, error)
+	Get(ctx context.Context, tx pgx.Tx, scope, key string) (*model.IdempotencyRecord, error)
+	SaveOutcome(ctx context.Context, tx pgx.Tx, record *model.IdempotencyRecord) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// StandingOrderRepository defines the interface for standing order database operations
+type StandingOrderRepository interface {
+	Create(ctx context.Context, tx pgx.Tx, order *model.StandingOrder) error
+	GetByID(ctx context.Context, id int64) (*model.StandingOrder, error)
+	GetByIDForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*model.StandingOrder, error)
+	ListBySourceAccount(ctx context.Context, accountID int64, after *model.KeysetCursor, limit int) ([]*model.StandingOrder, error)
+	ClaimDue(ctx context.Context, tx pgx.Tx) (*model.StandingOrder, error)
+	UpdateState(ctx context.Context, tx pgx.Tx, order *model.StandingOrder) error
+}
+
+// HoldRepository defines the interface for hold database operations
+type HoldRepository interface {
+	Create(ctx context.Context, tx pgx.Tx, hold *model.Hold) error
+	GetByID(ctx context.Context, id int64) (*model.Hold, error)
+	GetByIDForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*model.Hold, error)
+	ClaimExpired(ctx context.Context, tx pgx.Tx) (*model.Hold, error)
+	Release(ctx context.Context, tx pgx.Tx, hold *model.Hold) error
+}
+
+// FXRepository defines the interface for exchange rates and quotes. Its Rate method makes the
+// rates table an fx.RateProvider.
+type FXRepository interface {
+	Rate(ctx context.Context, base, quote string) (*fx.Rate, error)
+	CreateQuote(ctx context.Context, quote *model.FXQuote) error
+	GetQuote(ctx context.Context, id int64) (*model.FXQuote, error)
+	GetQuoteForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*model.FXQuote, error)
+	MarkQuoteUsed(ctx context.Context, tx pgx.Tx, id, transactionID int64) error
+	AccrueRemainder(ctx context.Context, tx pgx.Tx, accountID int64, remainder decimal.Decimal, precision int32) (decimal.Decimal, error)
+}
+
+// VelocityLimitRepository defines the interface for velocity limits and the outgoing transfers
+// they are checked against
+type VelocityLimitRepository interface {
+	Create(ctx context.Context, limit *model.VelocityLimit) error
+	List(ctx context.Context, filter *model.ListVelocityLimitsRequest) ([]*model.VelocityLimit, error)
+	Delete(ctx context.Context, id int64) error
+	LockApplicable(ctx context.Context, tx pgx.Tx, accountIDs []int64, groups []string) ([]*model.VelocityLimit, error)
+	ListOutgoingByAccount(ctx context.Context, tx pgx.Tx, accountID int64, currency string, since time.Time) ([]velocity.Movement, error)
+	ListOutgoingByGroup(ctx context.Context, tx pgx.Tx, group string, currency string, since time.Time) ([]velocity.Movement, error)
+}
+
+// FeeRuleRepository defines the interface for the fee rules transfers are priced with
+type FeeRuleRepository interface {
+	Create(ctx context.Context, rule *model.FeeRule) error
+	List(ctx context.Context, filter *model.ListFeeRulesRequest) ([]*model.FeeRule, error)
+	Deactivate(ctx context.Context, id int64) error
+	ListMatching(ctx context.Context, tx pgx.Tx, sourceAccountID, destinationAccountID int64) ([]*model.FeeRule, error)
+}
+
+// InterestRepository defines the interface for interest products and the daily accruals they earn
+type InterestRepository interface {
+	CreateProduct(ctx context.Context, product *model.InterestProduct) error
+	GetProduct(ctx context.Context, id int64) (*model.InterestProduct, error)
+	ListProducts(ctx context.Context) ([]*model.InterestProduct, error)
+	ListCandidates(ctx context.Context, endOfDay time.Time) ([]*model.InterestCandidate, error)
+	CreateAccrual(ctx context.Context, accrual *model.InterestAccrual) (bool, error)
+	GetLastAccrualDate(ctx context.Context) (*time.Time, error)
+	ListCapitalizable(ctx context.Context, before time.Time) ([]int64, error)
+	LockUnposted(ctx context.Context, tx pgx.Tx, accountID int64, before time.Time) ([]*model.InterestAccrual, error)
+	MarkPosted(ctx context.Context, tx pgx.Tx, ids []int64, transactionID int64) error
+	AccrueCarry(ctx context.Context, tx pgx.Tx, accountID int64, amount decimal.Decimal, precision int32) (decimal.Decimal, error)
+	GetCarry(ctx context.Context, accountID int64) (decimal.Decimal, error)
+	ListAccruals(ctx context.Context, accountID int64, from, to *time.Time) ([]*model.InterestAccrual, error)
+}
+
+// OutboxRepository defines the interface for the outbox events are recorded in and relayed from
+type OutboxRepository interface {
+	Insert(ctx context.Context, tx pgx.Tx, e *event.Event) error
+	LockUnpublished(ctx context.Context, tx pgx.Tx, limit int) ([]*event.Event, error)
+	MarkPublished(ctx context.Context, tx pgx.Tx, ids []int64) error
+}
+
+// EventFeedRepository defines the interface for the ordered feed of published events and the
+// offsets its consumer groups commit
+type EventFeedRepository interface {
+	Append(ctx context.Context, tx pgx.Tx, eventIDs []int64) error
+	ListAfter(ctx context.Context, position int64, limit int) ([]*model.FeedEvent, error)
+	GetLastPosition(ctx context.Context) (int64, error)
+	GetConsumerGroup(ctx context.Context, name string) (*model.ConsumerGroup, error)
+	ListConsumerGroups(ctx context.Context) ([]*model.ConsumerGroup, error)
+	CommitOffset(ctx context.Context, name string, position int64) (*model.ConsumerGroup, error)
+	DeleteConsumerGroup(ctx context.Context, name string) error
+}
+
+// BalanceSnapshotRepository defines the interface for end-of-day balances and the historical
+// balances read from them
+type BalanceSnapshotRepository interface {
+	GetLastRunDate(ctx context.Context) (*time.Time, error)
+	GetFirstPostingTime(ctx context.Context) (*time.Time, error)
+	TakeDay(ctx context.Context, tx pgx.Tx, date time.Time) (int64, error)
+	GetBalanceAsOf(ctx context.Context, accountID int64, asOf time.Time) (decimal.Decimal, error)
+	GetBalanceBefore(ctx context.Context, tx pgx.Tx, accountID int64, before time.Time) (decimal.Decimal, error)
+}
+
+// WebhookRepository defines the interface for webhooks, their deliveries and dead letters
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *model.Webhook) error
+	GetByID(ctx context.Context, id int64) (*model.Webhook, error)
+	List(ctx context.Context) ([]*model.Webhook, error)
+	Update(ctx context.Context, webhook *model.Webhook) error
+	RotateSecret(ctx context.Context, id int64, secret string, previousExpiresAt time.Time) (*model.Webhook, error)
+	Delete(ctx context.Context, id int64) error
+	Enqueue(ctx context.Context, tx pgx.Tx, eventID int64, eventType string, payload []byte) (int64, error)
+	ClaimDue(ctx context.Context, limit int, leaseUntil time.Time) ([]*model.WebhookTarget, error)
+	MarkDelivered(ctx context.Context, id int64, statusCode int) error
+	ScheduleRetry(ctx context.Context, id int64, statusCode *int, lastError string, nextAttemptAt time.Time) error
+	DeadLetter(ctx context.Context, id int64, statusCode *int, lastError string) (*model.WebhookDeadLetter, error)
+	ListDeadLetters(ctx context.Context, filter *model.WebhookDeadLetterFilter) ([]*model.WebhookDeadLetter, error)
+	GetDeadLetterForUpdate(ctx context.Context, tx pgx.Tx, webhookID, id int64) (*model.WebhookDeadLetter, error)
+	Replay(ctx context.Context, tx pgx.Tx, deadLetter *model.WebhookDeadLetter) (*model.WebhookDelivery, error)
 }
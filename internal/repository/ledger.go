@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type ledgerRepository struct {
+	db database.DB
+}
+
+func NewLedgerRepository(s *server.Server) LedgerRepository {
+	return &ledgerRepository{
+		db: s.DB,
+	}
+}
+
+// Post applies an entry to the account balance and records it in one statement.
+// It is the only way an account balance changes.
+func (r *ledgerRepository) Post(ctx context.Context, tx pgx.Tx, entry *model.LedgerEntry) error {
+	query := `
+		WITH updated AS (
+			UPDATE accounts
+			SET balance = balance + $3
+			WHERE id = $1
+			RETURNING id, balance
+		)
+		INSERT INTO ledger_entries (account_id, transaction_id, direction, entry_type, amount, balance_after, created_at)
+		SELECT id, $2, $4, $5, $6, balance, NOW()
+		FROM updated
+		RETURNING id, balance_after, created_at
+	`
+
+	err := tx.QueryRow(ctx, query,
+		entry.AccountID,
+		entry.TransactionID,
+		entry.SignedAmount(),
+		entry.Direction,
+		entry.EntryType,
+		entry.Amount,
+	).Scan(
+		&entry.ID,
+		&entry.BalanceAfter,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("account not found")
+		}
+		// Return the error directly so it can be checked for constraint violations
+		return err
+	}
+
+	return nil
+}
+
+// RecordCorrection records an entry for a balance that already changed outside the ledger.
+// The account balance is left untouched, so the entry only brings the ledger back in line.
+func (r *ledgerRepository) RecordCorrection(ctx context.Context, tx pgx.Tx, entry *model.LedgerEntry) error {
+	query := `
+		INSERT INTO ledger_entries (account_id, transaction_id, direction, entry_type, amount, balance_after, created_at)
+		SELECT id, $2, $3, $4, $5, balance, NOW()
+		FROM accounts
+		WHERE id = $1
+		RETURNING id, balance_after, created_at
+	`
+
+	err := tx.QueryRow(ctx, query,
+		entry.AccountID,
+		entry.TransactionID,
+		entry.Direction,
+		entry.EntryType,
+		entry.Amount,
+	).Scan(
+		&entry.ID,
+		&entry.BalanceAfter,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("account not found")
+		}
+		return fmt.Errorf("failed to record ledger correction: %w", err)
+	}
+
+	return nil
+}
+
+// Notify announces entries on the ledger channel. Postgres holds the notifications back until
+// the transaction commits and drops them if it rolls back.
+func (r *ledgerRepository) Notify(ctx context.Context, tx pgx.Tx, entries ...*model.LedgerEntry) error {
+	payloads := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		payload, err := json.Marshal(model.LedgerNotification{AccountID: entry.AccountID, EntryID: entry.ID})
+		if err != nil {
+			return fmt.Errorf("failed to encode ledger notification: %w", err)
+		}
+		payloads = append(payloads, string(payload))
+	}
+
+	query := `SELECT pg_notify($1, payload) FROM unnest($2::text[]) AS payload`
+
+	rows, err := tx.Query(ctx, query, model.LedgerChannel, payloads)
+	if err != nil {
+		return fmt.Errorf("failed to notify ledger postings: %w", err)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to notify ledger postings: %w", err)
+	}
+
+	return nil
+}
+
+// GetLastEntryID returns the latest ledger entry of an account, or 0 when it has none
+func (r *ledgerRepository) GetLastEntryID(ctx context.Context, accountID int64) (int64, error) {
+	query := `SELECT COALESCE(MAX(id), 0) FROM ledger_entries WHERE account_id = $1`
+
+	var id int64
+	if err := r.db.QueryRow(ctx, query, accountID).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to get last ledger entry: %w", err)
+	}
+
+	return id, nil
+}
+
+// ListStatementEntries returns a page of the account's ledger entries in the period, in posting
+// order by (created_at, id), with the details of the transactions they posted
+func (r *ledgerRepository) ListStatementEntries(ctx context.Context, tx pgx.Tx, filter *model.StatementEntryFilter) ([]*model.StatementEntry, error) {
+	args := []interface{}{filter.AccountID, filter.From, filter.To}
+	after := ""
+	if filter.After != nil {
+		args = append(args, filter.After.Timestamp, filter.After.ID)
+		after = "AND (le.created_at, le.id) > ($4, $5)"
+	}
+	args = append(args, filter.Limit)
+
+	query := `
+		SELECT le.id, le.account_id, le.transaction_id, le.direction, le.entry_type, le.amount, le.balance_after, le.created_at,
+			t.source_account_id, t.destination_account_id, t.reference, t.description
+		FROM ledger_entries le
+		LEFT JOIN transactions t ON t.id = le.transaction_id
+		WHERE le.account_id = $1 AND le.created_at >= $2 AND le.created_at < $3 ` + after + `
+		ORDER BY le.created_at, le.id
+		LIMIT ` + fmt.Sprintf("$%d", len(args))
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*model.StatementEntry, 0)
+	for rows.Next() {
+		var entry model.StatementEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.AccountID,
+			&entry.TransactionID,
+			&entry.Direction,
+			&entry.EntryType,
+			&entry.Amount,
+			&entry.BalanceAfter,
+			&entry.CreatedAt,
+			&entry.SourceAccountID,
+			&entry.DestinationAccountID,
+			&entry.Reference,
+			&entry.Description,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan statement entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating statement entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/event"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type outboxRepository struct {
+	db database.DB
+}
+
+func NewOutboxRepository(s *server.Server) OutboxRepository {
+	return &outboxRepository{
+		db: s.DB,
+	}
+}
+
+// outboxColumns is the column list every outbox query selects, in scanEvent order
+const outboxColumns = `id, event_type, aggregate_type, aggregate_id, payload, created_at`
+
+// scanEvent scans a row selected with outboxColumns
+func scanEvent(row pgx.Row, e *event.Event) error {
+	return row.Scan(
+		&e.ID,
+		&e.Type,
+		&e.AggregateType,
+		&e.AggregateID,
+		&e.Data,
+		&e.CreatedAt,
+	)
+}
+
+// collectEvents scans every row selected with outboxColumns
+func collectEvents(rows pgx.Rows) ([]*event.Event, error) {
+	defer rows.Close()
+
+	events := make([]*event.Event, 0)
+	for rows.Next() {
+		var e event.Event
+		if err := scanEvent(rows, &e); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		events = append(events, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return events, nil
+}
+
+// Insert records an event inside the transaction that made the change it describes
+func (r *outboxRepository) Insert(ctx context.Context, tx pgx.Tx, e *event.Event) error {
+	query := `
+		INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING ` + outboxColumns
+
+	err := scanEvent(tx.QueryRow(ctx, query, e.Type, e.AggregateType, e.AggregateID, e.Data), e)
+	if err != nil {
+		return fmt.Errorf("failed to insert event: %w", err)
+	}
+
+	return nil
+}
+
+// LockUnpublished locks up to limit unpublished events in ID order. A second relay waits for
+// the first to commit instead of skipping ahead, so events are published in order.
+func (r *outboxRepository) LockUnpublished(ctx context.Context, tx pgx.Tx, limit int) ([]*event.Event, error) {
+	query := `
+		SELECT ` + outboxColumns + `
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE
+	`
+
+	rows, err := tx.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unpublished events: %w", err)
+	}
+
+	return collectEvents(rows)
+}
+
+// MarkPublished stamps the events as published
+func (r *outboxRepository) MarkPublished(ctx context.Context, tx pgx.Tx, ids []int64) error {
+	query := `
+		UPDATE outbox
+		SET published_at = NOW()
+		WHERE id = ANY($1)
+	`
+
+	if _, err := tx.Exec(ctx, query, ids); err != nil {
+		return fmt.Errorf("failed to mark events published: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+type reconciliationRepository struct {
+	db database.DB
+}
+
+func NewReconciliationRepository(s *server.Server) ReconciliationRepository {
+	return &reconciliationRepository{
+		db: s.DB,
+	}
+}
+
+// SumBalances returns the number of accounts and the sum of their balances per currency
+func (r *reconciliationRepository) SumBalances(ctx context.Context) (int64, map[string]decimal.Decimal, error) {
+	query := `
+		SELECT currency, COUNT(*), SUM(balance)
+		FROM accounts
+		GROUP BY currency
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to sum balances: %w", err)
+	}
+	defer rows.Close()
+
+	var count int64
+	totals := make(map[string]decimal.Decimal)
+	for rows.Next() {
+		var code string
+		var accounts int64
+		var total decimal.Decimal
+		if err := rows.Scan(&code, &accounts, &total); err != nil {
+			return 0, nil, fmt.Errorf("failed to scan balance sum: %w", err)
+		}
+		count += accounts
+		totals[code] = total
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("error iterating balance sums: %w", err)
+	}
+
+	return count, totals, nil
+}
+
+// SumFunding returns, per currency, the money brought into the system by opening postings,
+// adjusted by FX postings, which move money out of one currency and into another
+func (r *reconciliationRepository) SumFunding(ctx context.Context) (map[string]decimal.Decimal, error) {
+	query := `
+		SELECT a.currency, SUM(CASE WHEN le.direction = 'credit' THEN le.amount ELSE -le.amount END)
+		FROM ledger_entries le
+		JOIN accounts a ON a.id = le.account_id
+		WHERE le.entry_type IN ($1, $2)
+		GROUP BY a.currency
+	`
+
+	rows, err := r.db.Query(ctx, query, model.LedgerEntryTypeOpening, model.LedgerEntryTypeFX)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum funding: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]decimal.Decimal)
+	for rows.Next() {
+		var code string
+		var total decimal.Decimal
+		if err := rows.Scan(&code, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan funding sum: %w", err)
+		}
+		totals[code] = total
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating funding sums: %w", err)
+	}
+
+	return totals, nil
+}
+
+// GetBalancesBelowOverdraft returns the accounts whose balance is below their overdraft limit
+func (r *reconciliationRepository) GetBalancesBelowOverdraft(ctx context.Context) ([]*model.Account, error) {
+	query := `
+		SELECT ` + accountColumns + `
+		FROM accounts
+		WHERE balance < -overdraft_limit
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balances below overdraft: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*model.Account
+	for rows.Next() {
+		var account model.Account
+		if err := scanAccount(rows, &account); err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, &account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// GetCompletedWithoutTimestamp returns completed transactions missing completed_at
+func (r *reconciliationRepository) GetCompletedWithoutTimestamp(ctx context.Context) ([]int64, error) {
+	query := `
+		SELECT id
+		FROM transactions
+		WHERE status = $1 AND completed_at IS NULL
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query, model.TransactionStatusCompleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completed transactions: %w", err)
+	}
+	defer rows.Close()
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan transaction ids: %w", err)
+	}
+
+	return ids, nil
+}
+
+// HasLedgerPostings reports whether the ledger holds any entries
+func (r *reconciliationRepository) HasLedgerPostings(ctx context.Context) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM ledger_entries)`
+
+	var exists bool
+	if err := r.db.QueryRow(ctx, query).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check ledger postings: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetLedgerDrift returns accounts whose balance differs from the sum of their postings
+func (r *reconciliationRepository) GetLedgerDrift(ctx context.Context) ([]*model.LedgerDrift, error) {
+	query := `
+		SELECT a.id, a.balance, COALESCE(l.posted, 0)
+		FROM accounts a
+		LEFT JOIN (
+			SELECT account_id, SUM(CASE WHEN direction = 'credit' THEN amount ELSE -amount END) AS posted
+			FROM ledger_entries
+			GROUP BY account_id
+		) l ON l.account_id = a.id
+		WHERE a.balance <> COALESCE(l.posted, 0)
+		ORDER BY a.id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger drift: %w", err)
+	}
+	defer rows.Close()
+
+	var drifts []*model.LedgerDrift
+	for rows.Next() {
+		var drift model.LedgerDrift
+		if err := rows.Scan(&drift.AccountID, &drift.Balance, &drift.LedgerBalance); err != nil {
+			return nil, fmt.Errorf("failed to scan ledger drift: %w", err)
+		}
+		drifts = append(drifts, &drift)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ledger drift: %w", err)
+	}
+
+	return drifts, nil
+}
+
+// GetLedgerBalance sums the postings of an account inside tx
+func (r *reconciliationRepository) GetLedgerBalance(ctx context.Context, tx pgx.Tx, accountID int64) (decimal.Decimal, error) {
+	query := `
+		SELECT COALESCE(SUM(CASE WHEN direction = 'credit' THEN amount ELSE -amount END), 0)
+		FROM ledger_entries
+		WHERE account_id = $1
+	`
+
+	var total decimal.Decimal
+	if err := tx.QueryRow(ctx, query, accountID).Scan(&total); err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get ledger balance: %w", err)
+	}
+
+	return total, nil
+}
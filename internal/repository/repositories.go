@@ -5,13 +5,41 @@ import (
 )
 
 type Repositories struct {
-	Account     AccountRepository
-	Transaction TransactionRepository
+	Account        AccountRepository
+	AccountLimit   AccountLimitRepository
+	Transaction    TransactionRepository
+	Ledger         LedgerRepository
+	Idempotency    IdempotencyRepository
+	Reconciliation ReconciliationRepository
+	StandingOrder  StandingOrderRepository
+	Hold           HoldRepository
+	FX             FXRepository
+	VelocityLimit  VelocityLimitRepository
+	FeeRule        FeeRuleRepository
+	Interest       InterestRepository
+	Outbox         OutboxRepository
+	EventFeed      EventFeedRepository
+	Snapshot       BalanceSnapshotRepository
+	Webhook        WebhookRepository
 }
 
 func NewRepositories(s *server.Server) *Repositories {
 	return &Repositories{
-		Account:     NewAccountRepository(s),
-		Transaction: NewTransactionRepository(s),
+		Account:        NewAccountRepository(s),
+		AccountLimit:   NewAccountLimitRepository(s),
+		Transaction:    NewTransactionRepository(s),
+		Ledger:         NewLedgerRepository(s),
+		Idempotency:    NewIdempotencyRepository(s),
+		Reconciliation: NewReconciliationRepository(s),
+		StandingOrder:  NewStandingOrderRepository(s),
+		Hold:           NewHoldRepository(s),
+		FX:             NewFXRepository(s),
+		VelocityLimit:  NewVelocityLimitRepository(s),
+		FeeRule:        NewFeeRuleRepository(s),
+		Interest:       NewInterestRepository(s),
+		Outbox:         NewOutboxRepository(s),
+		EventFeed:      NewEventFeedRepository(s),
+		Snapshot:       NewBalanceSnapshotRepository(s),
+		Webhook:        NewWebhookRepository(s),
 	}
 }
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type standingOrderRepository struct {
+	db database.DB
+}
+
+func NewStandingOrderRepository(s *server.Server) StandingOrderRepository {
+	return &standingOrderRepository{
+		db: s.DB,
+	}
+}
+
+// standingOrderColumns is the column list every standing order query selects, in scanStandingOrder order
+const standingOrderColumns = `id, source_account_id, destination_account_id, amount, currency, frequency, schedule_day,
+	cron_expression, start_at, end_at, max_occurrences, occurrences, insufficient_funds_policy, max_retries,
+	retry_count, status, next_run_at, last_run_at, last_failure_code, last_failure_reason, created_at, updated_at`
+
+// scanStandingOrder scans a row selected with standingOrderColumns
+func scanStandingOrder(row pgx.Row, order *model.StandingOrder) error {
+	return row.Scan(
+		&order.ID,
+		&order.SourceAccountID,
+		&order.DestinationAccountID,
+		&order.Amount,
+		&order.Currency,
+		&order.Frequency,
+		&order.ScheduleDay,
+		&order.CronExpression,
+		&order.StartAt,
+		&order.EndAt,
+		&order.MaxOccurrences,
+		&order.Occurrences,
+		&order.InsufficientFundsPolicy,
+		&order.MaxRetries,
+		&order.RetryCount,
+		&order.Status,
+		&order.NextRunAt,
+		&order.LastRunAt,
+		&order.LastFailureCode,
+		&order.LastFailureReason,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+}
+
+// Create inserts a standing order. The amount is in the currency of the source account.
+func (r *standingOrderRepository) Create(ctx context.Context, tx pgx.Tx, order *model.StandingOrder) error {
+	query := `
+		INSERT INTO standing_orders (source_account_id, destination_account_id, amount, currency, frequency, schedule_day,
+			cron_expression, start_at, end_at, max_occurrences, insufficient_funds_policy, max_retries, status,
+			next_run_at, created_at, updated_at)
+		VALUES ($1, $2, $3, (SELECT currency FROM accounts WHERE id = $1), $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW())
+		RETURNING ` + standingOrderColumns
+
+	err := scanStandingOrder(tx.QueryRow(ctx, query,
+		order.SourceAccountID,
+		order.DestinationAccountID,
+		order.Amount,
+		order.Frequency,
+		order.ScheduleDay,
+		order.CronExpression,
+		order.StartAt,
+		order.EndAt,
+		order.MaxOccurrences,
+		order.InsufficientFundsPolicy,
+		order.MaxRetries,
+		order.Status,
+		order.NextRunAt,
+	), order)
+	if err != nil {
+		return fmt.Errorf("failed to create standing order: %w", err)
+	}
+
+	return nil
+}
+
+func (r *standingOrderRepository) GetByID(ctx context.Context, id int64) (*model.StandingOrder, error) {
+	query := `
+		SELECT ` + standingOrderColumns + `
+		FROM standing_orders
+		WHERE id = $1
+	`
+
+	var order model.StandingOrder
+	err := scanStandingOrder(r.db.QueryRow(ctx, query, id), &order)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("standing order not found")
+		}
+		return nil, fmt.Errorf("failed to get standing order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// GetByIDForUpdate uses row lock to serialize state changes with the scheduler
+func (r *standingOrderRepository) GetByIDForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*model.StandingOrder, error) {
+	query := `
+		SELECT ` + standingOrderColumns + `
+		FROM standing_orders
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var order model.StandingOrder
+	err := scanStandingOrder(tx.QueryRow(ctx, query, id), &order)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("standing order not found")
+		}
+		return nil, fmt.Errorf("failed to get standing order for update: %w", err)
+	}
+
+	return &order, nil
+}
+
+// ListBySourceAccount returns a page of the orders paying out of an account, oldest first
+func (r *standingOrderRepository) ListBySourceAccount(ctx context.Context, accountID int64, after *model.KeysetCursor, limit int) ([]*model.StandingOrder, error) {
+	query := `
+		SELECT ` + standingOrderColumns + `
+		FROM standing_orders
+		WHERE source_account_id = $1
+		ORDER BY created_at, id
+		LIMIT $2
+	`
+	args := []interface{}{accountID, limit}
+
+	if after != nil {
+		query = `
+			SELECT ` + standingOrderColumns + `
+			FROM standing_orders
+			WHERE source_account_id = $1 AND (created_at, id) > ($3, $4)
+			ORDER BY created_at, id
+			LIMIT $2
+		`
+		args = append(args, after.Timestamp, after.ID)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get standing orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orders []*model.StandingOrder
+	for rows.Next() {
+		var order model.StandingOrder
+		if err := scanStandingOrder(rows, &order); err != nil {
+			return nil, fmt.Errorf("failed to scan standing order: %w", err)
+		}
+		orders = append(orders, &order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating standing orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// ClaimDue locks the next active order whose run time has passed. Orders locked by other
+// schedulers are skipped. Returns nil when nothing is due.
+func (r *standingOrderRepository) ClaimDue(ctx context.Context, tx pgx.Tx) (*model.StandingOrder, error) {
+	query := `
+		SELECT ` + standingOrderColumns + `
+		FROM standing_orders
+		WHERE status = $1 AND next_run_at <= NOW()
+		ORDER BY next_run_at, id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var order model.StandingOrder
+	err := scanStandingOrder(tx.QueryRow(ctx, query, model.StandingOrderStatusActive), &order)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim standing order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// UpdateState saves the run state of an order: status, counters, next run and last outcome
+func (r *standingOrderRepository) UpdateState(ctx context.Context, tx pgx.Tx, order *model.StandingOrder) error {
+	query := `
+		UPDATE standing_orders
+		SET status = $2, occurrences = $3, retry_count = $4, next_run_at = $5, last_run_at = $6,
+			last_failure_code = $7, last_failure_reason = $8, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := tx.QueryRow(ctx, query,
+		order.ID,
+		order.Status,
+		order.Occurrences,
+		order.RetryCount,
+		order.NextRunAt,
+		order.LastRunAt,
+		order.LastFailureCode,
+		order.LastFailureReason,
+	).Scan(&order.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("standing order not found")
+		}
+		return fmt.Errorf("failed to update standing order: %w", err)
+	}
+
+	return nil
+}
@@ -3,12 +3,15 @@ package repository
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/chandra-shekhar/internal-transfers/internal/database"
 	"github.com/chandra-shekhar/internal-transfers/internal/model"
 	"github.com/chandra-shekhar/internal-transfers/internal/server"
 	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
 )
 
 type transactionRepository struct {
@@ -21,23 +24,152 @@ func NewTransactionRepository(s *server.Server) TransactionRepository {
 	}
 }
 
-func (r *transactionRepository) Create(ctx context.Context, tx pgx.Tx, transaction *model.Transaction) error {
-	query := `
-		INSERT INTO transactions (source_account_id, destination_account_id, amount, status, created_at)
-		VALUES ($1, $2, $3, $4, NOW())
-		RETURNING id, source_account_id, destination_account_id, amount, status, created_at, completed_at
-	`
+// transactionColumns is the column list every transaction query selects, in scanTransaction order
+const transactionColumns = `id, source_account_id, destination_account_id, amount, currency, status, kind,
+	parent_transaction_id, reversed_amount, reason, execute_at, failure_code, failure_reason, standing_order_id, created_at, completed_at,
+	fx_requested, fx_quote_id, destination_amount, destination_currency, fx_rate, fx_spread, fx_rate_timestamp, fx_remainder, fx_pnl_account_id,
+	fee_rule_id, fee_amount, fee_payer, fee_waived, fee_transaction_id,
+	reference, description, external_id, metadata, expected_source_version`
+
+// scanTransaction scans a row selected with transactionColumns, followed by any extra destinations
+func scanTransaction(row pgx.Row, transaction *model.Transaction, extra ...interface{}) error {
+	// The conversion columns are only set on settled cross-currency transfers
+	var (
+		destinationAmount   *decimal.Decimal
+		destinationCurrency *string
+		rate                *decimal.Decimal
+		spread              *decimal.Decimal
+		rateTimestamp       *time.Time
+		remainder           *decimal.Decimal
+		pnlAccountID        *int64
+	)
+
+	// The fee columns are only set on settled transfers a fee rule matched
+	var (
+		feeRuleID        *int64
+		feeAmount        *decimal.Decimal
+		feePayer         *model.FeePayer
+		feeWaived        bool
+		feeTransactionID *int64
+	)
 
-	err := tx.QueryRow(ctx, query, transaction.SourceAccountID, transaction.DestinationAccountID, transaction.Amount, model.TransactionStatusPending).Scan(
+	dest := []interface{}{
 		&transaction.ID,
 		&transaction.SourceAccountID,
 		&transaction.DestinationAccountID,
 		&transaction.Amount,
+		&transaction.Currency,
 		&transaction.Status,
+		&transaction.Kind,
+		&transaction.ParentTransactionID,
+		&transaction.ReversedAmount,
+		&transaction.Reason,
+		&transaction.ExecuteAt,
+		&transaction.FailureCode,
+		&transaction.FailureReason,
+		&transaction.StandingOrderID,
 		&transaction.CreatedAt,
 		&transaction.CompletedAt,
-	)
+		&transaction.FXRequested,
+		&transaction.FXQuoteID,
+		&destinationAmount,
+		&destinationCurrency,
+		&rate,
+		&spread,
+		&rateTimestamp,
+		&remainder,
+		&pnlAccountID,
+		&feeRuleID,
+		&feeAmount,
+		&feePayer,
+		&feeWaived,
+		&feeTransactionID,
+		&transaction.Reference,
+		&transaction.Description,
+		&transaction.ExternalID,
+		&transaction.Metadata,
+		&transaction.ExpectedSourceVersion,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+
+	transaction.FX = nil
+	if destinationAmount != nil {
+		transaction.FX = &model.FXConversion{
+			DestinationAmount:   *destinationAmount,
+			DestinationCurrency: *destinationCurrency,
+			Rate:                *rate,
+			Spread:              *spread,
+			RateTimestamp:       *rateTimestamp,
+			Remainder:           *remainder,
+			PnLAccountID:        *pnlAccountID,
+		}
+	}
+
+	transaction.Fee = nil
+	if feeRuleID != nil {
+		transaction.Fee = &model.TransactionFee{
+			RuleID:        *feeRuleID,
+			Amount:        *feeAmount,
+			Payer:         *feePayer,
+			Waived:        feeWaived,
+			TransactionID: feeTransactionID,
+		}
+	}
+
+	return nil
+}
+
+// Create inserts a transaction as pending, or with the status already set on it such as scheduled.
+// The amount is always in the currency of the source account. An external ID the source account
+// already used is reported without aborting the transaction.
+func (r *transactionRepository) Create(ctx context.Context, tx pgx.Tx, transaction *model.Transaction) error {
+	query := `
+		INSERT INTO transactions (source_account_id, destination_account_id, amount, currency, status, kind, parent_transaction_id, reason, execute_at,
+			standing_order_id, fx_requested, fx_quote_id, reference, description, external_id, metadata, expected_source_version, created_at)
+		VALUES ($1, $2, $3, (SELECT currency FROM accounts WHERE id = $1), $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW())
+		ON CONFLICT (source_account_id, external_id) WHERE external_id IS NOT NULL DO NOTHING
+		RETURNING ` + transactionColumns
+
+	status := transaction.Status
+	if status == "" {
+		status = model.TransactionStatusPending
+	}
+
+	kind := transaction.Kind
+	if kind == "" {
+		kind = model.TransactionKindTransfer
+	}
+
+	// Transactions without metadata store NULL rather than an empty object
+	var metadata map[string]interface{}
+	if len(transaction.Metadata) > 0 {
+		metadata = transaction.Metadata
+	}
+
+	err := scanTransaction(tx.QueryRow(ctx, query,
+		transaction.SourceAccountID,
+		transaction.DestinationAccountID,
+		transaction.Amount,
+		status,
+		kind,
+		transaction.ParentTransactionID,
+		transaction.Reason,
+		transaction.ExecuteAt,
+		transaction.StandingOrderID,
+		transaction.FXRequested,
+		transaction.FXQuoteID,
+		transaction.Reference,
+		transaction.Description,
+		transaction.ExternalID,
+		metadata,
+		transaction.ExpectedSourceVersion,
+	), transaction)
 	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("external id already exists")
+		}
 		return fmt.Errorf("failed to create transaction: %w", err)
 	}
 
@@ -76,23 +208,84 @@ func (r *transactionRepository) UpdateStatus(ctx context.Context, tx pgx.Tx, id
 	return nil
 }
 
+// RecordConversion locks the conversion of a cross-currency transfer into its row
+func (r *transactionRepository) RecordConversion(ctx context.Context, tx pgx.Tx, id int64, conversion *model.FXConversion) error {
+	query := `
+		UPDATE transactions
+		SET destination_amount = $2, destination_currency = $3, fx_rate = $4, fx_spread = $5,
+			fx_rate_timestamp = $6, fx_remainder = $7, fx_pnl_account_id = $8
+		WHERE id = $1
+	`
+
+	result, err := tx.Exec(ctx, query, id,
+		conversion.DestinationAmount,
+		conversion.DestinationCurrency,
+		conversion.Rate,
+		conversion.Spread,
+		conversion.RateTimestamp,
+		conversion.Remainder,
+		conversion.PnLAccountID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record conversion: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("transaction not found")
+	}
+
+	return nil
+}
+
+// RecordFee stores the fee assessed on a transfer and the child transaction that posted it
+func (r *transactionRepository) RecordFee(ctx context.Context, tx pgx.Tx, id int64, fee *model.TransactionFee) error {
+	query := `
+		UPDATE transactions
+		SET fee_rule_id = $2, fee_amount = $3, fee_payer = $4, fee_waived = $5, fee_transaction_id = $6
+		WHERE id = $1
+	`
+
+	result, err := tx.Exec(ctx, query, id, fee.RuleID, fee.Amount, fee.Payer, fee.Waived, fee.TransactionID)
+	if err != nil {
+		return fmt.Errorf("failed to record fee: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("transaction not found")
+	}
+
+	return nil
+}
+
+// MarkFailed moves a transaction to failed and records why it was rejected
+func (r *transactionRepository) MarkFailed(ctx context.Context, tx pgx.Tx, id int64, code, reason string) error {
+	query := `
+		UPDATE transactions
+		SET status = $2, failure_code = $3, failure_reason = $4
+		WHERE id = $1
+	`
+
+	result, err := tx.Exec(ctx, query, id, model.TransactionStatusFailed, code, reason)
+	if err != nil {
+		return fmt.Errorf("failed to mark transaction failed: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("transaction not found")
+	}
+
+	return nil
+}
+
 func (r *transactionRepository) GetByID(ctx context.Context, id int64) (*model.Transaction, error) {
 	query := `
-		SELECT id, source_account_id, destination_account_id, amount, status, created_at, completed_at
+		SELECT ` + transactionColumns + `
 		FROM transactions
 		WHERE id = $1
 	`
 
 	var transaction model.Transaction
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&transaction.ID,
-		&transaction.SourceAccountID,
-		&transaction.DestinationAccountID,
-		&transaction.Amount,
-		&transaction.Status,
-		&transaction.CreatedAt,
-		&transaction.CompletedAt,
-	)
+	err := scanTransaction(r.db.QueryRow(ctx, query, id), &transaction)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, fmt.Errorf("transaction not found")
@@ -103,34 +296,225 @@ func (r *transactionRepository) GetByID(ctx context.Context, id int64) (*model.T
 	return &transaction, nil
 }
 
-// GetByAccountID retrieves all transactions for a specific account
-func (r *transactionRepository) GetByAccountID(ctx context.Context, accountID int64, limit, offset int) ([]*model.Transaction, error) {
+// GetByIDForUpdate uses row lock to serialize changes to a transaction such as reversals
+func (r *transactionRepository) GetByIDForUpdate(ctx context.Context, tx pgx.Tx, id int64) (*model.Transaction, error) {
 	query := `
-		SELECT id, source_account_id, destination_account_id, amount, status, created_at, completed_at
+		SELECT ` + transactionColumns + `
 		FROM transactions
-		WHERE source_account_id = $1 OR destination_account_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		WHERE id = $1
+		FOR UPDATE
 	`
 
-	rows, err := r.db.Query(ctx, query, accountID, limit, offset)
+	var transaction model.Transaction
+	err := scanTransaction(tx.QueryRow(ctx, query, id), &transaction)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get transactions: %w", err)
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("transaction not found")
+		}
+		return nil, fmt.Errorf("failed to get transaction for update: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// ClaimDueScheduled locks the next scheduled transaction whose execution time has passed.
+// Rows locked by other schedulers are skipped. Returns nil when nothing is due.
+func (r *transactionRepository) ClaimDueScheduled(ctx context.Context, tx pgx.Tx) (*model.Transaction, error) {
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE status = $1 AND execute_at <= NOW()
+		ORDER BY execute_at, id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var transaction model.Transaction
+	err := scanTransaction(tx.QueryRow(ctx, query, model.TransactionStatusScheduled), &transaction)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim scheduled transaction: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// ClaimPending locks the oldest pending transaction queued for a worker. Transfers settled
+// synchronously are never visible as pending, and rows locked by other workers are skipped.
+// Returns nil when the queue is empty.
+func (r *transactionRepository) ClaimPending(ctx context.Context, tx pgx.Tx) (*model.Transaction, error) {
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE status = $1
+		ORDER BY created_at, id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var transaction model.Transaction
+	err := scanTransaction(tx.QueryRow(ctx, query, model.TransactionStatusPending), &transaction)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim pending transaction: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// ListScheduled returns a page of future-dated transactions ordered by execution time
+func (r *transactionRepository) ListScheduled(ctx context.Context, filter *model.ScheduledTransactionFilter) ([]*model.Transaction, error) {
+	args := []interface{}{filter.Status}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions := []string{"execute_at IS NOT NULL", "status = $1"}
+	if filter.AccountID != nil {
+		account := addArg(*filter.AccountID)
+		conditions = append(conditions, fmt.Sprintf("(source_account_id = %s OR destination_account_id = %s)", account, account))
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(execute_at, id) > (%s, %s)", addArg(filter.After.Timestamp), addArg(filter.After.ID)))
+	}
+
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY execute_at, id
+		LIMIT ` + addArg(filter.Limit)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled transactions: %w", err)
 	}
 	defer rows.Close()
 
 	var transactions []*model.Transaction
 	for rows.Next() {
 		var transaction model.Transaction
-		err := rows.Scan(
-			&transaction.ID,
-			&transaction.SourceAccountID,
-			&transaction.DestinationAccountID,
-			&transaction.Amount,
-			&transaction.Status,
-			&transaction.CreatedAt,
-			&transaction.CompletedAt,
-		)
+		if err := scanTransaction(rows, &transaction); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, &transaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// AddReversedAmount records money sent back by a reversal and moves the transaction to
+// reversed once the whole amount has been returned. The table check rejects over-reversal.
+func (r *transactionRepository) AddReversedAmount(ctx context.Context, tx pgx.Tx, id int64, amount decimal.Decimal) (*model.Transaction, error) {
+	query := `
+		UPDATE transactions
+		SET reversed_amount = reversed_amount + $2,
+			status = CASE WHEN reversed_amount + $2 = amount THEN $3 ELSE $4 END
+		WHERE id = $1
+		RETURNING ` + transactionColumns
+
+	var transaction model.Transaction
+	err := scanTransaction(tx.QueryRow(ctx, query, id, amount, model.TransactionStatusReversed, model.TransactionStatusPartiallyReversed), &transaction)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("transaction not found")
+		}
+		return nil, fmt.Errorf("failed to update reversed amount: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// ListByAccount returns a page of the account's transactions, newest first, with keyset
+// pagination on (created_at, id). Each side of the account is read through its own index.
+func (r *transactionRepository) ListByAccount(ctx context.Context, filter *model.AccountTransactionFilter) ([]*model.AccountTransaction, error) {
+	args := []interface{}{filter.AccountID}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var conditions []string
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= "+addArg(*filter.From))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < "+addArg(*filter.To))
+	}
+	if filter.Status != nil {
+		conditions = append(conditions, "status = "+addArg(*filter.Status))
+	}
+	var minAmount, maxAmount string
+	if filter.MinAmount != nil {
+		minAmount = addArg(*filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		maxAmount = addArg(*filter.MaxAmount)
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < (%s, %s)", addArg(filter.After.Timestamp), addArg(filter.After.ID)))
+	}
+	limit := addArg(filter.Limit)
+
+	// The amount range applies to the amount the line shows: the destination of a conversion
+	// sees the converted amount, once the conversion has settled
+	branch := func(accountColumn, amountColumn string) string {
+		where := append([]string{accountColumn + " = $1"}, conditions...)
+		if minAmount != "" {
+			where = append(where, amountColumn+" >= "+minAmount)
+		}
+		if maxAmount != "" {
+			where = append(where, amountColumn+" <= "+maxAmount)
+		}
+		return `(
+			SELECT ` + transactionColumns + `
+			FROM transactions
+			WHERE ` + strings.Join(where, " AND ") + `
+			ORDER BY created_at DESC, id DESC
+			LIMIT ` + limit + `
+		)`
+	}
+
+	var branches []string
+	if filter.Direction == nil || *filter.Direction == model.TransactionDirectionOut {
+		branches = append(branches, branch("source_account_id", "amount"))
+	}
+	if filter.Direction == nil || *filter.Direction == model.TransactionDirectionIn {
+		branches = append(branches, branch("destination_account_id", "COALESCE(destination_amount, amount)"))
+	}
+
+	query := `
+		SELECT t.*, le.balance_after
+		FROM (` + strings.Join(branches, " UNION ALL ") + `) t
+		LEFT JOIN LATERAL (
+			SELECT balance_after
+			FROM ledger_entries
+			WHERE transaction_id = t.id AND account_id = $1
+			ORDER BY id DESC
+			LIMIT 1
+		) le ON TRUE
+		ORDER BY t.created_at DESC, t.id DESC
+		LIMIT ` + limit
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*model.AccountTransaction
+	for rows.Next() {
+		var transaction model.AccountTransaction
+		err := scanTransaction(rows, &transaction.Transaction, &transaction.RunningBalance)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan transaction: %w", err)
 		}
@@ -143,3 +527,115 @@ func (r *transactionRepository) GetByAccountID(ctx context.Context, accountID in
 
 	return transactions, nil
 }
+
+// ListPostings returns the account's transactions in the order they were posted to its ledger,
+// starting after the given ledger entry. Postings without a transaction, such as the opening
+// balance, are skipped.
+func (r *transactionRepository) ListPostings(ctx context.Context, accountID, afterEntryID int64, limit int) ([]*model.AccountPosting, error) {
+	query := `
+		SELECT t.*, le.id, le.balance_after
+		FROM ledger_entries le
+		JOIN LATERAL (
+			SELECT ` + transactionColumns + `
+			FROM transactions
+			WHERE id = le.transaction_id
+		) t ON TRUE
+		WHERE le.account_id = $1 AND le.id > $2
+		ORDER BY le.id
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, accountID, afterEntryID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get postings: %w", err)
+	}
+	defer rows.Close()
+
+	postings := make([]*model.AccountPosting, 0)
+	for rows.Next() {
+		var posting model.AccountPosting
+		err := scanTransaction(rows, &posting.Transaction, &posting.EntryID, &posting.RunningBalance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan posting: %w", err)
+		}
+		postings = append(postings, &posting)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating postings: %w", err)
+	}
+
+	return postings, nil
+}
+
+// Search returns a page of transactions matching the details their callers supplied, newest first,
+// with keyset pagination on (created_at, id)
+func (r *transactionRepository) Search(ctx context.Context, filter *model.TransactionSearchFilter) ([]*model.Transaction, error) {
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var conditions []string
+	if filter.Reference != "" {
+		conditions = append(conditions, "reference = "+addArg(filter.Reference))
+	}
+	if filter.ExternalID != "" {
+		conditions = append(conditions, "external_id = "+addArg(filter.ExternalID))
+	}
+	if filter.AccountID != 0 {
+		account := addArg(filter.AccountID)
+		conditions = append(conditions, fmt.Sprintf("(source_account_id = %s OR destination_account_id = %s)", account, account))
+	}
+	// Keys are sorted so the same filter always builds the same query
+	keys := make([]string, 0, len(filter.Metadata))
+	for key := range filter.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		// Each containment is answered by the GIN index on metadata
+		var matches []string
+		for _, value := range model.MetadataValues(filter.Metadata[key]) {
+			matches = append(matches, "metadata @> "+addArg(map[string]interface{}{key: value}))
+		}
+		conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < (%s, %s)", addArg(filter.After.Timestamp), addArg(filter.After.ID)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		` + where + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT ` + addArg(filter.Limit)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transactions: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := make([]*model.Transaction, 0)
+	for rows.Next() {
+		var transaction model.Transaction
+		if err := scanTransaction(rows, &transaction); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		transactions = append(transactions, &transaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transactions: %w", err)
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/chandra-shekhar/internal-transfers/internal/velocity"
+	"github.com/jackc/pgx/v5"
+)
+
+type velocityLimitRepository struct {
+	db database.DB
+}
+
+func NewVelocityLimitRepository(s *server.Server) VelocityLimitRepository {
+	return &velocityLimitRepository{
+		db: s.DB,
+	}
+}
+
+// velocityLimitColumns is the column list every velocity limit query selects, in scanVelocityLimit order
+const velocityLimitColumns = `id, account_id, account_group, currency, kind, window_seconds, max_value, created_at`
+
+// scanVelocityLimit scans a row selected with velocityLimitColumns
+func scanVelocityLimit(row pgx.Row, limit *model.VelocityLimit) error {
+	return row.Scan(
+		&limit.ID,
+		&limit.AccountID,
+		&limit.AccountGroup,
+		&limit.Currency,
+		&limit.Kind,
+		&limit.WindowSeconds,
+		&limit.MaxValue,
+		&limit.CreatedAt,
+	)
+}
+
+func (r *velocityLimitRepository) Create(ctx context.Context, limit *model.VelocityLimit) error {
+	query := `
+		INSERT INTO velocity_limits (account_id, account_group, currency, kind, window_seconds, max_value, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING ` + velocityLimitColumns
+
+	err := scanVelocityLimit(r.db.QueryRow(ctx, query,
+		limit.AccountID,
+		limit.AccountGroup,
+		limit.Currency,
+		limit.Kind,
+		limit.WindowSeconds,
+		limit.MaxValue,
+	), limit)
+	if err != nil {
+		return fmt.Errorf("failed to create velocity limit: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the velocity limits matching the filter, oldest first
+func (r *velocityLimitRepository) List(ctx context.Context, filter *model.ListVelocityLimitsRequest) ([]*model.VelocityLimit, error) {
+	query := `
+		SELECT ` + velocityLimitColumns + `
+		FROM velocity_limits
+		WHERE ($1::BIGINT = 0 OR account_id = $1)
+			AND ($2::VARCHAR = '' OR account_group = $2)
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query, filter.AccountID, filter.AccountGroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list velocity limits: %w", err)
+	}
+
+	return collectVelocityLimits(rows)
+}
+
+func (r *velocityLimitRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM velocity_limits WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete velocity limit: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("velocity limit not found")
+	}
+
+	return nil
+}
+
+// LockApplicable locks the limits of the given accounts and groups in ascending ID order.
+// Holding the group limits serializes transfers from different accounts of the same group,
+// which the account locks alone would not.
+func (r *velocityLimitRepository) LockApplicable(ctx context.Context, tx pgx.Tx, accountIDs []int64, groups []string) ([]*model.VelocityLimit, error) {
+	query := `
+		SELECT ` + velocityLimitColumns + `
+		FROM velocity_limits
+		WHERE account_id = ANY($1) OR account_group = ANY($2)
+		ORDER BY id
+		FOR UPDATE
+	`
+
+	rows, err := tx.Query(ctx, query, accountIDs, groups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock velocity limits: %w", err)
+	}
+
+	return collectVelocityLimits(rows)
+}
+
+// ListOutgoingByAccount returns the transfers in currency the account completed since the given time
+func (r *velocityLimitRepository) ListOutgoingByAccount(ctx context.Context, tx pgx.Tx, accountID int64, currency string, since time.Time) ([]velocity.Movement, error) {
+	query := `
+		SELECT amount, completed_at
+		FROM transactions
+		WHERE source_account_id = $1
+			AND kind = $2
+			AND completed_at > $3
+			AND currency = $4
+	`
+
+	rows, err := tx.Query(ctx, query, accountID, model.TransactionKindTransfer, since, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outgoing transfers: %w", err)
+	}
+
+	return collectMovements(rows)
+}
+
+// ListOutgoingByGroup returns the transfers in currency the accounts of a group completed since
+// the given time. Transfers in other currencies are left out, as their amounts do not add up.
+func (r *velocityLimitRepository) ListOutgoingByGroup(ctx context.Context, tx pgx.Tx, group string, currency string, since time.Time) ([]velocity.Movement, error) {
+	query := `
+		SELECT t.amount, t.completed_at
+		FROM transactions t
+		JOIN accounts a ON a.id = t.source_account_id
+		WHERE a.account_group = $1
+			AND t.kind = $2
+			AND t.completed_at > $3
+			AND t.currency = $4
+	`
+
+	rows, err := tx.Query(ctx, query, group, model.TransactionKindTransfer, since, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outgoing transfers: %w", err)
+	}
+
+	return collectMovements(rows)
+}
+
+func collectVelocityLimits(rows pgx.Rows) ([]*model.VelocityLimit, error) {
+	defer rows.Close()
+
+	limits := make([]*model.VelocityLimit, 0)
+	for rows.Next() {
+		var limit model.VelocityLimit
+		if err := scanVelocityLimit(rows, &limit); err != nil {
+			return nil, fmt.Errorf("failed to scan velocity limit: %w", err)
+		}
+		limits = append(limits, &limit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating velocity limits: %w", err)
+	}
+
+	return limits, nil
+}
+
+func collectMovements(rows pgx.Rows) ([]velocity.Movement, error) {
+	defer rows.Close()
+
+	var movements []velocity.Movement
+	for rows.Next() {
+		var movement velocity.Movement
+		if err := rows.Scan(&movement.Amount, &movement.At); err != nil {
+			return nil, fmt.Errorf("failed to scan outgoing transfer: %w", err)
+		}
+		movements = append(movements, movement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outgoing transfers: %w", err)
+	}
+
+	return movements, nil
+}
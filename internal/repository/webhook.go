@@ -0,0 +1,428 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type webhookRepository struct {
+	db database.DB
+}
+
+func NewWebhookRepository(s *server.Server) WebhookRepository {
+	return &webhookRepository{
+		db: s.DB,
+	}
+}
+
+// webhookColumns is the column list every webhook query selects, in scanWebhook order
+const webhookColumns = `id, url, event_types, description, active, secret, previous_secret,
+	previous_secret_expires_at, created_at, updated_at`
+
+// scanWebhook scans a row selected with webhookColumns
+func scanWebhook(row pgx.Row, webhook *model.Webhook) error {
+	return row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&webhook.EventTypes,
+		&webhook.Description,
+		&webhook.Active,
+		&webhook.Secret,
+		&webhook.PreviousSecret,
+		&webhook.PreviousSecretExpiresAt,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+}
+
+// webhookDeliveryColumns is the column list every delivery query selects, in scanWebhookDelivery order
+const webhookDeliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, last_status_code, last_error, created_at, delivered_at`
+
+// scanWebhookDelivery scans a row selected with webhookDeliveryColumns, followed by extra destinations
+func scanWebhookDelivery(row pgx.Row, delivery *model.WebhookDelivery, extra ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	}, extra...)...)
+}
+
+// webhookDeadLetterColumns is the column list every dead letter query selects, in scanWebhookDeadLetter order
+const webhookDeadLetterColumns = `id, webhook_id, event_id, event_type, payload, attempts,
+	last_status_code, last_error, created_at, replayed_at`
+
+// scanWebhookDeadLetter scans a row selected with webhookDeadLetterColumns
+func scanWebhookDeadLetter(row pgx.Row, deadLetter *model.WebhookDeadLetter) error {
+	return row.Scan(
+		&deadLetter.ID,
+		&deadLetter.WebhookID,
+		&deadLetter.EventID,
+		&deadLetter.EventType,
+		&deadLetter.Payload,
+		&deadLetter.Attempts,
+		&deadLetter.LastStatusCode,
+		&deadLetter.LastError,
+		&deadLetter.CreatedAt,
+		&deadLetter.ReplayedAt,
+	)
+}
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *model.Webhook) error {
+	query := `
+		INSERT INTO webhooks (url, event_types, description, active, secret, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING ` + webhookColumns
+
+	err := scanWebhook(r.db.QueryRow(ctx, query,
+		webhook.URL,
+		webhook.EventTypes,
+		webhook.Description,
+		webhook.Active,
+		webhook.Secret,
+	), webhook)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id int64) (*model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+
+	var webhook model.Webhook
+	if err := scanWebhook(r.db.QueryRow(ctx, query, id), &webhook); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// List returns every webhook, oldest first
+func (r *webhookRepository) List(ctx context.Context) ([]*model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks ORDER BY id`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]*model.Webhook, 0)
+	for rows.Next() {
+		var webhook model.Webhook
+		if err := scanWebhook(rows, &webhook); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// Update writes the subscription of the webhook; its secrets are only changed by RotateSecret
+func (r *webhookRepository) Update(ctx context.Context, webhook *model.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = $2, event_types = $3, description = $4, active = $5
+		WHERE id = $1
+		RETURNING ` + webhookColumns
+
+	err := scanWebhook(r.db.QueryRow(ctx, query,
+		webhook.ID,
+		webhook.URL,
+		webhook.EventTypes,
+		webhook.Description,
+		webhook.Active,
+	), webhook)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("webhook not found")
+		}
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return nil
+}
+
+// RotateSecret replaces the secret and keeps the old one valid until previousExpiresAt
+func (r *webhookRepository) RotateSecret(ctx context.Context, id int64, secret string, previousExpiresAt time.Time) (*model.Webhook, error) {
+	query := `
+		UPDATE webhooks
+		SET previous_secret = secret, previous_secret_expires_at = $3, secret = $2
+		WHERE id = $1
+		RETURNING ` + webhookColumns
+
+	var webhook model.Webhook
+	if err := scanWebhook(r.db.QueryRow(ctx, query, id, secret, previousExpiresAt), &webhook); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		return nil, fmt.Errorf("failed to rotate webhook secret: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// Delete removes the webhook with its pending deliveries and dead letters
+func (r *webhookRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("webhook not found")
+	}
+
+	return nil
+}
+
+// Enqueue creates a delivery of the event for every active webhook subscribed to its type and
+// returns how many it created. An event is enqueued at most once per webhook.
+func (r *webhookRepository) Enqueue(ctx context.Context, tx pgx.Tx, eventID int64, eventType string, payload []byte) (int64, error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+		SELECT id, $1, $2, $3, $4, NOW(), NOW()
+		FROM webhooks
+		WHERE active AND $2 = ANY(event_types)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+	`
+
+	result, err := tx.Exec(ctx, query, eventID, eventType, payload, model.WebhookDeliveryPending)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// ClaimDue leases up to limit due deliveries of active webhooks until leaseUntil, so a
+// worker can send them outside a database transaction. A delivery whose worker dies is
+// claimed again once its lease runs out.
+func (r *webhookRepository) ClaimDue(ctx context.Context, limit int, leaseUntil time.Time) ([]*model.WebhookTarget, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE webhook_deliveries
+			SET next_attempt_at = $3
+			WHERE id IN (
+				SELECT d.id
+				FROM webhook_deliveries d
+				JOIN webhooks w ON w.id = d.webhook_id AND w.active
+				WHERE d.status = $1 AND d.next_attempt_at <= NOW()
+				ORDER BY d.next_attempt_at, d.id
+				LIMIT $2
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING ` + webhookDeliveryColumns + `
+		)
+		SELECT claimed.*, w.url, w.secret,
+			CASE WHEN w.previous_secret_expires_at > NOW() THEN w.previous_secret END
+		FROM claimed
+		JOIN webhooks w ON w.id = claimed.webhook_id
+		ORDER BY claimed.id
+	`
+
+	rows, err := r.db.Query(ctx, query, model.WebhookDeliveryPending, limit, leaseUntil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	targets := make([]*model.WebhookTarget, 0)
+	for rows.Next() {
+		var (
+			delivery       model.WebhookDelivery
+			target         = model.WebhookTarget{Delivery: &delivery}
+			secret         string
+			previousSecret *string
+		)
+		if err := scanWebhookDelivery(rows, &delivery, &target.URL, &secret, &previousSecret); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+
+		target.Secrets = []string{secret}
+		if previousSecret != nil {
+			target.Secrets = append(target.Secrets, *previousSecret)
+		}
+		targets = append(targets, &target)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	return targets, nil
+}
+
+// MarkDelivered records the attempt the receiver acknowledged
+func (r *webhookRepository) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, last_status_code = $3, last_error = NULL, delivered_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Exec(ctx, query, id, model.WebhookDeliveryDelivered, statusCode); err != nil {
+		return fmt.Errorf("failed to mark webhook delivery delivered: %w", err)
+	}
+
+	return nil
+}
+
+// ScheduleRetry records a failed attempt and when the next one is due. statusCode is nil
+// when no response arrived.
+func (r *webhookRepository) ScheduleRetry(ctx context.Context, id int64, statusCode *int, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, last_status_code = $2, last_error = $3, next_attempt_at = $4
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Exec(ctx, query, id, statusCode, lastError, nextAttemptAt); err != nil {
+		return fmt.Errorf("failed to schedule webhook delivery retry: %w", err)
+	}
+
+	return nil
+}
+
+// DeadLetter moves a delivery whose last attempt failed into the dead letters
+func (r *webhookRepository) DeadLetter(ctx context.Context, id int64, statusCode *int, lastError string) (*model.WebhookDeadLetter, error) {
+	query := `
+		WITH dead AS (
+			DELETE FROM webhook_deliveries
+			WHERE id = $1
+			RETURNING webhook_id, event_id, event_type, payload, attempts
+		)
+		INSERT INTO webhook_dead_letters (webhook_id, event_id, event_type, payload, attempts, last_status_code, last_error, created_at)
+		SELECT webhook_id, event_id, event_type, payload, attempts + 1, $2, $3, NOW()
+		FROM dead
+		RETURNING ` + webhookDeadLetterColumns
+
+	var deadLetter model.WebhookDeadLetter
+	if err := scanWebhookDeadLetter(r.db.QueryRow(ctx, query, id, statusCode, lastError), &deadLetter); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("webhook delivery not found")
+		}
+		return nil, fmt.Errorf("failed to dead-letter webhook delivery: %w", err)
+	}
+
+	return &deadLetter, nil
+}
+
+// ListDeadLetters returns a page of the dead letters of a webhook, newest first, with keyset
+// pagination on (created_at, id)
+func (r *webhookRepository) ListDeadLetters(ctx context.Context, filter *model.WebhookDeadLetterFilter) ([]*model.WebhookDeadLetter, error) {
+	args := []interface{}{filter.WebhookID}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions := []string{"webhook_id = $1"}
+	if filter.Before != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < (%s, %s)", addArg(filter.Before.Timestamp), addArg(filter.Before.ID)))
+	}
+
+	query := `
+		SELECT ` + webhookDeadLetterColumns + `
+		FROM webhook_dead_letters
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT ` + addArg(filter.Limit)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	deadLetters := make([]*model.WebhookDeadLetter, 0)
+	for rows.Next() {
+		var deadLetter model.WebhookDeadLetter
+		if err := scanWebhookDeadLetter(rows, &deadLetter); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook dead letter: %w", err)
+		}
+		deadLetters = append(deadLetters, &deadLetter)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook dead letters: %w", err)
+	}
+
+	return deadLetters, nil
+}
+
+// GetDeadLetterForUpdate locks a dead letter of the webhook
+func (r *webhookRepository) GetDeadLetterForUpdate(ctx context.Context, tx pgx.Tx, webhookID, id int64) (*model.WebhookDeadLetter, error) {
+	query := `
+		SELECT ` + webhookDeadLetterColumns + `
+		FROM webhook_dead_letters
+		WHERE id = $1 AND webhook_id = $2
+		FOR UPDATE
+	`
+
+	var deadLetter model.WebhookDeadLetter
+	if err := scanWebhookDeadLetter(tx.QueryRow(ctx, query, id, webhookID), &deadLetter); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("webhook dead letter not found")
+		}
+		return nil, fmt.Errorf("failed to get webhook dead letter: %w", err)
+	}
+
+	return &deadLetter, nil
+}
+
+// Replay marks the dead letter replayed and queues its event for delivery again, with a
+// fresh set of attempts
+func (r *webhookRepository) Replay(ctx context.Context, tx pgx.Tx, deadLetter *model.WebhookDeadLetter) (*model.WebhookDelivery, error) {
+	if _, err := tx.Exec(ctx, `UPDATE webhook_dead_letters SET replayed_at = NOW() WHERE id = $1`, deadLetter.ID); err != nil {
+		return nil, fmt.Errorf("failed to mark webhook dead letter replayed: %w", err)
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (webhook_id, event_id) DO UPDATE
+		SET status = EXCLUDED.status, attempts = 0, next_attempt_at = EXCLUDED.next_attempt_at,
+			last_status_code = NULL, last_error = NULL, delivered_at = NULL
+		RETURNING ` + webhookDeliveryColumns
+
+	var delivery model.WebhookDelivery
+	err := scanWebhookDelivery(tx.QueryRow(ctx, query,
+		deadLetter.WebhookID,
+		deadLetter.EventID,
+		deadLetter.EventType,
+		deadLetter.Payload,
+		model.WebhookDeliveryPending,
+	), &delivery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue webhook delivery: %w", err)
+	}
+
+	return &delivery, nil
+}
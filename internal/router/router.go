@@ -74,9 +74,75 @@ func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services
 	// Account routes
 	v1.POST("/accounts", h.Account.CreateAccount)
 	v1.GET("/accounts/:account_id", h.Account.GetAccount)
+	v1.GET("/accounts/:account_id/transactions", h.Transaction.ListAccountTransactions)
+	v1.GET("/accounts/:account_id/balance", h.Balance.GetBalance)
+	v1.GET("/accounts/:account_id/statements", h.Statement.GetStatement)
+	v1.GET("/accounts/:account_id/stream", h.AccountStream.StreamAccount)
+	v1.GET("/accounts/:account_id/standing-orders", h.StandingOrder.ListStandingOrders)
+	v1.POST("/accounts/:account_id/holds", h.Hold.CreateHold)
+	v1.POST("/accounts/:account_id/freeze", h.Account.FreezeAccount)
+	v1.POST("/accounts/:account_id/unfreeze", h.Account.UnfreezeAccount)
+	v1.POST("/accounts/:account_id/close", h.Account.CloseAccount)
+	v1.GET("/accounts/:account_id/interest-accruals", h.Interest.ListAccruals)
 
 	// Transaction routes
 	v1.POST("/transactions", h.Transaction.CreateTransaction)
+	v1.GET("/transactions", h.Transaction.SearchTransactions)
+	v1.POST("/transactions/batch", h.Transaction.CreateBatchTransaction)
+	v1.GET("/transactions/scheduled", h.Transaction.ListScheduledTransactions)
+	v1.GET("/transactions/:transaction_id", h.Transaction.GetTransaction)
+	v1.POST("/transactions/:transaction_id/cancel", h.Transaction.CancelScheduledTransaction)
+
+	// Standing order routes
+	v1.POST("/standing-orders", h.StandingOrder.CreateStandingOrder)
+	v1.GET("/standing-orders/:standing_order_id", h.StandingOrder.GetStandingOrder)
+	v1.POST("/standing-orders/:standing_order_id/pause", h.StandingOrder.PauseStandingOrder)
+	v1.POST("/standing-orders/:standing_order_id/resume", h.StandingOrder.ResumeStandingOrder)
+	v1.POST("/standing-orders/:standing_order_id/cancel", h.StandingOrder.CancelStandingOrder)
+	v1.POST("/transactions/:transaction_id/reversals", h.Transaction.CreateReversal)
+
+	// Hold routes
+	v1.GET("/holds/:hold_id", h.Hold.GetHold)
+	v1.POST("/holds/:hold_id/capture", h.Hold.CaptureHold)
+	v1.POST("/holds/:hold_id/void", h.Hold.VoidHold)
+
+	// FX routes
+	v1.POST("/fx/quotes", h.FX.CreateQuote)
+	v1.GET("/fx/quotes/:quote_id", h.FX.GetQuote)
+
+	// Webhook routes
+	v1.POST("/webhooks", h.Webhook.CreateWebhook)
+	v1.GET("/webhooks", h.Webhook.ListWebhooks)
+	v1.GET("/webhooks/:webhook_id", h.Webhook.GetWebhook)
+	v1.PATCH("/webhooks/:webhook_id", h.Webhook.UpdateWebhook)
+	v1.DELETE("/webhooks/:webhook_id", h.Webhook.DeleteWebhook)
+	v1.POST("/webhooks/:webhook_id/rotate-secret", h.Webhook.RotateSecret)
+	v1.GET("/webhooks/:webhook_id/dead-letters", h.Webhook.ListDeadLetters)
+	v1.POST("/webhooks/:webhook_id/dead-letters/:dead_letter_id/replay", h.Webhook.ReplayDeadLetter)
+
+	// Event feed routes
+	v1.GET("/events", h.EventFeed.ListEvents)
+	v1.GET("/consumer-groups", h.EventFeed.ListConsumerGroups)
+	v1.GET("/consumer-groups/:group", h.EventFeed.GetConsumerGroup)
+	v1.PUT("/consumer-groups/:group/offset", h.EventFeed.CommitOffset)
+	v1.DELETE("/consumer-groups/:group", h.EventFeed.DeleteConsumerGroup)
+
+	// Admin routes
+	admin := v1.Group("/admin")
+	admin.PUT("/accounts/:account_id/limits", h.Account.UpdateAccountLimits)
+	admin.GET("/accounts/:account_id/limits/history", h.Account.ListAccountLimitChanges)
+	admin.PUT("/accounts/:account_id/group", h.Velocity.UpdateAccountGroup)
+	admin.POST("/velocity-limits", h.Velocity.CreateLimit)
+	admin.GET("/velocity-limits", h.Velocity.ListLimits)
+	admin.DELETE("/velocity-limits/:limit_id", h.Velocity.DeleteLimit)
+	admin.POST("/fee-rules", h.Fee.CreateRule)
+	admin.GET("/fee-rules", h.Fee.ListRules)
+	admin.DELETE("/fee-rules/:rule_id", h.Fee.DeleteRule)
+	admin.PUT("/accounts/:account_id/interest", h.Interest.UpdateAccountProduct)
+	admin.POST("/interest-products", h.Interest.CreateProduct)
+	admin.GET("/interest-products", h.Interest.ListProducts)
+	admin.POST("/interest/accruals", h.Interest.RunAccrual)
+	admin.POST("/interest/capitalizations", h.Interest.RunCapitalization)
 
 	return router
 }
@@ -0,0 +1,109 @@
+// Package schedule computes the occurrences of recurring work such as standing orders
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// Frequency is how often a schedule fires
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "daily"
+	FrequencyWeekly  Frequency = "weekly"
+	FrequencyMonthly Frequency = "monthly"
+	FrequencyCron    Frequency = "cron"
+)
+
+// Schedule yields the occurrences of a recurring job
+type Schedule interface {
+	// Next returns the first occurrence strictly after the given time, or the zero time if
+	// there is none
+	Next(after time.Time) time.Time
+}
+
+// New builds a schedule. Daily, weekly and monthly schedules fire at the time of day of anchor,
+// in its location. Weekly schedules take day as the weekday (0 = Sunday) and monthly schedules
+// as the day of the month (1-31), clamped to the last day of shorter months. Cron schedules use
+// a standard five-field expression evaluated in UTC.
+func New(frequency Frequency, day int, expression string, anchor time.Time) (Schedule, error) {
+	switch frequency {
+	case FrequencyDaily:
+		return daily{anchor: anchor}, nil
+	case FrequencyWeekly:
+		if day < 0 || day > 6 {
+			return nil, fmt.Errorf("weekly schedule day must be a weekday between 0 (Sunday) and 6")
+		}
+		return weekly{anchor: anchor, weekday: time.Weekday(day)}, nil
+	case FrequencyMonthly:
+		if day < 1 || day > 31 {
+			return nil, fmt.Errorf("monthly schedule day must be between 1 and 31")
+		}
+		return monthly{anchor: anchor, day: day}, nil
+	case FrequencyCron:
+		cron, err := ParseCron(expression)
+		if err != nil {
+			return nil, err
+		}
+		return cron, nil
+	default:
+		return nil, fmt.Errorf("unknown schedule frequency %q", frequency)
+	}
+}
+
+type daily struct {
+	anchor time.Time
+}
+
+func (s daily) Next(after time.Time) time.Time {
+	after = after.In(s.anchor.Location())
+	next := atClock(after.Year(), after.Month(), after.Day(), s.anchor)
+	if !next.After(after) {
+		next = atClock(after.Year(), after.Month(), after.Day()+1, s.anchor)
+	}
+	return next
+}
+
+type weekly struct {
+	anchor  time.Time
+	weekday time.Weekday
+}
+
+func (s weekly) Next(after time.Time) time.Time {
+	after = after.In(s.anchor.Location())
+	for offset := 0; ; offset++ {
+		next := atClock(after.Year(), after.Month(), after.Day()+offset, s.anchor)
+		if next.Weekday() == s.weekday && next.After(after) {
+			return next
+		}
+	}
+}
+
+type monthly struct {
+	anchor time.Time
+	day    int
+}
+
+func (s monthly) Next(after time.Time) time.Time {
+	after = after.In(s.anchor.Location())
+	for offset := 0; ; offset++ {
+		// Day 0 of the following month is the last day of this one
+		month := time.Date(after.Year(), after.Month()+time.Month(offset), 1, 0, 0, 0, 0, s.anchor.Location())
+		lastDay := time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, s.anchor.Location()).Day()
+		day := s.day
+		if day > lastDay {
+			day = lastDay
+		}
+
+		next := atClock(month.Year(), month.Month(), day, s.anchor)
+		if next.After(after) {
+			return next
+		}
+	}
+}
+
+// atClock returns the given date at the time of day of clock, in its location
+func atClock(year int, month time.Month, day int, clock time.Time) time.Time {
+	return time.Date(year, month, day, clock.Hour(), clock.Minute(), clock.Second(), 0, clock.Location())
+}
@@ -0,0 +1,100 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/schedule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var anchor = time.Date(2026, 1, 1, 9, 30, 0, 0, time.UTC)
+
+func TestDaily_Next(t *testing.T) {
+	s, err := schedule.New(schedule.FrequencyDaily, 0, "", anchor)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC), s.Next(time.Date(2026, 3, 4, 8, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2026, 3, 5, 9, 30, 0, 0, time.UTC), s.Next(time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC)))
+}
+
+func TestWeekly_Next(t *testing.T) {
+	s, err := schedule.New(schedule.FrequencyWeekly, int(time.Friday), "", anchor)
+	require.NoError(t, err)
+
+	// 2026-10-16 is a Friday
+	assert.Equal(t, time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC), s.Next(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2026, 10, 23, 9, 30, 0, 0, time.UTC), s.Next(time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)))
+}
+
+func TestMonthly_ClampsToLastDay(t *testing.T) {
+	s, err := schedule.New(schedule.FrequencyMonthly, 31, "", anchor)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2026, 2, 28, 9, 30, 0, 0, time.UTC), s.Next(time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2026, 3, 31, 9, 30, 0, 0, time.UTC), s.Next(time.Date(2026, 2, 28, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestNew_InvalidDay(t *testing.T) {
+	_, err := schedule.New(schedule.FrequencyMonthly, 0, "", anchor)
+	assert.Error(t, err)
+
+	_, err = schedule.New(schedule.FrequencyWeekly, 7, "", anchor)
+	assert.Error(t, err)
+}
+
+func TestCron_Next(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		after      time.Time
+		want       time.Time
+	}{
+		{
+			name:       "every 15 minutes during business hours on weekdays",
+			expression: "*/15 9-17 * * 1-5",
+			after:      time.Date(2026, 10, 16, 17, 50, 0, 0, time.UTC),
+			want:       time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "first of the month at midnight",
+			expression: "0 0 1 * *",
+			after:      time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC),
+			want:       time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "restricted day of month or day of week",
+			expression: "0 12 13 * 5",
+			after:      time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC),
+			want:       time.Date(2026, 10, 13, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "sunday as 7",
+			expression: "30 8 * * 7",
+			after:      time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+			want:       time.Date(2026, 10, 18, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "never fires",
+			expression: "0 0 30 2 *",
+			after:      time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:       time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := schedule.New(schedule.FrequencyCron, 0, tt.expression, anchor)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.Next(tt.after))
+		})
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := schedule.ParseCron(expression)
+		assert.Error(t, err, expression)
+	}
+}
@@ -3,25 +3,44 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/chandra-shekhar/internal-transfers/internal/currency"
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
 	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/event"
 	"github.com/chandra-shekhar/internal-transfers/internal/model"
 	"github.com/chandra-shekhar/internal-transfers/internal/repository"
-	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/pkg/errors"
+	"github.com/jackc/pgx/v5"
 	"github.com/rs/zerolog"
 	"github.com/shopspring/decimal"
 )
 
 type AccountService struct {
+	db          database.DB
 	accountRepo repository.AccountRepository
-	logger      *zerolog.Logger
+	limitRepo   repository.AccountLimitRepository
+	ledgerRepo  repository.LedgerRepository
+	// transactions sweeps the balance of accounts that are being closed
+	transactions *TransactionService
+	outbox       *OutboxService
+	idempotency  *IdempotencyService
+	// defaultCurrency is used for accounts created without a currency
+	defaultCurrency string
+	logger          *zerolog.Logger
 }
 
-func NewAccountService(accountRepo repository.AccountRepository, logger *zerolog.Logger) *AccountService {
+func NewAccountService(db database.DB, accountRepo repository.AccountRepository, limitRepo repository.AccountLimitRepository, ledgerRepo repository.LedgerRepository, transactions *TransactionService, outbox *OutboxService, idempotency *IdempotencyService, defaultCurrency string, logger *zerolog.Logger) *AccountService {
 	return &AccountService{
-		accountRepo: accountRepo,
-		logger:      logger,
+		db:              db,
+		accountRepo:     accountRepo,
+		limitRepo:       limitRepo,
+		ledgerRepo:      ledgerRepo,
+		transactions:    transactions,
+		outbox:          outbox,
+		idempotency:     idempotency,
+		defaultCurrency: defaultCurrency,
+		logger:          logger,
 	}
 }
 
@@ -32,34 +51,79 @@ func (s *AccountService) CreateAccount(ctx context.Context, req *model.CreateAcc
 		return nil, fmt.Errorf("invalid balance format: %w", err)
 	}
 
+	account, err := inIdempotentTx(ctx, s.db, s.idempotency, s.logger, idempotencyScopeCreateAccount, req.IdempotencyKey, req,
+		func(tx pgx.Tx) (*model.Account, error) {
+			return s.createAccount(ctx, tx, req, balance)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().
+		Int64("account_id", account.ID).
+		Str("balance", account.Balance.String()).
+		Str("currency", account.Currency).
+		Msg("account created successfully")
+
+	return account, nil
+}
+
+// createAccount validates the request and inserts the account inside tx
+func (s *AccountService) createAccount(ctx context.Context, tx pgx.Tx, req *model.CreateAccountRequest, balance decimal.Decimal) (*model.Account, error) {
 	// Check if balance is negative
 	if balance.IsNegative() {
 		return nil, errs.ErrInvalidBalance
 	}
 
+	code := s.defaultCurrency
+	if req.Currency != "" {
+		code = strings.ToUpper(req.Currency)
+	}
+
+	c, ok := currency.Lookup(code)
+	if !ok {
+		return nil, errs.WrapHTTPError(errs.ErrUnsupportedCurrency, "currency %s is not supported", code)
+	}
+
+	if rejection := checkPrecision(c.Code, balance); rejection != nil {
+		return nil, rejection
+	}
+
 	// Check if balance exceeds maximum allowed
-	// NUMERIC(20,5) means max is 999999999999999.99999
-	maxBalance, _ := decimal.NewFromString("999999999999999.99999")
-	if balance.GreaterThan(maxBalance) {
+	if balance.GreaterThan(c.Max()) {
 		return nil, errs.WrapHTTPError(errs.ErrBalanceOverflow, "initial balance exceeds maximum allowed")
 	}
 
 	// Create the account
-	account, err := s.accountRepo.Create(ctx, req.AccountID, balance)
+	account, err := s.accountRepo.Create(ctx, tx, req.AccountID, c.Code)
 	if err != nil {
-		// Check if it's a duplicate key error
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // PostgreSQL unique violation code
+		if err.Error() == "account already exists" {
 			return nil, errs.WrapHTTPError(errs.ErrAccountExists, "account with ID %d already exists", req.AccountID)
 		}
 		s.logger.Error().Err(err).Int64("account_id", req.AccountID).Msg("failed to create account")
 		return nil, fmt.Errorf("failed to create account: %w", err)
 	}
 
-	s.logger.Info().
-		Int64("account_id", account.ID).
-		Str("balance", account.Balance.String()).
-		Msg("account created successfully")
+	// Fund the account with an opening posting so its balance can be rebuilt from the ledger
+	if balance.IsPositive() {
+		entry := &model.LedgerEntry{
+			AccountID: account.ID,
+			Direction: model.LedgerDirectionCredit,
+			EntryType: model.LedgerEntryTypeOpening,
+			Amount:    balance,
+		}
+		if err := s.ledgerRepo.Post(ctx, tx, entry); err != nil {
+			s.logger.Error().Err(err).Int64("account_id", req.AccountID).Msg("failed to post opening balance")
+			return nil, fmt.Errorf("failed to post opening balance: %w", err)
+		}
+		account.Balance = entry.BalanceAfter
+		// The posting updated the row, which bumped its version
+		account.Version++
+	}
+
+	if err := s.outbox.recordAccount(ctx, tx, event.TypeAccountCreated, account); err != nil {
+		return nil, err
+	}
 
 	return account, nil
 }
@@ -74,10 +138,187 @@ func (s *AccountService) GetAccount(ctx context.Context, accountID int64) (*mode
 		return nil, fmt.Errorf("failed to get account: %w", err)
 	}
 
+	return toAccountResponse(account), nil
+}
+
+// UpdateLimits replaces the overdraft limit and maximum balance of an account and records the
+// change in the audit trail. Limits the current balance already breaks are rejected.
+func (s *AccountService) UpdateLimits(ctx context.Context, req *model.UpdateAccountLimitsRequest) (*model.AccountResponse, error) {
+	overdraftLimit, err := decimal.NewFromString(req.OverdraftLimit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount format: %w", err)
+	}
+
+	var maxBalance *decimal.Decimal
+	if req.MaxBalance != nil {
+		parsed, err := decimal.NewFromString(*req.MaxBalance)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount format: %w", err)
+		}
+		maxBalance = &parsed
+	}
+
+	if overdraftLimit.IsNegative() || (maxBalance != nil && maxBalance.IsNegative()) {
+		return nil, errs.ErrValidationError.WithMessage("Limits cannot be negative")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	// Lock the account so no transfer moves the balance while the limits are checked against it
+	account, err := s.accountRepo.GetByIDForUpdate(ctx, tx, req.AccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", req.AccountID)
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	if rejection := checkVersion(account, req.ExpectedVersion); rejection != nil {
+		return nil, rejection
+	}
+
+	if account.Status == model.AccountStatusClosed {
+		return nil, errs.ErrAccountClosed
+	}
+
+	if rejection := checkPrecision(account.Currency, overdraftLimit); rejection != nil {
+		return nil, rejection
+	}
+
+	if maxBalance != nil {
+		if rejection := checkPrecision(account.Currency, *maxBalance); rejection != nil {
+			return nil, rejection
+		}
+	}
+
+	if account.Balance.LessThan(overdraftLimit.Neg()) {
+		return nil, errs.WrapHTTPError(errs.ErrLimitConflictsWithBalance, "balance %s is below the requested overdraft limit", account.Balance.String())
+	}
+
+	if maxBalance != nil && account.Balance.GreaterThan(*maxBalance) {
+		return nil, errs.WrapHTTPError(errs.ErrLimitConflictsWithBalance, "balance %s is above the requested maximum balance", account.Balance.String())
+	}
+
+	change := &model.AccountLimitChange{
+		AccountID:              account.ID,
+		PreviousOverdraftLimit: account.OverdraftLimit,
+		OverdraftLimit:         overdraftLimit,
+		PreviousMaxBalance:     account.MaxBalance,
+		MaxBalance:             maxBalance,
+		ChangedBy:              req.ChangedBy,
+	}
+	change.Reason = optionalString(req.Reason)
+
+	updated, err := s.accountRepo.UpdateLimits(ctx, tx, account.ID, overdraftLimit, maxBalance)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", account.ID).Msg("failed to update account limits")
+		return nil, fmt.Errorf("failed to update account limits: %w", err)
+	}
+
+	if err := s.limitRepo.RecordChange(ctx, tx, change); err != nil {
+		s.logger.Error().Err(err).Int64("account_id", account.ID).Msg("failed to record account limit change")
+		return nil, fmt.Errorf("failed to record account limit change: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	s.logger.Info().
+		Int64("account_id", updated.ID).
+		Str("overdraft_limit", updated.OverdraftLimit.String()).
+		Str("changed_by", req.ChangedBy).
+		Msg("account limits updated")
+
+	return toAccountResponse(updated), nil
+}
+
+// ListLimitChanges returns the audit trail of an account's limit changes, newest first
+func (s *AccountService) ListLimitChanges(ctx context.Context, accountID int64) (*model.ListResponse[*model.AccountLimitChangeResponse], error) {
+	if _, err := s.accountRepo.GetByID(ctx, accountID); err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", accountID)
+		}
+		s.logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to get account")
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	changes, err := s.limitRepo.ListChanges(ctx, accountID)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to list account limit changes")
+		return nil, fmt.Errorf("failed to list account limit changes: %w", err)
+	}
+
+	responses := make([]*model.AccountLimitChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		responses = append(responses, &model.AccountLimitChangeResponse{
+			ID:                     change.ID,
+			AccountID:              change.AccountID,
+			PreviousOverdraftLimit: change.PreviousOverdraftLimit.String(),
+			OverdraftLimit:         change.OverdraftLimit.String(),
+			PreviousMaxBalance:     decimalString(change.PreviousMaxBalance),
+			MaxBalance:             decimalString(change.MaxBalance),
+			ChangedBy:              change.ChangedBy,
+			Reason:                 change.Reason,
+			CreatedAt:              change.CreatedAt,
+		})
+	}
+
+	return &model.ListResponse[*model.AccountLimitChangeResponse]{Data: responses}, nil
+}
+
+// toAccountResponse converts an account into its API representation
+func toAccountResponse(account *model.Account) *model.AccountResponse {
 	return &model.AccountResponse{
-		AccountID: account.ID,
-		Balance:   account.Balance.String(),
-	}, nil
+		AccountID:         account.ID,
+		Currency:          account.Currency,
+		Balance:           account.Balance.String(),
+		LedgerBalance:     account.Balance.String(),
+		AvailableBalance:  account.AvailableBalance().String(),
+		OverdraftLimit:    account.OverdraftLimit.String(),
+		MaxBalance:        decimalString(account.MaxBalance),
+		Status:            account.Status,
+		StatusReason:      account.StatusReason,
+		ClosedAt:          account.ClosedAt,
+		AccountGroup:      account.AccountGroup,
+		InterestProductID: account.InterestProductID,
+		Version:           account.Version,
+	}
+}
+
+// checkVersion rejects a change made against another version of the account than the caller
+// expected. A nil expected version accepts any.
+func checkVersion(account *model.Account, expected *int64) *errs.HTTPError {
+	if expected != nil && account.Version != *expected {
+		return errs.WrapHTTPError(errs.ErrAccountVersionMismatch, "account %d is at version %d, not %d", account.ID, account.Version, *expected)
+	}
+
+	return nil
+}
+
+// decimalString formats an optional amount
+func decimalString(amount *decimal.Decimal) *string {
+	if amount == nil {
+		return nil
+	}
+
+	formatted := amount.String()
+	return &formatted
 }
 
 // ValidateAccountExists checks if an account exists
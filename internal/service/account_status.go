@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/event"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// FreezeAccount blocks debits, or every movement, on an account. An account that is already
+// frozen takes the new scope.
+func (s *AccountService) FreezeAccount(ctx context.Context, req *model.FreezeAccountRequest) (*model.AccountResponse, error) {
+	status := model.AccountStatusFrozenDebit
+	if req.Scope == "all" {
+		status = model.AccountStatusFrozenAll
+	}
+
+	return s.changeStatus(ctx, req.AccountID, req.ExpectedVersion, req.Reason, func(account *model.Account) (model.AccountStatus, error) {
+		if account.Status == model.AccountStatusClosed {
+			return "", errs.ErrAccountClosed
+		}
+		return status, nil
+	})
+}
+
+// UnfreezeAccount makes a frozen account active again
+func (s *AccountService) UnfreezeAccount(ctx context.Context, req *model.UnfreezeAccountRequest) (*model.AccountResponse, error) {
+	return s.changeStatus(ctx, req.AccountID, req.ExpectedVersion, req.Reason, func(account *model.Account) (model.AccountStatus, error) {
+		switch account.Status {
+		case model.AccountStatusFrozenDebit, model.AccountStatusFrozenAll:
+			return model.AccountStatusActive, nil
+		case model.AccountStatusClosed:
+			return "", errs.ErrAccountClosed
+		default:
+			return "", errs.ErrAccountNotFrozen
+		}
+	})
+}
+
+// changeStatus locks the account, asks next for its new status and saves it
+func (s *AccountService) changeStatus(ctx context.Context, accountID int64, expectedVersion *int64, reason string, next func(account *model.Account) (model.AccountStatus, error)) (*model.AccountResponse, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	account, err := s.accountRepo.GetByIDForUpdate(ctx, tx, accountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", accountID)
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	if rejection := checkVersion(account, expectedVersion); rejection != nil {
+		return nil, rejection
+	}
+
+	status, err := next(account)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.accountRepo.UpdateStatus(ctx, tx, accountID, status, optionalString(reason))
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to update account status")
+		return nil, fmt.Errorf("failed to update account status: %w", err)
+	}
+
+	if err := s.outbox.recordAccount(ctx, tx, event.TypeAccountStatusChanged, updated); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	s.logger.Info().
+		Int64("account_id", accountID).
+		Str("previous_status", string(account.Status)).
+		Str("status", string(updated.Status)).
+		Msg("account status changed")
+
+	return toAccountResponse(updated), nil
+}
+
+// CloseAccount closes an account for good. A positive balance must be swept to another account
+// in the same currency, which happens in the same database transaction.
+func (s *AccountService) CloseAccount(ctx context.Context, req *model.CloseAccountRequest) (*model.CloseAccountResponse, error) {
+	response, err := inIdempotentTx(ctx, s.db, s.idempotency, s.logger, idempotencyScopeCloseAccount, req.IdempotencyKey, req.IdempotencyPayload(),
+		func(tx pgx.Tx) (*model.CloseAccountResponse, error) {
+			return s.closeAccount(ctx, tx, req)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	logEvent := s.logger.Info().Int64("account_id", req.AccountID)
+	if response.SweepTransaction != nil {
+		logEvent = logEvent.Int64("sweep_transaction_id", response.SweepTransaction.ID)
+	}
+	logEvent.Msg("account closed")
+
+	return response, nil
+}
+
+func (s *AccountService) closeAccount(ctx context.Context, tx pgx.Tx, req *model.CloseAccountRequest) (*model.CloseAccountResponse, error) {
+	accountIDs := []int64{req.AccountID}
+	if req.SweepAccountID != nil {
+		if *req.SweepAccountID == req.AccountID {
+			return nil, errs.ErrSameAccount
+		}
+		accountIDs = append(accountIDs, *req.SweepAccountID)
+	}
+
+	locked, err := s.transactions.lockAccounts(ctx, tx, accountIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	account, ok := locked[req.AccountID]
+	if !ok {
+		return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", req.AccountID)
+	}
+
+	if rejection := checkVersion(account, req.ExpectedVersion); rejection != nil {
+		return nil, rejection
+	}
+
+	if account.Status == model.AccountStatusClosed {
+		return nil, errs.ErrAccountClosed
+	}
+
+	if account.HeldAmount.IsPositive() {
+		return nil, errs.ErrAccountNotEmpty.WithMessage("Account has active holds, capture or void them before closing")
+	}
+
+	if account.Balance.IsNegative() {
+		return nil, errs.ErrAccountNotEmpty.WithMessage("Account is overdrawn and must be repaid before closing")
+	}
+
+	response := &model.CloseAccountResponse{}
+	if account.Balance.IsPositive() {
+		sweep, err := s.sweep(ctx, tx, req, account, locked)
+		if err != nil {
+			return nil, err
+		}
+		response.SweepTransaction = toTransactionResponse(sweep)
+	}
+
+	closed, err := s.accountRepo.UpdateStatus(ctx, tx, account.ID, model.AccountStatusClosed, optionalString(req.Reason))
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", account.ID).Msg("failed to close account")
+		return nil, fmt.Errorf("failed to close account: %w", err)
+	}
+
+	if err := s.outbox.recordAccount(ctx, tx, event.TypeAccountStatusChanged, closed); err != nil {
+		return nil, err
+	}
+	response.AccountResponse = toAccountResponse(closed)
+
+	return response, nil
+}
+
+// sweep moves the whole balance of a closing account to the sweep account. Every rejection is
+// checked up front, so a failing sweep leaves no failed transaction behind.
+func (s *AccountService) sweep(ctx context.Context, tx pgx.Tx, req *model.CloseAccountRequest, account *model.Account, locked map[int64]*model.Account) (*model.Transaction, error) {
+	if req.SweepAccountID == nil {
+		return nil, errs.ErrAccountNotEmpty
+	}
+
+	destination, ok := locked[*req.SweepAccountID]
+	if !ok {
+		return nil, errs.ErrDestinationAccountNotFound
+	}
+
+	if rejection := checkStatus(account, destination); rejection != nil {
+		return nil, rejection
+	}
+
+	if rejection := checkTransferable(account, destination, account.Balance); rejection != nil {
+		return nil, rejection
+	}
+
+	if rejection := checkCredit(destination, account.Balance); rejection != nil {
+		return nil, rejection
+	}
+
+	transaction := &model.Transaction{
+		SourceAccountID:      account.ID,
+		DestinationAccountID: destination.ID,
+		Amount:               account.Balance,
+		Status:               model.TransactionStatusPending,
+		Kind:                 model.TransactionKindSweep,
+	}
+	if req.Reason != "" {
+		transaction.Reason = &req.Reason
+	}
+
+	if err := s.transactions.execute(ctx, tx, transaction); err != nil {
+		// Everything settle checks was checked above, so a rejection here is unexpected.
+		// Roll back instead of committing a failed sweep.
+		return nil, fmt.Errorf("failed to sweep account %d: %v", account.ID, err)
+	}
+
+	return transaction, nil
+}
+
+// checkStatus rejects a transfer between accounts whose status blocks it
+func checkStatus(source, destination *model.Account) *errs.HTTPError {
+	if rejection := checkDebitStatus(source); rejection != nil {
+		return rejection
+	}
+
+	return checkCreditStatus(destination)
+}
+
+// checkDebitStatus rejects a debit from a frozen or closed account
+func checkDebitStatus(account *model.Account) *errs.HTTPError {
+	if account.Status == model.AccountStatusClosed {
+		return errs.ErrSourceAccountClosed
+	}
+
+	if !account.AcceptsDebits() {
+		return errs.ErrSourceAccountFrozen
+	}
+
+	return nil
+}
+
+// checkCreditStatus rejects a credit to an account that is frozen for credits or closed
+func checkCreditStatus(account *model.Account) *errs.HTTPError {
+	if account.Status == model.AccountStatusClosed {
+		return errs.ErrDestinationAccountClosed
+	}
+
+	if !account.AcceptsCredits() {
+		return errs.ErrDestinationAccountFrozen
+	}
+
+	return nil
+}
+
+// optionalString returns nil for an empty string
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
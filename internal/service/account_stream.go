@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// streamBatchSize is the number of postings read from the ledger at a time
+	streamBatchSize = 100
+	// maxListenBackoff caps the wait between attempts to reconnect the ledger listener
+	maxListenBackoff = 30 * time.Second
+	// listenCloseTimeout bounds releasing the listener connection on shutdown
+	listenCloseTimeout = 5 * time.Second
+)
+
+// AccountStreamService pushes the postings of an account to live subscribers. One connection
+// per instance listens for ledger notifications and wakes the streams of the accounts they
+// name. A woken stream reads what it has not sent yet from the ledger, so a lost notification
+// only delays a posting until the next heartbeat.
+type AccountStreamService struct {
+	db              database.DB
+	accountRepo     repository.AccountRepository
+	transactionRepo repository.TransactionRepository
+	ledgerRepo      repository.LedgerRepository
+	heartbeat       time.Duration
+	logger          *zerolog.Logger
+
+	mu          sync.Mutex
+	subscribers map[int64]map[chan struct{}]struct{}
+	// done is closed when Listen returns, which ends every open stream
+	done chan struct{}
+}
+
+func NewAccountStreamService(db database.DB, accountRepo repository.AccountRepository, transactionRepo repository.TransactionRepository, ledgerRepo repository.LedgerRepository, heartbeat time.Duration, logger *zerolog.Logger) *AccountStreamService {
+	return &AccountStreamService{
+		db:              db,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		ledgerRepo:      ledgerRepo,
+		heartbeat:       heartbeat,
+		logger:          logger,
+		subscribers:     make(map[int64]map[chan struct{}]struct{}),
+		done:            make(chan struct{}),
+	}
+}
+
+// Listen receives ledger notifications until the context is cancelled, reconnecting with backoff
+// when the connection is lost
+func (s *AccountStreamService) Listen(ctx context.Context) error {
+	defer close(s.done)
+
+	delay := time.Second
+	for {
+		connected, err := s.listen(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connected {
+			delay = time.Second
+		}
+
+		s.logger.Error().Err(err).Dur("retry_in", delay).Msg("ledger listener disconnected")
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxListenBackoff)
+	}
+}
+
+// listen holds one listener connection until it fails, and reports whether it got that far
+func (s *AccountStreamService) listen(ctx context.Context) (bool, error) {
+	listener, err := s.db.Listen(ctx, model.LedgerChannel)
+	if err != nil {
+		return false, fmt.Errorf("failed to listen for ledger postings: %w", err)
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), listenCloseTimeout)
+		defer cancel()
+		if err := listener.Close(closeCtx); err != nil {
+			s.logger.Warn().Err(err).Msg("failed to close ledger listener")
+		}
+	}()
+
+	// Postings committed while nobody was listening are only found by reading the ledger
+	s.wakeAll()
+
+	for {
+		notification, err := listener.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+
+		var posted model.LedgerNotification
+		if err := json.Unmarshal([]byte(notification.Payload), &posted); err != nil {
+			s.logger.Warn().Err(err).Str("payload", notification.Payload).Msg("invalid ledger notification")
+			continue
+		}
+
+		s.wake(posted.AccountID)
+	}
+}
+
+// AccountStream is one subscriber's stream of an account. It must be closed when done.
+type AccountStream struct {
+	service   *AccountStreamService
+	accountID int64
+	// after is the last ledger entry the stream has covered, nil until a snapshot is sent
+	after *int64
+	wake  chan struct{}
+}
+
+// OpenStream subscribes to an account. A stream resumed from a Last-Event-ID replays the postings
+// after it; a fresh one starts with a snapshot of the account.
+func (s *AccountStreamService) OpenStream(ctx context.Context, accountID int64, lastEventID *int64) (*AccountStream, error) {
+	_, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", accountID)
+		}
+		s.logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to get account")
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	stream := &AccountStream{
+		service:   s,
+		accountID: accountID,
+		after:     lastEventID,
+		wake:      make(chan struct{}, 1),
+	}
+
+	// Subscribe before the first read, so nothing committed in between goes unnoticed
+	s.mu.Lock()
+	if s.subscribers[accountID] == nil {
+		s.subscribers[accountID] = make(map[chan struct{}]struct{})
+	}
+	s.subscribers[accountID][stream.wake] = struct{}{}
+	s.mu.Unlock()
+
+	return stream, nil
+}
+
+// Close unsubscribes the stream
+func (st *AccountStream) Close() {
+	s := st.service
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.subscribers[st.accountID], st.wake)
+	if len(s.subscribers[st.accountID]) == 0 {
+		delete(s.subscribers, st.accountID)
+	}
+}
+
+// Run sends events until the context is cancelled, the service shuts down or emit fails
+func (st *AccountStream) Run(ctx context.Context, emit func(*model.AccountStreamEvent) error) error {
+	s := st.service
+
+	if st.after == nil {
+		if err := st.snapshot(ctx, emit); err != nil {
+			return err
+		}
+	}
+	if err := st.catchUp(ctx, emit); err != nil {
+		return err
+	}
+
+	heartbeat := time.NewTicker(s.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.done:
+			return nil
+		case <-st.wake:
+		case <-heartbeat.C:
+			if err := emit(&model.AccountStreamEvent{ID: *st.after, Type: model.AccountStreamEventHeartbeat}); err != nil {
+				return err
+			}
+		}
+
+		if err := st.catchUp(ctx, emit); err != nil {
+			return err
+		}
+	}
+}
+
+// snapshot sends the account as it is now. The last entry is read before the account, so a
+// posting that lands in between is sent again rather than missed.
+func (st *AccountStream) snapshot(ctx context.Context, emit func(*model.AccountStreamEvent) error) error {
+	s := st.service
+
+	after, err := s.ledgerRepo.GetLastEntryID(ctx, st.accountID)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", st.accountID).Msg("failed to get last ledger entry")
+		return fmt.Errorf("failed to get last ledger entry: %w", err)
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, st.accountID)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", st.accountID).Msg("failed to get account")
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	st.after = &after
+	return emit(&model.AccountStreamEvent{ID: after, Type: model.AccountStreamEventAccount, Data: toAccountResponse(account)})
+}
+
+// catchUp sends every posting after the last one the stream covered
+func (st *AccountStream) catchUp(ctx context.Context, emit func(*model.AccountStreamEvent) error) error {
+	s := st.service
+
+	for {
+		postings, err := s.transactionRepo.ListPostings(ctx, st.accountID, *st.after, streamBatchSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			s.logger.Error().Err(err).Int64("account_id", st.accountID).Msg("failed to list postings")
+			return fmt.Errorf("failed to list postings: %w", err)
+		}
+
+		for _, posting := range postings {
+			if err := emit(&model.AccountStreamEvent{ID: posting.EntryID, Type: model.AccountStreamEventPosting, Data: toAccountPostingResponse(st.accountID, posting)}); err != nil {
+				return err
+			}
+			st.after = &posting.EntryID
+		}
+
+		if len(postings) < streamBatchSize {
+			return nil
+		}
+	}
+}
+
+// wake signals the streams of an account without blocking. A stream that is already due to
+// read the ledger does not need a second signal.
+func (s *AccountStreamService) wake(accountID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for wake := range s.subscribers[accountID] {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// wakeAll signals every open stream
+func (s *AccountStreamService) wakeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, streams := range s.subscribers {
+		for wake := range streams {
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// toAccountPostingResponse converts a posting into the data of a posting event
+func toAccountPostingResponse(accountID int64, posting *model.AccountPosting) *model.AccountPostingResponse {
+	response := &model.AccountPostingResponse{
+		EntryID:     posting.EntryID,
+		AccountID:   accountID,
+		Transaction: toAccountTransactionResponse(accountID, &posting.AccountTransaction),
+	}
+	if posting.RunningBalance != nil {
+		response.Balance = posting.RunningBalance.String()
+	}
+
+	return response
+}
@@ -0,0 +1,10 @@
+package service
+
+import "context"
+
+// ExecutePending settles up to limit transfers queued with ?mode=async, oldest first, and
+// returns how many it processed. Every worker of the pool runs this concurrently; each
+// transfer is claimed by exactly one of them and settled in its own database transaction.
+func (s *TransactionService) ExecutePending(ctx context.Context, limit int) (int, error) {
+	return s.executeClaimed(ctx, limit, "async", s.transactionRepo.ClaimPending)
+}
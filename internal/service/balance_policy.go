@@ -0,0 +1,45 @@
+package service
+
+import (
+	"github.com/chandra-shekhar/internal-transfers/internal/currency"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// checkDebit rejects a debit that would take more than the account's available balance plus
+// its overdraft limit. The account must be locked.
+func checkDebit(account *model.Account, amount decimal.Decimal) *errs.HTTPError {
+	if account.SpendableBalance().LessThan(amount) {
+		return errs.ErrInsufficientBalance
+	}
+
+	return nil
+}
+
+// checkCredit rejects a credit that would take the balance above the account's maximum, or
+// above the largest amount its currency can store. The account must be locked.
+func checkCredit(account *model.Account, amount decimal.Decimal) *errs.HTTPError {
+	limit := maxBalance(account)
+	if account.Balance.Add(amount).GreaterThan(limit) {
+		return errs.WrapHTTPError(errs.ErrBalanceOverflow, "credit would take account %d above its maximum balance of %s",
+			account.ID, limit.String())
+	}
+
+	return nil
+}
+
+// maxBalance is the highest balance the account may hold
+func maxBalance(account *model.Account) decimal.Decimal {
+	// Every stored currency is known; the storage scale only matters for a corrupt row
+	limit := currency.Currency{Code: account.Currency, Precision: currency.StorageScale}.Max()
+	if c, ok := currency.Lookup(account.Currency); ok {
+		limit = c.Max()
+	}
+
+	if account.MaxBalance != nil && account.MaxBalance.LessThan(limit) {
+		limit = *account.MaxBalance
+	}
+
+	return limit
+}
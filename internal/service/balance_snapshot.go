@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/interest"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+// maxSnapshotDaysPerRun bounds how many days one run of the snapshot job catches up on
+const maxSnapshotDaysPerRun = 31
+
+// BalanceSnapshotService takes end-of-day balance snapshots and answers what an account held
+// at any point in time from them
+type BalanceSnapshotService struct {
+	db           database.DB
+	accountRepo  repository.AccountRepository
+	snapshotRepo repository.BalanceSnapshotRepository
+	// delay is how long after midnight UTC a day is snapshotted, so that transfers which
+	// started before midnight have committed
+	delay  time.Duration
+	logger *zerolog.Logger
+}
+
+func NewBalanceSnapshotService(db database.DB, accountRepo repository.AccountRepository, snapshotRepo repository.BalanceSnapshotRepository, delay time.Duration, logger *zerolog.Logger) *BalanceSnapshotService {
+	return &BalanceSnapshotService{
+		db:           db,
+		accountRepo:  accountRepo,
+		snapshotRepo: snapshotRepo,
+		delay:        delay,
+		logger:       logger,
+	}
+}
+
+// GetBalance returns the balance of an account at the requested time, or now
+func (s *BalanceSnapshotService) GetBalance(ctx context.Context, req *model.GetAccountBalanceRequest) (*model.AccountBalanceResponse, error) {
+	now := time.Now()
+	asOf := now
+	if req.AsOf != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, req.AsOf)
+		if err != nil {
+			return nil, errs.ErrInvalidFormat.WithMessage("as_of must be an RFC 3339 timestamp")
+		}
+		if parsed.After(now) {
+			return nil, errs.ErrInvalidFormat.WithMessage("as_of must not be in the future")
+		}
+		asOf = parsed
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, req.AccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", req.AccountID)
+		}
+		s.logger.Error().Err(err).Int64("account_id", req.AccountID).Msg("failed to get account")
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	if asOf.Before(account.CreatedAt) {
+		return nil, errs.WrapHTTPError(errs.ErrAccountNotOpenAsOf, "account %d was opened at %s", account.ID, account.CreatedAt.UTC().Format(time.RFC3339))
+	}
+
+	balance, err := s.snapshotRepo.GetBalanceAsOf(ctx, account.ID, asOf)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", account.ID).Time("as_of", asOf).Msg("failed to get historical balance")
+		return nil, fmt.Errorf("failed to get historical balance: %w", err)
+	}
+
+	return &model.AccountBalanceResponse{
+		AccountID: account.ID,
+		Currency:  account.Currency,
+		Balance:   balance.String(),
+		AsOf:      asOf.UTC(),
+	}, nil
+}
+
+// TakeDue snapshots, oldest first, every day that has not been snapshotted yet and ended at
+// least the delay ago, and returns how many days it took. The first run starts at the day of
+// the first ledger entry.
+func (s *BalanceSnapshotService) TakeDue(ctx context.Context, now time.Time) (int, error) {
+	last, err := s.snapshotRepo.GetLastRunDate(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to get last balance snapshot run")
+		return 0, fmt.Errorf("failed to get last balance snapshot run: %w", err)
+	}
+
+	var next time.Time
+	if last != nil {
+		next = interest.Date(*last).AddDate(0, 0, 1)
+	} else {
+		first, err := s.snapshotRepo.GetFirstPostingTime(ctx)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("failed to get first ledger entry")
+			return 0, fmt.Errorf("failed to get first ledger entry: %w", err)
+		}
+		if first == nil {
+			return 0, nil
+		}
+		next = interest.Date(*first)
+	}
+
+	taken := 0
+	for ; taken < maxSnapshotDaysPerRun && !now.Before(next.AddDate(0, 0, 1).Add(s.delay)); taken++ {
+		if err := s.takeDay(ctx, next); err != nil {
+			return taken, err
+		}
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return taken, nil
+}
+
+// RunDue is the body of the background job
+func (s *BalanceSnapshotService) RunDue(ctx context.Context) error {
+	_, err := s.TakeDue(ctx, time.Now())
+	return err
+}
+
+// takeDay writes the snapshots of one day in a transaction
+func (s *BalanceSnapshotService) takeDay(ctx context.Context, date time.Time) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to begin transaction")
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	accounts, err := s.snapshotRepo.TakeDay(ctx, tx, date)
+	if err != nil {
+		if err.Error() == "balance snapshot already taken" {
+			// Another instance took the day while this one was waiting
+			return nil
+		}
+		s.logger.Error().Err(err).Str("date", date.Format(time.DateOnly)).Msg("failed to take balance snapshots")
+		return fmt.Errorf("failed to take balance snapshots: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("failed to commit transaction")
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	s.logger.Info().Str("date", date.Format(time.DateOnly)).Int64("accounts", accounts).Msg("balance snapshots taken")
+
+	return nil
+}
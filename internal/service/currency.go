@@ -0,0 +1,42 @@
+package service
+
+import (
+	"github.com/chandra-shekhar/internal-transfers/internal/currency"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/shopspring/decimal"
+)
+
+// checkSameCurrency rejects a transfer between accounts that hold different currencies
+func checkSameCurrency(source, destination *model.Account) *errs.HTTPError {
+	if source.Currency != destination.Currency {
+		return errs.WrapHTTPError(errs.ErrCurrencyMismatch, "source account is in %s but destination account is in %s",
+			source.Currency, destination.Currency)
+	}
+
+	return nil
+}
+
+// checkPrecision rejects an amount with more decimal places than its currency allows
+func checkPrecision(code string, amount decimal.Decimal) *errs.HTTPError {
+	c, ok := currency.Lookup(code)
+	if !ok {
+		return errs.WrapHTTPError(errs.ErrUnsupportedCurrency, "currency %s is not supported", code)
+	}
+
+	if !c.Fits(amount) {
+		return errs.WrapHTTPError(errs.ErrInvalidAmountPrecision, "%s amounts allow at most %d decimal places", c.Code, c.Precision)
+	}
+
+	return nil
+}
+
+// checkTransferable runs the currency checks every transfer of amount from source to
+// destination has to pass
+func checkTransferable(source, destination *model.Account, amount decimal.Decimal) *errs.HTTPError {
+	if rejection := checkSameCurrency(source, destination); rejection != nil {
+		return rejection
+	}
+
+	return checkPrecision(source.Currency, amount)
+}
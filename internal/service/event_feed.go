@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultEventFeedLimit is the page size of the event feed when none is requested
+	DefaultEventFeedLimit = 100
+	// feedPollInterval is how often a waiting request checks the feed for new events
+	feedPollInterval = 500 * time.Millisecond
+)
+
+// EventFeedService serves the published events as a feed that consumers pull from in order
+type EventFeedService struct {
+	feedRepo repository.EventFeedRepository
+	logger   *zerolog.Logger
+}
+
+func NewEventFeedService(feedRepo repository.EventFeedRepository, logger *zerolog.Logger) *EventFeedService {
+	return &EventFeedService{
+		feedRepo: feedRepo,
+		logger:   logger,
+	}
+}
+
+// ListEvents returns the events after the requested cursor, or after the offset the consumer
+// group committed; a group that has never committed starts at the beginning. When there are
+// no events yet and a wait is requested, the feed is checked again until one arrives or the
+// wait runs out.
+func (s *EventFeedService) ListEvents(ctx context.Context, req *model.ListEventsRequest) (*model.EventFeedResponse, error) {
+	if req.After != "" && req.Group != "" {
+		return nil, errs.ErrInvalidRequest.WithMessage("Pass either after or group, not both")
+	}
+
+	after, err := s.startPosition(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultEventFeedLimit
+	}
+
+	deadline := time.Now().Add(time.Duration(req.Wait) * time.Second)
+
+	var events []*model.FeedEvent
+	for {
+		// Fetch one extra event to learn whether more follow
+		events, err = s.feedRepo.ListAfter(ctx, after, limit+1)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			s.logger.Error().Err(err).Int64("after", after).Msg("failed to list events")
+			return nil, fmt.Errorf("failed to list events: %w", err)
+		}
+
+		remaining := time.Until(deadline)
+		if len(events) > 0 || remaining <= 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(min(feedPollInterval, remaining)):
+		}
+	}
+
+	response := &model.EventFeedResponse{
+		Data:       make([]model.FeedEventResponse, 0, len(events)),
+		Limit:      limit,
+		NextCursor: model.FeedCursor(after).Encode(),
+	}
+
+	if len(events) > limit {
+		events = events[:limit]
+		response.HasMore = true
+	}
+
+	for _, e := range events {
+		cursor := model.FeedCursor(e.Position).Encode()
+		response.Data = append(response.Data, model.FeedEventResponse{Cursor: cursor, Event: e.Event})
+		response.NextCursor = cursor
+	}
+
+	return response, nil
+}
+
+// startPosition resolves where a read of the feed starts
+func (s *EventFeedService) startPosition(ctx context.Context, req *model.ListEventsRequest) (int64, error) {
+	if req.After != "" {
+		cursor, err := model.DecodeFeedCursor(req.After)
+		if err != nil {
+			return 0, errs.ErrInvalidFormat.WithMessage("Invalid cursor")
+		}
+		return int64(cursor), nil
+	}
+
+	if req.Group == "" {
+		return 0, nil
+	}
+
+	group, err := s.feedRepo.GetConsumerGroup(ctx, req.Group)
+	if err != nil {
+		if err.Error() == "consumer group not found" {
+			return 0, nil
+		}
+		s.logger.Error().Err(err).Str("group", req.Group).Msg("failed to get consumer group")
+		return 0, fmt.Errorf("failed to get consumer group: %w", err)
+	}
+
+	return group.Position, nil
+}
+
+func (s *EventFeedService) GetConsumerGroup(ctx context.Context, name string) (*model.ConsumerGroupResponse, error) {
+	group, err := s.feedRepo.GetConsumerGroup(ctx, name)
+	if err != nil {
+		if err.Error() == "consumer group not found" {
+			return nil, errs.WrapHTTPError(errs.ErrConsumerGroupNotFound, "consumer group %s not found", name)
+		}
+		s.logger.Error().Err(err).Str("group", name).Msg("failed to get consumer group")
+		return nil, fmt.Errorf("failed to get consumer group: %w", err)
+	}
+
+	return toConsumerGroupResponse(group), nil
+}
+
+func (s *EventFeedService) ListConsumerGroups(ctx context.Context) (*model.ListResponse[*model.ConsumerGroupResponse], error) {
+	groups, err := s.feedRepo.ListConsumerGroups(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list consumer groups")
+		return nil, fmt.Errorf("failed to list consumer groups: %w", err)
+	}
+
+	responses := make([]*model.ConsumerGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		responses = append(responses, toConsumerGroupResponse(group))
+	}
+
+	return &model.ListResponse[*model.ConsumerGroupResponse]{Data: responses}, nil
+}
+
+// CommitOffset records that a consumer group has processed every event up to and including the
+// cursor. Offsets only move forward, so a consumer that lost track and commits an older cursor
+// learns that another member got further instead of causing events to be processed twice.
+func (s *EventFeedService) CommitOffset(ctx context.Context, req *model.CommitConsumerGroupOffsetRequest) (*model.ConsumerGroupResponse, error) {
+	cursor, err := model.DecodeFeedCursor(req.Cursor)
+	if err != nil {
+		return nil, errs.ErrInvalidFormat.WithMessage("Invalid cursor")
+	}
+
+	last, err := s.feedRepo.GetLastPosition(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to get last event position")
+		return nil, fmt.Errorf("failed to get last event position: %w", err)
+	}
+	if int64(cursor) > last {
+		return nil, errs.ErrCursorAheadOfFeed
+	}
+
+	group, err := s.feedRepo.CommitOffset(ctx, req.Name, int64(cursor))
+	if err != nil {
+		if err.Error() == "consumer group offset is ahead" {
+			return nil, errs.WrapHTTPError(errs.ErrConsumerGroupOffsetBehind, "consumer group %s has already committed a later offset", req.Name)
+		}
+		s.logger.Error().Err(err).Str("group", req.Name).Msg("failed to commit consumer group offset")
+		return nil, fmt.Errorf("failed to commit consumer group offset: %w", err)
+	}
+
+	s.logger.Info().Str("group", group.Name).Int64("position", group.Position).Msg("consumer group offset committed")
+
+	return toConsumerGroupResponse(group), nil
+}
+
+// DeleteConsumerGroup forgets a consumer group, which then starts again from the beginning
+func (s *EventFeedService) DeleteConsumerGroup(ctx context.Context, name string) error {
+	if err := s.feedRepo.DeleteConsumerGroup(ctx, name); err != nil {
+		if err.Error() == "consumer group not found" {
+			return errs.WrapHTTPError(errs.ErrConsumerGroupNotFound, "consumer group %s not found", name)
+		}
+		s.logger.Error().Err(err).Str("group", name).Msg("failed to delete consumer group")
+		return fmt.Errorf("failed to delete consumer group: %w", err)
+	}
+
+	s.logger.Info().Str("group", name).Msg("consumer group deleted")
+
+	return nil
+}
+
+func toConsumerGroupResponse(group *model.ConsumerGroup) *model.ConsumerGroupResponse {
+	return &model.ConsumerGroupResponse{
+		Name:      group.Name,
+		Cursor:    model.FeedCursor(group.Position).Encode(),
+		CreatedAt: group.CreatedAt,
+		UpdatedAt: group.UpdatedAt,
+	}
+}
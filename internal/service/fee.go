@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/currency"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/fee"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type FeeService struct {
+	accountRepo repository.AccountRepository
+	feeRuleRepo repository.FeeRuleRepository
+	logger      *zerolog.Logger
+}
+
+func NewFeeService(accountRepo repository.AccountRepository, feeRuleRepo repository.FeeRuleRepository, logger *zerolog.Logger) *FeeService {
+	return &FeeService{
+		accountRepo: accountRepo,
+		feeRuleRepo: feeRuleRepo,
+		logger:      logger,
+	}
+}
+
+// CreateRule adds a fee rule after checking that its pricing fits its kind and currency
+func (s *FeeService) CreateRule(ctx context.Context, req *model.CreateFeeRuleRequest) (*model.FeeRuleResponse, error) {
+	c, ok := currency.Lookup(req.Currency)
+	if !ok {
+		return nil, errs.WrapHTTPError(errs.ErrUnsupportedCurrency, "currency %s is not supported", req.Currency)
+	}
+
+	rule := &model.FeeRule{
+		Name:                 req.Name,
+		Kind:                 req.Kind,
+		Currency:             c.Code,
+		SourceAccountID:      req.SourceAccountID,
+		DestinationAccountID: req.DestinationAccountID,
+		Payer:                req.Payer,
+		Priority:             req.Priority,
+	}
+	if rule.Payer == "" {
+		rule.Payer = model.FeePayerSender
+	}
+
+	if req.Kind == model.FeeRuleWaiver {
+		if req.SourceAccountID == nil || req.DestinationAccountID == nil {
+			return nil, errs.ErrValidationError.WithMessage("A waiver needs both source_account_id and destination_account_id")
+		}
+		if req.RevenueAccountID != nil {
+			return nil, errs.ErrValidationError.WithMessage("A waiver charges nothing and takes no revenue_account_id")
+		}
+	} else {
+		if err := s.priceRule(rule, req, c); err != nil {
+			return nil, err
+		}
+
+		if req.RevenueAccountID == nil {
+			return nil, errs.ErrValidationError.WithMessage("revenue_account_id is required")
+		}
+		revenueAccount, err := s.getAccount(ctx, *req.RevenueAccountID)
+		if err != nil {
+			return nil, err
+		}
+		if revenueAccount.Currency != c.Code {
+			return nil, errs.WrapHTTPError(errs.ErrCurrencyMismatch, "revenue account %d holds %s, the rule charges %s",
+				revenueAccount.ID, revenueAccount.Currency, c.Code)
+		}
+		rule.RevenueAccountID = req.RevenueAccountID
+	}
+
+	for _, accountID := range []*int64{req.SourceAccountID, req.DestinationAccountID} {
+		if accountID == nil {
+			continue
+		}
+		if _, err := s.getAccount(ctx, *accountID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.feeRuleRepo.Create(ctx, rule); err != nil {
+		s.logger.Error().Err(err).Msg("failed to create fee rule")
+		return nil, fmt.Errorf("failed to create fee rule: %w", err)
+	}
+
+	s.logger.Info().
+		Int64("fee_rule_id", rule.ID).
+		Str("kind", string(rule.Kind)).
+		Str("currency", rule.Currency).
+		Msg("fee rule created")
+
+	return toFeeRuleResponse(rule), nil
+}
+
+// priceRule parses the pricing fields of the request into rule and validates the schedule
+func (s *FeeService) priceRule(rule *model.FeeRule, req *model.CreateFeeRuleRequest, c currency.Currency) error {
+	var err error
+	if rule.FlatAmount, err = parseFeeAmount("flat_amount", req.FlatAmount, c); err != nil {
+		return err
+	}
+	if rule.MinFee, err = parseFeeAmount("min_fee", req.MinFee, c); err != nil {
+		return err
+	}
+	if rule.MaxFee, err = parseFeeAmount("max_fee", req.MaxFee, c); err != nil {
+		return err
+	}
+	if req.Rate != nil {
+		rate, err := decimal.NewFromString(*req.Rate)
+		if err != nil {
+			return errs.ErrInvalidFormat.WithMessage("Invalid rate format")
+		}
+		rule.Rate = &rate
+	}
+
+	for i, tier := range req.Tiers {
+		var parsed fee.Tier
+		if tier.UpTo != nil {
+			if parsed.UpTo, err = parseFeeAmount(fmt.Sprintf("tiers[%d].up_to", i), tier.UpTo, c); err != nil {
+				return err
+			}
+		}
+		if tier.Flat != "" {
+			flat, err := parseFeeAmount(fmt.Sprintf("tiers[%d].flat", i), &tier.Flat, c)
+			if err != nil {
+				return err
+			}
+			parsed.Flat = *flat
+		}
+		if tier.Rate != "" {
+			if parsed.Rate, err = decimal.NewFromString(tier.Rate); err != nil {
+				return errs.ErrInvalidFormat.WithMessage(fmt.Sprintf("Invalid tiers[%d].rate format", i))
+			}
+		}
+		rule.Tiers = append(rule.Tiers, parsed)
+	}
+
+	// Fields of other kinds would be stored but never used, so they are refused
+	switch rule.Kind {
+	case model.FeeRuleFlat:
+		if rule.Rate != nil || rule.MinFee != nil || rule.MaxFee != nil || len(rule.Tiers) > 0 {
+			return errs.ErrValidationError.WithMessage("A flat rule only takes flat_amount")
+		}
+	case model.FeeRulePercentage:
+		if rule.FlatAmount != nil || len(rule.Tiers) > 0 {
+			return errs.ErrValidationError.WithMessage("A percentage rule takes rate, min_fee and max_fee")
+		}
+	case model.FeeRuleTiered:
+		if rule.FlatAmount != nil || rule.Rate != nil || rule.MinFee != nil || rule.MaxFee != nil {
+			return errs.ErrValidationError.WithMessage("A tiered rule only takes tiers")
+		}
+	}
+
+	if err := rule.Schedule().Validate(); err != nil {
+		return errs.ErrValidationError.WithMessage(err.Error())
+	}
+
+	return nil
+}
+
+// parseFeeAmount parses an optional fee amount that must fit the precision of c
+func parseFeeAmount(field string, value *string, c currency.Currency) (*decimal.Decimal, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	amount, err := decimal.NewFromString(*value)
+	if err != nil {
+		return nil, errs.ErrInvalidFormat.WithMessage("Invalid " + field + " format")
+	}
+	if amount.IsNegative() {
+		return nil, errs.ErrValidationError.WithMessage(field + " cannot be negative")
+	}
+	if rejection := checkPrecision(c.Code, amount); rejection != nil {
+		return nil, rejection
+	}
+
+	return &amount, nil
+}
+
+// getAccount returns an account a rule refers to
+func (s *FeeService) getAccount(ctx context.Context, accountID int64) (*model.Account, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", accountID)
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	return account, nil
+}
+
+// ListRules returns the fee rules matching the filter
+func (s *FeeService) ListRules(ctx context.Context, req *model.ListFeeRulesRequest) (*model.ListResponse[*model.FeeRuleResponse], error) {
+	rules, err := s.feeRuleRepo.List(ctx, req)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list fee rules")
+		return nil, fmt.Errorf("failed to list fee rules: %w", err)
+	}
+
+	responses := make([]*model.FeeRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		responses = append(responses, toFeeRuleResponse(rule))
+	}
+
+	return &model.ListResponse[*model.FeeRuleResponse]{Data: responses}, nil
+}
+
+// DeleteRule deactivates a fee rule so it no longer matches new transfers
+func (s *FeeService) DeleteRule(ctx context.Context, ruleID int64) error {
+	if err := s.feeRuleRepo.Deactivate(ctx, ruleID); err != nil {
+		if err.Error() == "fee rule not found" {
+			return errs.WrapHTTPError(errs.ErrFeeRuleNotFound, "fee rule with ID %d not found", ruleID)
+		}
+		s.logger.Error().Err(err).Int64("fee_rule_id", ruleID).Msg("failed to deactivate fee rule")
+		return fmt.Errorf("failed to deactivate fee rule: %w", err)
+	}
+
+	s.logger.Info().Int64("fee_rule_id", ruleID).Msg("fee rule deactivated")
+
+	return nil
+}
+
+// assess prices a transfer with the rules that match it. A waiver for the account pair beats
+// every other rule; otherwise the rule with the lowest priority charges. It returns nil when
+// no rule matches.
+func (s *FeeService) assess(ctx context.Context, tx pgx.Tx, transaction *model.Transaction) (*model.TransactionFee, error) {
+	rules, err := s.feeRuleRepo.ListMatching(ctx, tx, transaction.SourceAccountID, transaction.DestinationAccountID)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list matching fee rules")
+		return nil, fmt.Errorf("failed to list matching fee rules: %w", err)
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	for _, rule := range rules {
+		if rule.Kind == model.FeeRuleWaiver {
+			return &model.TransactionFee{RuleID: rule.ID, Amount: decimal.Zero, Payer: rule.Payer, Waived: true}, nil
+		}
+	}
+
+	rule := rules[0]
+	c, ok := currency.Lookup(rule.Currency)
+	if !ok {
+		return nil, fmt.Errorf("fee rule %d charges unsupported currency %s", rule.ID, rule.Currency)
+	}
+
+	amount, err := rule.Schedule().Compute(transaction.Amount, c.Precision)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("fee_rule_id", rule.ID).Msg("failed to compute fee")
+		return nil, fmt.Errorf("failed to compute fee with rule %d: %w", rule.ID, err)
+	}
+
+	return &model.TransactionFee{
+		RuleID:           rule.ID,
+		Amount:           amount,
+		Payer:            rule.Payer,
+		RevenueAccountID: rule.RevenueAccountID,
+	}, nil
+}
+
+// checkRevenueAccount makes sure the locked revenue account can take a fee in the currency
+// it is charged in. A rule pointing at an unusable account is a configuration error rather
+// than a reason to reject the customer's transfer.
+func (s *FeeService) checkRevenueAccount(transactionFee *model.TransactionFee, locked map[int64]*model.Account, feeCurrency string) error {
+	revenueAccount, ok := locked[*transactionFee.RevenueAccountID]
+	if !ok || revenueAccount.Currency != feeCurrency {
+		s.logger.Error().Int64("fee_rule_id", transactionFee.RuleID).Int64("account_id", *transactionFee.RevenueAccountID).
+			Msg("fee revenue account is missing or holds another currency")
+		return fmt.Errorf("revenue account %d of fee rule %d cannot take %s fees", *transactionFee.RevenueAccountID, transactionFee.RuleID, feeCurrency)
+	}
+
+	if rejection := checkCreditStatus(revenueAccount); rejection != nil {
+		s.logger.Error().Int64("fee_rule_id", transactionFee.RuleID).Int64("account_id", revenueAccount.ID).
+			Msg("fee revenue account does not accept credits")
+		return fmt.Errorf("revenue account %d of fee rule %d: %s", revenueAccount.ID, transactionFee.RuleID, rejection.Message)
+	}
+
+	if rejection := checkCredit(revenueAccount, transactionFee.Amount); rejection != nil {
+		s.logger.Error().Int64("fee_rule_id", transactionFee.RuleID).Int64("account_id", revenueAccount.ID).
+			Msg("fee revenue account is full")
+		return fmt.Errorf("revenue account %d of fee rule %d: %s", revenueAccount.ID, transactionFee.RuleID, rejection.Message)
+	}
+
+	return nil
+}
+
+// feeCharged reports whether a fee moves money, as opposed to a waiver or a zero fee
+func feeCharged(transactionFee *model.TransactionFee) bool {
+	return transactionFee != nil && !transactionFee.Waived && transactionFee.Amount.IsPositive()
+}
+
+// toFeeRuleResponse converts a fee rule into its API representation
+func toFeeRuleResponse(rule *model.FeeRule) *model.FeeRuleResponse {
+	response := &model.FeeRuleResponse{
+		ID:                   rule.ID,
+		Name:                 rule.Name,
+		Kind:                 rule.Kind,
+		Currency:             rule.Currency,
+		FlatAmount:           decimalString(rule.FlatAmount),
+		Rate:                 decimalString(rule.Rate),
+		MinFee:               decimalString(rule.MinFee),
+		MaxFee:               decimalString(rule.MaxFee),
+		SourceAccountID:      rule.SourceAccountID,
+		DestinationAccountID: rule.DestinationAccountID,
+		Payer:                rule.Payer,
+		RevenueAccountID:     rule.RevenueAccountID,
+		Priority:             rule.Priority,
+		Active:               rule.Active,
+		CreatedAt:            rule.CreatedAt,
+	}
+
+	for _, tier := range rule.Tiers {
+		response.Tiers = append(response.Tiers, model.FeeTier{
+			UpTo: decimalString(tier.UpTo),
+			Flat: tier.Flat.String(),
+			Rate: tier.Rate.String(),
+		})
+	}
+
+	return response
+}
+
+// toTransactionFeeResponse converts the fee of a transfer into its API representation
+func toTransactionFeeResponse(transactionFee *model.TransactionFee, feeCurrency string) *model.TransactionFeeResponse {
+	return &model.TransactionFeeResponse{
+		Amount:           transactionFee.Amount.String(),
+		Currency:         feeCurrency,
+		PaidBy:           transactionFee.Payer,
+		Waived:           transactionFee.Waived,
+		RuleID:           transactionFee.RuleID,
+		RevenueAccountID: transactionFee.RevenueAccountID,
+		TransactionID:    transactionFee.TransactionID,
+	}
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/currency"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/fx"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type FXService struct {
+	accountRepo repository.AccountRepository
+	fxRepo      repository.FXRepository
+	rates       fx.RateProvider
+	spread      decimal.Decimal
+	quoteTTL    time.Duration
+	// pnlAccounts holds, per destination currency, the account credited with rounding remainders.
+	// Conversion into a currency without one is not offered.
+	pnlAccounts map[string]int64
+	logger      *zerolog.Logger
+}
+
+func NewFXService(accountRepo repository.AccountRepository, fxRepo repository.FXRepository, rates fx.RateProvider, spread decimal.Decimal, quoteTTL time.Duration, pnlAccounts map[string]int64, logger *zerolog.Logger) *FXService {
+	return &FXService{
+		accountRepo: accountRepo,
+		fxRepo:      fxRepo,
+		rates:       rates,
+		spread:      spread,
+		quoteTTL:    quoteTTL,
+		pnlAccounts: pnlAccounts,
+		logger:      logger,
+	}
+}
+
+// CreateQuote prices a transfer between two accounts of different currencies. The quote can be
+// executed once, by passing its ID to a transfer of the same amount, until it expires.
+func (s *FXService) CreateQuote(ctx context.Context, req *model.CreateFXQuoteRequest) (*model.FXQuoteResponse, error) {
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount format: %w", err)
+	}
+
+	if !amount.IsPositive() {
+		return nil, errs.ErrAmountMustBePositive
+	}
+
+	sourceAccount, err := s.accountRepo.GetByID(ctx, req.SourceAccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.ErrSourceAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to verify source account: %w", err)
+	}
+
+	destinationAccount, err := s.accountRepo.GetByID(ctx, req.DestinationAccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.ErrDestinationAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to verify destination account: %w", err)
+	}
+
+	if sourceAccount.Currency == destinationAccount.Currency {
+		return nil, errs.ErrValidationError.WithMessage("Both accounts hold " + sourceAccount.Currency + ", no conversion is needed")
+	}
+
+	if rejection := checkPrecision(sourceAccount.Currency, amount); rejection != nil {
+		return nil, rejection
+	}
+
+	if _, ok := s.pnlAccounts[destinationAccount.Currency]; !ok {
+		return nil, s.unavailable(sourceAccount.Currency, destinationAccount.Currency)
+	}
+
+	rate, err := s.rate(ctx, sourceAccount.Currency, destinationAccount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	conversion, err := convert(amount, rate.Value, s.spread, destinationAccount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &model.FXQuote{
+		SourceCurrency:      sourceAccount.Currency,
+		DestinationCurrency: destinationAccount.Currency,
+		Amount:              amount,
+		ConvertedAmount:     conversion.Amount,
+		Rate:                rate.Value,
+		Spread:              s.spread,
+		RateTimestamp:       rate.Timestamp,
+		ExpiresAt:           time.Now().Add(s.quoteTTL),
+	}
+	if err := s.fxRepo.CreateQuote(ctx, quote); err != nil {
+		s.logger.Error().Err(err).Msg("failed to create fx quote")
+		return nil, fmt.Errorf("failed to create fx quote: %w", err)
+	}
+
+	s.logger.Info().
+		Int64("quote_id", quote.ID).
+		Str("pair", quote.SourceCurrency+"/"+quote.DestinationCurrency).
+		Str("rate", quote.Rate.String()).
+		Msg("fx quote created")
+
+	return toFXQuoteResponse(quote), nil
+}
+
+// GetQuote retrieves a quote by its ID
+func (s *FXService) GetQuote(ctx context.Context, quoteID int64) (*model.FXQuoteResponse, error) {
+	quote, err := s.fxRepo.GetQuote(ctx, quoteID)
+	if err != nil {
+		if err.Error() == "fx quote not found" {
+			return nil, errs.WrapHTTPError(errs.ErrFXQuoteNotFound, "fx quote with ID %d not found", quoteID)
+		}
+		s.logger.Error().Err(err).Int64("quote_id", quoteID).Msg("failed to get fx quote")
+		return nil, fmt.Errorf("failed to get fx quote: %w", err)
+	}
+
+	return toFXQuoteResponse(quote), nil
+}
+
+// pnlAccountFor returns the remainder account for conversions into the currency of the
+// destination account, so it can be locked along with the transfer's accounts. The currency
+// of an account never changes, which makes reading it without a lock safe.
+func (s *FXService) pnlAccountFor(ctx context.Context, destinationAccountID int64) (int64, bool, error) {
+	destinationAccount, err := s.accountRepo.GetByID(ctx, destinationAccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to verify destination account: %w", err)
+	}
+
+	accountID, ok := s.pnlAccounts[destinationAccount.Currency]
+	return accountID, ok, nil
+}
+
+// conversionFor prices a cross-currency transaction inside tx, from its quote when it has one
+// and at the current rate otherwise. The quote stays locked until useQuote marks it used.
+func (s *FXService) conversionFor(ctx context.Context, tx pgx.Tx, transaction *model.Transaction, sourceCurrency, destinationCurrency string) (*model.FXConversion, error) {
+	pnlAccountID, ok := s.pnlAccounts[destinationCurrency]
+	if !ok {
+		return nil, s.unavailable(sourceCurrency, destinationCurrency)
+	}
+
+	rate, spread, rateTimestamp := decimal.Zero, s.spread, time.Time{}
+	if transaction.FXQuoteID != nil {
+		quote, err := s.fxRepo.GetQuoteForUpdate(ctx, tx, *transaction.FXQuoteID)
+		if err != nil {
+			if err.Error() == "fx quote not found" {
+				return nil, errs.WrapHTTPError(errs.ErrFXQuoteNotFound, "fx quote with ID %d not found", *transaction.FXQuoteID)
+			}
+			return nil, fmt.Errorf("failed to get fx quote: %w", err)
+		}
+
+		if quote.TransactionID != nil {
+			return nil, errs.ErrFXQuoteUsed
+		}
+
+		if !quote.ExpiresAt.After(time.Now()) {
+			return nil, errs.ErrFXQuoteExpired
+		}
+
+		if quote.SourceCurrency != sourceCurrency || quote.DestinationCurrency != destinationCurrency || !quote.Amount.Equal(transaction.Amount) {
+			return nil, errs.WrapHTTPError(errs.ErrFXQuoteMismatch, "quote converts %s %s into %s",
+				quote.Amount.String(), quote.SourceCurrency, quote.DestinationCurrency)
+		}
+
+		rate, spread, rateTimestamp = quote.Rate, quote.Spread, quote.RateTimestamp
+	} else {
+		current, err := s.rate(ctx, sourceCurrency, destinationCurrency)
+		if err != nil {
+			return nil, err
+		}
+		rate, rateTimestamp = current.Value, current.Timestamp
+	}
+
+	conversion, err := convert(transaction.Amount, rate, spread, destinationCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.FXConversion{
+		DestinationAmount:   conversion.Amount,
+		DestinationCurrency: destinationCurrency,
+		Rate:                rate,
+		Spread:              spread,
+		RateTimestamp:       rateTimestamp,
+		Remainder:           conversion.Remainder,
+		PnLAccountID:        pnlAccountID,
+	}, nil
+}
+
+// useQuote marks the quote of a transaction, if it has one, as used by it
+func (s *FXService) useQuote(ctx context.Context, tx pgx.Tx, transaction *model.Transaction) error {
+	if transaction.FXQuoteID == nil {
+		return nil
+	}
+
+	if err := s.fxRepo.MarkQuoteUsed(ctx, tx, *transaction.FXQuoteID, transaction.ID); err != nil {
+		s.logger.Error().Err(err).Int64("quote_id", *transaction.FXQuoteID).Msg("failed to mark fx quote used")
+		return fmt.Errorf("failed to mark fx quote used: %w", err)
+	}
+
+	return nil
+}
+
+// rate asks the provider for the current rate of the pair
+func (s *FXService) rate(ctx context.Context, base, quote string) (*fx.Rate, error) {
+	rate, err := s.rates.Rate(ctx, base, quote)
+	if err != nil {
+		if errors.Is(err, fx.ErrRateNotFound) {
+			return nil, s.unavailable(base, quote)
+		}
+		s.logger.Error().Err(err).Str("pair", base+"/"+quote).Msg("failed to get fx rate")
+		return nil, fmt.Errorf("failed to get fx rate: %w", err)
+	}
+
+	return rate, nil
+}
+
+func (s *FXService) unavailable(base, quote string) *errs.HTTPError {
+	return errs.WrapHTTPError(errs.ErrFXUnavailable, "conversion from %s to %s is not available", base, quote)
+}
+
+// accrueRemainder adds the remainder of a conversion to what its P&L account is owed and returns
+// the whole minor units now due to it. A single remainder is less than one minor unit, and
+// posting it as is would leave the P&L account with a balance that cannot be paid out in full.
+func (s *FXService) accrueRemainder(ctx context.Context, tx pgx.Tx, conversion *model.FXConversion) (decimal.Decimal, error) {
+	c, ok := currency.Lookup(conversion.DestinationCurrency)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("unsupported currency %s", conversion.DestinationCurrency)
+	}
+
+	due, err := s.fxRepo.AccrueRemainder(ctx, tx, conversion.PnLAccountID, conversion.Remainder, c.Precision)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", conversion.PnLAccountID).Msg("failed to accrue fx remainder")
+		return decimal.Zero, err
+	}
+
+	return due, nil
+}
+
+// convert converts amount into the destination currency, rounded down to its precision
+func convert(amount, rate, spread decimal.Decimal, destinationCurrency string) (*fx.Conversion, error) {
+	c, ok := currency.Lookup(destinationCurrency)
+	if !ok {
+		return nil, errs.WrapHTTPError(errs.ErrUnsupportedCurrency, "currency %s is not supported", destinationCurrency)
+	}
+
+	conversion := fx.Convert(amount, rate, spread, c.Precision, currency.StorageScale)
+	if !conversion.Amount.IsPositive() {
+		return nil, errs.ErrAmountMustBePositive.WithMessage("Amount converts to less than the smallest " + c.Code + " unit")
+	}
+
+	return &conversion, nil
+}
+
+// toFXQuoteResponse converts a quote into its API representation
+func toFXQuoteResponse(quote *model.FXQuote) *model.FXQuoteResponse {
+	conversion := model.FXConversion{Rate: quote.Rate, Spread: quote.Spread}
+
+	return &model.FXQuoteResponse{
+		ID:                  quote.ID,
+		SourceCurrency:      quote.SourceCurrency,
+		DestinationCurrency: quote.DestinationCurrency,
+		Amount:              quote.Amount.String(),
+		ConvertedAmount:     quote.ConvertedAmount.String(),
+		Rate:                quote.Rate.String(),
+		Spread:              quote.Spread.String(),
+		EffectiveRate:       conversion.EffectiveRate().String(),
+		RateTimestamp:       quote.RateTimestamp,
+		ExpiresAt:           quote.ExpiresAt,
+		TransactionID:       quote.TransactionID,
+	}
+}
+
+// toFXConversionResponse converts the conversion of a transaction into its API representation
+func toFXConversionResponse(quoteID *int64, conversion *model.FXConversion) *model.FXConversionResponse {
+	return &model.FXConversionResponse{
+		QuoteID:             quoteID,
+		DestinationAmount:   conversion.DestinationAmount.String(),
+		DestinationCurrency: conversion.DestinationCurrency,
+		Rate:                conversion.Rate.String(),
+		Spread:              conversion.Spread.String(),
+		EffectiveRate:       conversion.EffectiveRate().String(),
+		RateTimestamp:       conversion.RateTimestamp,
+		Remainder:           conversion.Remainder.String(),
+	}
+}
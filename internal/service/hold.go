@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type HoldService struct {
+	db           database.DB
+	accountRepo  repository.AccountRepository
+	holdRepo     repository.HoldRepository
+	transactions *TransactionService
+	idempotency  *IdempotencyService
+	defaultTTL   time.Duration
+	logger       *zerolog.Logger
+}
+
+func NewHoldService(db database.DB, accountRepo repository.AccountRepository, holdRepo repository.HoldRepository, transactions *TransactionService, idempotency *IdempotencyService, defaultTTL time.Duration, logger *zerolog.Logger) *HoldService {
+	return &HoldService{
+		db:           db,
+		accountRepo:  accountRepo,
+		holdRepo:     holdRepo,
+		transactions: transactions,
+		idempotency:  idempotency,
+		defaultTTL:   defaultTTL,
+		logger:       logger,
+	}
+}
+
+// PlaceHold reserves funds on an account. The reserved amount leaves the available balance
+// but stays in the ledger balance until the hold is captured.
+func (s *HoldService) PlaceHold(ctx context.Context, req *model.CreateHoldRequest) (*model.HoldResponse, error) {
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount format: %w", err)
+	}
+
+	response, err := inIdempotentTx(ctx, s.db, s.idempotency, s.logger, idempotencyScopeCreateHold, req.IdempotencyKey, req.IdempotencyPayload(),
+		func(tx pgx.Tx) (*model.HoldResponse, error) {
+			return s.placeHold(ctx, tx, req, amount)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().
+		Int64("hold_id", response.ID).
+		Int64("account_id", response.AccountID).
+		Str("amount", response.Amount).
+		Msg("hold placed successfully")
+
+	return response, nil
+}
+
+func (s *HoldService) placeHold(ctx context.Context, tx pgx.Tx, req *model.CreateHoldRequest, amount decimal.Decimal) (*model.HoldResponse, error) {
+	if !amount.IsPositive() {
+		return nil, errs.ErrAmountMustBePositive
+	}
+
+	expiresAt := time.Now().Add(s.defaultTTL)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return nil, errs.ErrValidationError.WithMessage("expires_at must be in the future")
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	account, err := s.accountRepo.GetByIDForUpdate(ctx, tx, req.AccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", req.AccountID)
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	if rejection := checkDebitStatus(account); rejection != nil {
+		return nil, rejection
+	}
+
+	if rejection := checkPrecision(account.Currency, amount); rejection != nil {
+		return nil, rejection
+	}
+
+	if rejection := checkDebit(account, amount); rejection != nil {
+		return nil, rejection
+	}
+
+	hold := &model.Hold{
+		AccountID: req.AccountID,
+		Amount:    amount,
+		ExpiresAt: expiresAt,
+	}
+	if req.Reference != "" {
+		hold.Reference = &req.Reference
+	}
+
+	if err := s.holdRepo.Create(ctx, tx, hold); err != nil {
+		s.logger.Error().Err(err).Int64("account_id", req.AccountID).Msg("failed to create hold")
+		return nil, fmt.Errorf("failed to create hold: %w", err)
+	}
+
+	if err := s.accountRepo.AdjustHeld(ctx, tx, req.AccountID, amount); err != nil {
+		s.logger.Error().Err(err).Int64("account_id", req.AccountID).Msg("failed to reserve held amount")
+		return nil, fmt.Errorf("failed to reserve held amount: %w", err)
+	}
+
+	return toHoldResponse(hold), nil
+}
+
+// CaptureHold turns all or part of an active hold into a transfer to the destination and
+// releases whatever is not captured
+func (s *HoldService) CaptureHold(ctx context.Context, req *model.CaptureHoldRequest) (*model.HoldResponse, error) {
+	var amount *decimal.Decimal
+	if req.Amount != "" {
+		parsed, err := decimal.NewFromString(req.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount format: %w", err)
+		}
+		amount = &parsed
+	}
+
+	response, err := inIdempotentTx(ctx, s.db, s.idempotency, s.logger, idempotencyScopeCaptureHold, req.IdempotencyKey, req.IdempotencyPayload(),
+		func(tx pgx.Tx) (*model.HoldResponse, error) {
+			return s.capture(ctx, tx, req, amount)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().
+		Int64("hold_id", response.ID).
+		Int64("transaction_id", *response.CaptureTransactionID).
+		Str("captured_amount", response.CapturedAmount).
+		Msg("hold captured successfully")
+
+	return response, nil
+}
+
+func (s *HoldService) capture(ctx context.Context, tx pgx.Tx, req *model.CaptureHoldRequest, amount *decimal.Decimal) (*model.HoldResponse, error) {
+	hold, err := s.lockActiveHold(ctx, tx, req.HoldID)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount == nil {
+		amount = &hold.Amount
+	}
+
+	if !amount.IsPositive() {
+		return nil, errs.ErrAmountMustBePositive
+	}
+
+	if amount.GreaterThan(hold.Amount) {
+		return nil, errs.WrapHTTPError(errs.ErrCaptureExceedsHold, "capture amount exceeds the held %s", hold.Amount.String())
+	}
+
+	if req.DestinationAccountID == hold.AccountID {
+		return nil, errs.ErrSameAccount
+	}
+
+	// Lock both accounts in the shared order before the held amount is touched
+	locked, err := s.transactions.lockAccounts(ctx, tx, hold.AccountID, req.DestinationAccountID)
+	if err != nil {
+		return nil, err
+	}
+	destinationAccount, ok := locked[req.DestinationAccountID]
+	if !ok {
+		return nil, errs.ErrDestinationAccountNotFound
+	}
+
+	if rejection := checkStatus(locked[hold.AccountID], destinationAccount); rejection != nil {
+		return nil, rejection
+	}
+
+	if rejection := checkTransferable(locked[hold.AccountID], destinationAccount, *amount); rejection != nil {
+		return nil, rejection
+	}
+
+	if rejection := checkCredit(destinationAccount, *amount); rejection != nil {
+		return nil, rejection
+	}
+
+	// Settling checks the velocity limits again, with the same result under the same locks
+	limits, err := s.transactions.velocity.lockLimits(ctx, tx, locked[hold.AccountID])
+	if err != nil {
+		return nil, err
+	}
+	if err := s.transactions.velocity.check(ctx, tx, limits, locked[hold.AccountID], *amount, nil); err != nil {
+		return nil, err
+	}
+
+	// Release the whole reservation, then move the captured part like any other transfer
+	if err := s.accountRepo.AdjustHeld(ctx, tx, hold.AccountID, hold.Amount.Neg()); err != nil {
+		s.logger.Error().Err(err).Int64("hold_id", hold.ID).Msg("failed to release held amount")
+		return nil, fmt.Errorf("failed to release held amount: %w", err)
+	}
+
+	transaction := &model.Transaction{
+		SourceAccountID:      hold.AccountID,
+		DestinationAccountID: req.DestinationAccountID,
+		Amount:               *amount,
+		Status:               model.TransactionStatusPending,
+		Kind:                 model.TransactionKindTransfer,
+		// The hold reserved the amount alone, so a fee could leave the capture uncovered
+		WaiveFees: true,
+	}
+	if err := s.transactions.execute(ctx, tx, transaction); err != nil {
+		// The held funds cover the capture, so a rejection here means the hold is out of step
+		// with the balance. Roll back instead of committing a released but uncaptured hold.
+		return nil, fmt.Errorf("failed to settle capture of hold %d: %v", hold.ID, err)
+	}
+
+	hold.Status = model.HoldStatusCaptured
+	hold.CapturedAmount = *amount
+	hold.CaptureTransactionID = &transaction.ID
+	if err := s.holdRepo.Release(ctx, tx, hold); err != nil {
+		s.logger.Error().Err(err).Int64("hold_id", hold.ID).Msg("failed to close hold")
+		return nil, fmt.Errorf("failed to close hold: %w", err)
+	}
+
+	return toHoldResponse(hold), nil
+}
+
+// VoidHold cancels an active hold and makes its funds available again
+func (s *HoldService) VoidHold(ctx context.Context, holdID int64) (*model.HoldResponse, error) {
+	// Without a key the helper only provides the commit-on-rejection semantics
+	response, err := inIdempotentTx(ctx, s.db, s.idempotency, s.logger, "", "", nil,
+		func(tx pgx.Tx) (*model.HoldResponse, error) {
+			hold, err := s.lockActiveHold(ctx, tx, holdID)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := s.release(ctx, tx, hold, model.HoldStatusVoided); err != nil {
+				return nil, err
+			}
+
+			return toHoldResponse(hold), nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().Int64("hold_id", holdID).Msg("hold voided successfully")
+
+	return response, nil
+}
+
+// GetHold retrieves a hold by its ID
+func (s *HoldService) GetHold(ctx context.Context, holdID int64) (*model.HoldResponse, error) {
+	hold, err := s.holdRepo.GetByID(ctx, holdID)
+	if err != nil {
+		if err.Error() == "hold not found" {
+			return nil, errs.WrapHTTPError(errs.ErrHoldNotFound, "hold with ID %d not found", holdID)
+		}
+		s.logger.Error().Err(err).Int64("hold_id", holdID).Msg("failed to get hold")
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+
+	return toHoldResponse(hold), nil
+}
+
+// ExpireDue releases up to limit holds past their expiry and returns how many it released
+func (s *HoldService) ExpireDue(ctx context.Context, limit int) (int, error) {
+	processed := 0
+	for processed < limit {
+		expired, err := s.expireNext(ctx)
+		if err != nil {
+			return processed, err
+		}
+		if !expired {
+			break
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// expireNext claims one lapsed hold and releases it. It reports false when none is left.
+func (s *HoldService) expireNext(ctx context.Context) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to begin transaction")
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	hold, err := s.holdRepo.ClaimExpired(ctx, tx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to claim expired hold")
+		return false, fmt.Errorf("failed to claim expired hold: %w", err)
+	}
+	if hold == nil {
+		return false, nil
+	}
+
+	if err := s.release(ctx, tx, hold, model.HoldStatusExpired); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("failed to commit transaction")
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	s.logger.Info().
+		Int64("hold_id", hold.ID).
+		Int64("account_id", hold.AccountID).
+		Str("amount", hold.Amount.String()).
+		Msg("hold expired")
+
+	return true, nil
+}
+
+// lockActiveHold locks a hold that can still be captured or voided. A hold found past its
+// expiry is expired on the spot, which the caller commits along with the rejection.
+func (s *HoldService) lockActiveHold(ctx context.Context, tx pgx.Tx, holdID int64) (*model.Hold, error) {
+	hold, err := s.holdRepo.GetByIDForUpdate(ctx, tx, holdID)
+	if err != nil {
+		if err.Error() == "hold not found" {
+			return nil, errs.WrapHTTPError(errs.ErrHoldNotFound, "hold with ID %d not found", holdID)
+		}
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+
+	if hold.Status != model.HoldStatusActive {
+		return nil, errs.WrapHTTPError(errs.ErrHoldNotActive, "hold with status %s cannot be changed", hold.Status)
+	}
+
+	if !hold.ExpiresAt.After(time.Now()) {
+		if err := s.release(ctx, tx, hold, model.HoldStatusExpired); err != nil {
+			return nil, err
+		}
+		return nil, errs.ErrHoldExpired
+	}
+
+	return hold, nil
+}
+
+// release closes an uncaptured hold with the given status and frees its amount
+func (s *HoldService) release(ctx context.Context, tx pgx.Tx, hold *model.Hold, status model.HoldStatus) error {
+	if err := s.accountRepo.AdjustHeld(ctx, tx, hold.AccountID, hold.Amount.Neg()); err != nil {
+		s.logger.Error().Err(err).Int64("hold_id", hold.ID).Msg("failed to release held amount")
+		return fmt.Errorf("failed to release held amount: %w", err)
+	}
+
+	hold.Status = status
+	if err := s.holdRepo.Release(ctx, tx, hold); err != nil {
+		s.logger.Error().Err(err).Int64("hold_id", hold.ID).Msg("failed to close hold")
+		return fmt.Errorf("failed to close hold: %w", err)
+	}
+
+	return nil
+}
+
+// toHoldResponse converts a hold into its API representation
+func toHoldResponse(hold *model.Hold) *model.HoldResponse {
+	return &model.HoldResponse{
+		ID:                   hold.ID,
+		AccountID:            hold.AccountID,
+		Amount:               hold.Amount.String(),
+		Currency:             hold.Currency,
+		CapturedAmount:       hold.CapturedAmount.String(),
+		Status:               hold.Status,
+		Reference:            hold.Reference,
+		CaptureTransactionID: hold.CaptureTransactionID,
+		ExpiresAt:            hold.ExpiresAt,
+		ReleasedAt:           hold.ReleasedAt,
+		CreatedAt:            hold.CreatedAt,
+	}
+}
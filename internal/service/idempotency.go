@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+// Idempotency scopes keep keys of different operations apart
+const (
+	idempotencyScopeCreateAccount       = "accounts.create"
+	idempotencyScopeCloseAccount        = "accounts.close"
+	idempotencyScopeCreateTransaction   = "transactions.create"
+	idempotencyScopeCreateReversal      = "transactions.reverse"
+	idempotencyScopeCreateBatch         = "transactions.batch"
+	idempotencyScopeCreateStandingOrder = "standing_orders.create"
+	idempotencyScopeCreateHold          = "holds.create"
+	idempotencyScopeCaptureHold         = "holds.capture"
+)
+
+type IdempotencyService struct {
+	idempotencyRepo repository.IdempotencyRepository
+	retention       time.Duration
+	logger          *zerolog.Logger
+}
+
+func NewIdempotencyService(idempotencyRepo repository.IdempotencyRepository, retention time.Duration, logger *zerolog.Logger) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		retention:       retention,
+		logger:          logger,
+	}
+}
+
+// claim reserves key for the request inside tx. When the key was already used with the
+// same payload it returns the stored record with replay set, and the caller must replay
+// it instead of performing the operation again.
+func (s *IdempotencyService) claim(ctx context.Context, tx pgx.Tx, scope, key string, payload interface{}) (*model.IdempotencyRecord, bool, error) {
+	hash, err := hashPayload(payload)
+	if err != nil {
+		return nil, false, err
+	}
+
+	record := &model.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		RequestHash: hash,
+		ExpiresAt:   time.Now().Add(s.retention),
+	}
+
+	claimed, err := s.idempotencyRepo.Claim(ctx, tx, record)
+	if err != nil {
+		return nil, false, err
+	}
+	if claimed {
+		return record, false, nil
+	}
+
+	existing, err := s.idempotencyRepo.Get(ctx, tx, scope, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if existing.RequestHash != hash {
+		return nil, false, errs.ErrIdempotencyKeyReused
+	}
+
+	if existing.StatusCode == nil {
+		return nil, false, errs.ErrIdempotencyKeyInProgress
+	}
+
+	s.logger.Info().
+		Str("scope", scope).
+		Str("idempotency_key", key).
+		Int("status_code", *existing.StatusCode).
+		Msg("replaying idempotent request")
+
+	return existing, true, nil
+}
+
+// saveOutcome stores the response for a claimed key in the same transaction as the operation
+func (s *IdempotencyService) saveOutcome(ctx context.Context, tx pgx.Tx, record *model.IdempotencyRecord, statusCode int, body interface{}, errorCode *string) error {
+	responseBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotent response: %w", err)
+	}
+
+	record.StatusCode = &statusCode
+	record.ResponseBody = responseBody
+	record.ErrorCode = errorCode
+
+	return s.idempotencyRepo.SaveOutcome(ctx, tx, record)
+}
+
+// PurgeExpired removes keys whose retention period has passed
+func (s *IdempotencyService) PurgeExpired(ctx context.Context) error {
+	deleted, err := s.idempotencyRepo.DeleteExpired(ctx)
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		s.logger.Info().Int64("deleted", deleted).Msg("purged expired idempotency keys")
+	}
+
+	return nil
+}
+
+// inIdempotentTx runs op in a new database transaction. With a key, the outcome is stored in
+// the same transaction and replayed to retries instead of running op again. Business
+// rejections (HTTP errors) are committed so failed records and their outcome persist;
+// any other error rolls everything back and leaves the key free for a retry.
+func inIdempotentTx[T any](ctx context.Context, db database.DB, idempotency *IdempotencyService, logger *zerolog.Logger, scope, key string, payload interface{}, op func(tx pgx.Tx) (*T, error)) (*T, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	// Claim the idempotency key, or replay the outcome of the request that used it first
+	var record *model.IdempotencyRecord
+	if key != "" {
+		claimed, replay, claimErr := idempotency.claim(ctx, tx, scope, key, payload)
+		if claimErr != nil {
+			return nil, claimErr
+		}
+		if replay {
+			var result T
+			if replayErr := replayOutcome(claimed, &result); replayErr != nil {
+				return nil, replayErr
+			}
+			return &result, nil
+		}
+		record = claimed
+	}
+
+	result, err := op(tx)
+	if err != nil {
+		httpErr, ok := errs.IsHTTPError(err)
+		if !ok {
+			return nil, err
+		}
+
+		if record != nil {
+			if saveErr := idempotency.saveOutcome(ctx, tx, record, httpErr.Status, httpErr, &httpErr.Code); saveErr != nil {
+				logger.Error().Err(saveErr).Msg("failed to save idempotent outcome")
+				return nil, fmt.Errorf("failed to save idempotent outcome: %w", saveErr)
+			}
+		}
+
+		if commitErr := tx.Commit(ctx); commitErr != nil {
+			logger.Error().Err(commitErr).Msg("failed to commit transaction")
+			return nil, fmt.Errorf("failed to commit transaction: %w", commitErr)
+		}
+		committed = true
+
+		return nil, err
+	}
+
+	if record != nil {
+		if err := idempotency.saveOutcome(ctx, tx, record, http.StatusCreated, result, nil); err != nil {
+			logger.Error().Err(err).Msg("failed to save idempotent outcome")
+			return nil, fmt.Errorf("failed to save idempotent outcome: %w", err)
+		}
+	}
+
+	// Commit the transaction
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error().Err(err).Msg("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	return result, nil
+}
+
+// replayOutcome decodes a stored success response into result, or returns the stored error
+func replayOutcome(record *model.IdempotencyRecord, result interface{}) error {
+	if record.ErrorCode != nil {
+		var httpErr errs.HTTPError
+		if err := json.Unmarshal(record.ResponseBody, &httpErr); err != nil {
+			return fmt.Errorf("failed to decode idempotent error: %w", err)
+		}
+		return &httpErr
+	}
+
+	if err := json.Unmarshal(record.ResponseBody, result); err != nil {
+		return fmt.Errorf("failed to decode idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+// hashPayload fingerprints a request so a reused key with a different payload can be detected
+func hashPayload(payload interface{}) (string, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request payload: %w", err)
+	}
+
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,482 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/currency"
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/interest"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type InterestService struct {
+	db           database.DB
+	accountRepo  repository.AccountRepository
+	interestRepo repository.InterestRepository
+	transactions *TransactionService
+	logger       *zerolog.Logger
+}
+
+func NewInterestService(db database.DB, accountRepo repository.AccountRepository, interestRepo repository.InterestRepository, transactions *TransactionService, logger *zerolog.Logger) *InterestService {
+	return &InterestService{
+		db:           db,
+		accountRepo:  accountRepo,
+		interestRepo: interestRepo,
+		transactions: transactions,
+		logger:       logger,
+	}
+}
+
+// CreateProduct adds an interest product paid out of an expense account in its currency
+func (s *InterestService) CreateProduct(ctx context.Context, req *model.CreateInterestProductRequest) (*model.InterestProductResponse, error) {
+	if _, ok := currency.Lookup(req.Currency); !ok {
+		return nil, errs.WrapHTTPError(errs.ErrUnsupportedCurrency, "currency %s is not supported", req.Currency)
+	}
+
+	rate, err := decimal.NewFromString(req.AnnualRate)
+	if err != nil {
+		return nil, errs.ErrInvalidFormat.WithMessage("Invalid annual_rate format")
+	}
+	if rate.IsNegative() || rate.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return nil, errs.ErrValidationError.WithMessage("annual_rate must be a fraction between 0 and 1")
+	}
+
+	dayCount, err := interest.ParseDayCount(req.DayCount)
+	if err != nil {
+		return nil, errs.ErrValidationError.WithMessage(err.Error())
+	}
+
+	expenseAccount, err := s.accountRepo.GetByID(ctx, req.ExpenseAccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", req.ExpenseAccountID)
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if expenseAccount.Currency != req.Currency {
+		return nil, errs.WrapHTTPError(errs.ErrCurrencyMismatch, "expense account %d holds %s, the product pays %s",
+			expenseAccount.ID, expenseAccount.Currency, req.Currency)
+	}
+
+	product := &model.InterestProduct{
+		Name:             req.Name,
+		Currency:         req.Currency,
+		AnnualRate:       rate,
+		DayCount:         dayCount,
+		ExpenseAccountID: req.ExpenseAccountID,
+	}
+	if err := s.interestRepo.CreateProduct(ctx, product); err != nil {
+		s.logger.Error().Err(err).Msg("failed to create interest product")
+		return nil, fmt.Errorf("failed to create interest product: %w", err)
+	}
+
+	s.logger.Info().
+		Int64("interest_product_id", product.ID).
+		Str("annual_rate", product.AnnualRate.String()).
+		Str("day_count", string(product.DayCount)).
+		Msg("interest product created")
+
+	return toInterestProductResponse(product), nil
+}
+
+// ListProducts returns every interest product
+func (s *InterestService) ListProducts(ctx context.Context) (*model.ListResponse[*model.InterestProductResponse], error) {
+	products, err := s.interestRepo.ListProducts(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list interest products")
+		return nil, fmt.Errorf("failed to list interest products: %w", err)
+	}
+
+	responses := make([]*model.InterestProductResponse, 0, len(products))
+	for _, product := range products {
+		responses = append(responses, toInterestProductResponse(product))
+	}
+
+	return &model.ListResponse[*model.InterestProductResponse]{Data: responses}, nil
+}
+
+// UpdateAccountProduct assigns an account to an interest product of its currency, or stops it
+// from earning interest. Accruals already made are still capitalized.
+func (s *InterestService) UpdateAccountProduct(ctx context.Context, req *model.UpdateAccountInterestRequest) (*model.AccountResponse, error) {
+	account, err := s.accountRepo.GetByID(ctx, req.AccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", req.AccountID)
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	if rejection := checkVersion(account, req.ExpectedVersion); rejection != nil {
+		return nil, rejection
+	}
+
+	if req.ProductID != nil {
+		if account.Status == model.AccountStatusClosed {
+			return nil, errs.WrapHTTPError(errs.ErrAccountClosed, "account %d is closed", account.ID)
+		}
+
+		product, err := s.interestRepo.GetProduct(ctx, *req.ProductID)
+		if err != nil {
+			if err.Error() == "interest product not found" {
+				return nil, errs.WrapHTTPError(errs.ErrInterestProductNotFound, "interest product with ID %d not found", *req.ProductID)
+			}
+			return nil, fmt.Errorf("failed to get interest product: %w", err)
+		}
+
+		if product.Currency != account.Currency {
+			return nil, errs.WrapHTTPError(errs.ErrCurrencyMismatch, "account %d holds %s, interest product %d pays %s",
+				account.ID, account.Currency, product.ID, product.Currency)
+		}
+	}
+
+	updated, err := s.accountRepo.UpdateInterestProduct(ctx, req.AccountID, req.ProductID, req.ExpectedVersion)
+	if err != nil {
+		if err.Error() == "account not found or version changed" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountVersionMismatch, "account %d changed while it was updated", req.AccountID)
+		}
+		s.logger.Error().Err(err).Int64("account_id", req.AccountID).Msg("failed to update account interest product")
+		return nil, fmt.Errorf("failed to update account interest product: %w", err)
+	}
+
+	return toAccountResponse(updated), nil
+}
+
+// ListAccruals returns the accruals of an account and the part of them not yet capitalized
+func (s *InterestService) ListAccruals(ctx context.Context, req *model.ListInterestAccrualsRequest) (*model.InterestAccrualListResponse, error) {
+	from, err := parseBusinessDate("from", req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseBusinessDate("to", req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, req.AccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", req.AccountID)
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	accruals, err := s.interestRepo.ListAccruals(ctx, account.ID, from, to)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", account.ID).Msg("failed to list interest accruals")
+		return nil, fmt.Errorf("failed to list interest accruals: %w", err)
+	}
+
+	response := &model.InterestAccrualListResponse{
+		Data: make([]*model.InterestAccrualResponse, 0, len(accruals)),
+	}
+	unposted := decimal.Zero
+	for _, accrual := range accruals {
+		if accrual.TransactionID == nil {
+			unposted = unposted.Add(accrual.Amount)
+		}
+		response.Data = append(response.Data, &model.InterestAccrualResponse{
+			BusinessDate:  accrual.BusinessDate.Format(time.DateOnly),
+			ProductID:     accrual.ProductID,
+			Balance:       accrual.Balance.String(),
+			AnnualRate:    accrual.AnnualRate.String(),
+			DayCount:      accrual.DayCount,
+			Amount:        accrual.Amount.String(),
+			TransactionID: accrual.TransactionID,
+		})
+	}
+	carry, err := s.interestRepo.GetCarry(ctx, account.ID)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", account.ID).Msg("failed to get interest carry")
+		return nil, fmt.Errorf("failed to get interest carry: %w", err)
+	}
+	response.Unposted = unposted.Add(carry).String()
+
+	return response, nil
+}
+
+// RunAccrual accrues interest for a business date that has ended
+func (s *InterestService) RunAccrual(ctx context.Context, req *model.RunInterestAccrualRequest) (*model.InterestAccrualRunResponse, error) {
+	businessDate, err := parseBusinessDate("business_date", req.BusinessDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if !businessDate.Before(interest.Date(time.Now())) {
+		return nil, errs.ErrValidationError.WithMessage("business_date must be a day that has ended")
+	}
+
+	return s.Accrue(ctx, *businessDate)
+}
+
+// Accrue records a day of interest for every account with an interest product, on its balance
+// at the end of the business date. Accounts accrued for the date before are skipped, so the
+// run can be repeated safely. Only positive balances earn interest.
+func (s *InterestService) Accrue(ctx context.Context, businessDate time.Time) (*model.InterestAccrualRunResponse, error) {
+	businessDate = interest.Date(businessDate)
+	endOfDay := businessDate.AddDate(0, 0, 1)
+
+	candidates, err := s.interestRepo.ListCandidates(ctx, endOfDay)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list interest candidates")
+		return nil, fmt.Errorf("failed to list interest candidates: %w", err)
+	}
+
+	response := &model.InterestAccrualRunResponse{BusinessDate: businessDate.Format(time.DateOnly)}
+	for _, candidate := range candidates {
+		amount := decimal.Zero
+		if candidate.Balance.IsPositive() {
+			amount = interest.Daily(candidate.Balance, candidate.Product.AnnualRate, candidate.Product.DayCount, businessDate)
+		}
+
+		accrual := &model.InterestAccrual{
+			AccountID:    candidate.AccountID,
+			ProductID:    candidate.Product.ID,
+			BusinessDate: businessDate,
+			Balance:      candidate.Balance,
+			AnnualRate:   candidate.Product.AnnualRate,
+			DayCount:     candidate.Product.DayCount,
+			Amount:       amount,
+		}
+		created, err := s.interestRepo.CreateAccrual(ctx, accrual)
+		if err != nil {
+			s.logger.Error().Err(err).Int64("account_id", candidate.AccountID).Msg("failed to create interest accrual")
+			return response, fmt.Errorf("failed to create interest accrual: %w", err)
+		}
+
+		if created {
+			response.Accrued++
+		} else {
+			response.Skipped++
+		}
+	}
+
+	s.logger.Info().
+		Str("business_date", response.BusinessDate).
+		Int("accrued", response.Accrued).
+		Int("skipped", response.Skipped).
+		Msg("interest accrued")
+
+	return response, nil
+}
+
+// RunCapitalization capitalizes the accruals of business dates before the requested date
+func (s *InterestService) RunCapitalization(ctx context.Context, req *model.RunInterestCapitalizationRequest) (*model.InterestCapitalizationRunResponse, error) {
+	before := interest.MonthStart(time.Now())
+	if req.Before != "" {
+		parsed, err := parseBusinessDate("before", req.Before)
+		if err != nil {
+			return nil, err
+		}
+		before = *parsed
+	}
+
+	if before.After(interest.Date(time.Now())) {
+		return nil, errs.ErrValidationError.WithMessage("before cannot be in the future")
+	}
+
+	return s.Capitalize(ctx, before)
+}
+
+// Capitalize pays the unposted accruals of business dates before the given date into their
+// accounts, one interest transaction per account. Run on the first of a month with that date
+// it capitalizes the month before; already posted accruals are never paid again.
+func (s *InterestService) Capitalize(ctx context.Context, before time.Time) (*model.InterestCapitalizationRunResponse, error) {
+	before = interest.Date(before)
+
+	accountIDs, err := s.interestRepo.ListCapitalizable(ctx, before)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list capitalizable accounts")
+		return nil, fmt.Errorf("failed to list capitalizable accounts: %w", err)
+	}
+
+	response := &model.InterestCapitalizationRunResponse{
+		Before:       before.Format(time.DateOnly),
+		Transactions: make([]int64, 0, len(accountIDs)),
+	}
+	for _, accountID := range accountIDs {
+		transactionID, err := s.capitalizeAccount(ctx, accountID, before)
+		if err != nil {
+			// A rejected payment leaves the accruals for the next run
+			if rejection, ok := errs.IsHTTPError(err); ok {
+				s.logger.Warn().
+					Int64("account_id", accountID).
+					Str("code", rejection.Code).
+					Str("reason", rejection.Message).
+					Msg("interest capitalization rejected")
+				response.Failed++
+				continue
+			}
+			return response, err
+		}
+
+		if transactionID != nil {
+			response.Transactions = append(response.Transactions, *transactionID)
+		}
+	}
+
+	s.logger.Info().
+		Str("before", response.Before).
+		Int("posted", len(response.Transactions)).
+		Int("failed", response.Failed).
+		Msg("interest capitalized")
+
+	return response, nil
+}
+
+// capitalizeAccount pays the unposted accruals of one account, with what earlier runs carried,
+// in its own database transaction. It returns nil when they do not add up to a whole minor
+// unit, in which case the transaction is rolled back and they carry over to the next run.
+func (s *InterestService) capitalizeAccount(ctx context.Context, accountID int64, before time.Time) (*int64, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	// Accruals are locked before the accounts, like holds
+	accruals, err := s.interestRepo.LockUnposted(ctx, tx, accountID, before)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to lock unposted accruals")
+		return nil, fmt.Errorf("failed to lock unposted accruals: %w", err)
+	}
+	if len(accruals) == 0 {
+		return nil, nil
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	// Every accrual of the run is paid from the expense account of the latest product
+	product, err := s.interestRepo.GetProduct(ctx, accruals[len(accruals)-1].ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get interest product: %w", err)
+	}
+
+	total := decimal.Zero
+	ids := make([]int64, 0, len(accruals))
+	for _, accrual := range accruals {
+		total = total.Add(accrual.Amount)
+		ids = append(ids, accrual.ID)
+	}
+
+	precision := int32(currency.StorageScale)
+	if c, ok := currency.Lookup(account.Currency); ok {
+		precision = c.Precision
+	}
+	// Only whole minor units are paid; the rest is carried and added to the next run
+	amount, err := s.interestRepo.AccrueCarry(ctx, tx, accountID, total, precision)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to accrue interest carry")
+		return nil, fmt.Errorf("failed to accrue interest carry: %w", err)
+	}
+	if !amount.IsPositive() {
+		return nil, nil
+	}
+
+	reason := fmt.Sprintf("Interest for business dates %s to %s",
+		accruals[0].BusinessDate.Format(time.DateOnly), accruals[len(accruals)-1].BusinessDate.Format(time.DateOnly))
+	transaction := &model.Transaction{
+		SourceAccountID:      product.ExpenseAccountID,
+		DestinationAccountID: accountID,
+		Amount:               amount,
+		Status:               model.TransactionStatusPending,
+		Kind:                 model.TransactionKindInterest,
+		Reason:               &reason,
+	}
+	if err := s.transactions.execute(ctx, tx, transaction); err != nil {
+		return nil, err
+	}
+
+	if err := s.interestRepo.MarkPosted(ctx, tx, ids, transaction.ID); err != nil {
+		s.logger.Error().Err(err).Int64("account_id", accountID).Msg("failed to mark accruals posted")
+		return nil, fmt.Errorf("failed to mark accruals posted: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	s.logger.Info().
+		Int64("account_id", accountID).
+		Int64("transaction_id", transaction.ID).
+		Str("amount", amount.String()).
+		Int("accruals", len(accruals)).
+		Msg("interest posted")
+
+	return &transaction.ID, nil
+}
+
+// RunDue accrues every business date that has ended since the last accrued one and capitalizes
+// the months before the current one. It is the body of the background job and does nothing new
+// when repeated.
+func (s *InterestService) RunDue(ctx context.Context) error {
+	today := interest.Date(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+
+	// Dates missed while the job was not running are caught up, starting from yesterday
+	// when nothing has accrued yet
+	businessDate := yesterday
+	last, err := s.interestRepo.GetLastAccrualDate(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to get last accrual date")
+		return fmt.Errorf("failed to get last accrual date: %w", err)
+	}
+	if last != nil && interest.Date(*last).Before(yesterday) {
+		businessDate = interest.Date(*last).AddDate(0, 0, 1)
+	}
+
+	for ; !businessDate.After(yesterday); businessDate = businessDate.AddDate(0, 0, 1) {
+		if _, err := s.Accrue(ctx, businessDate); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.Capitalize(ctx, interest.MonthStart(today))
+	return err
+}
+
+// parseBusinessDate parses an optional YYYY-MM-DD date
+func parseBusinessDate(field, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, errs.ErrInvalidFormat.WithMessage(field + " must be a YYYY-MM-DD date")
+	}
+
+	return &parsed, nil
+}
+
+// toInterestProductResponse converts an interest product into its API representation
+func toInterestProductResponse(product *model.InterestProduct) *model.InterestProductResponse {
+	return &model.InterestProductResponse{
+		ID:               product.ID,
+		Name:             product.Name,
+		Currency:         product.Currency,
+		AnnualRate:       product.AnnualRate.String(),
+		DayCount:         product.DayCount,
+		ExpenseAccountID: product.ExpenseAccountID,
+		CreatedAt:        product.CreatedAt,
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/event"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+type OutboxService struct {
+	db         database.DB
+	outboxRepo repository.OutboxRepository
+	feedRepo   repository.EventFeedRepository
+	publisher  event.Publisher
+	// webhooks queues every published event for the webhooks subscribed to it
+	webhooks *WebhookService
+	logger   *zerolog.Logger
+}
+
+func NewOutboxService(db database.DB, outboxRepo repository.OutboxRepository, feedRepo repository.EventFeedRepository, publisher event.Publisher, webhooks *WebhookService, logger *zerolog.Logger) *OutboxService {
+	return &OutboxService{
+		db:         db,
+		outboxRepo: outboxRepo,
+		feedRepo:   feedRepo,
+		publisher:  publisher,
+		webhooks:   webhooks,
+		logger:     logger,
+	}
+}
+
+// record writes an event inside tx, so it is published if and only if the change it
+// describes is committed
+func (s *OutboxService) record(ctx context.Context, tx pgx.Tx, eventType event.Type, aggregateType event.AggregateType, aggregateID int64, data interface{}) error {
+	e, err := event.New(eventType, aggregateType, aggregateID, data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	if err := s.outboxRepo.Insert(ctx, tx, e); err != nil {
+		s.logger.Error().Err(err).Str("event_type", string(eventType)).Int64("aggregate_id", aggregateID).Msg("failed to record event")
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	return nil
+}
+
+// recordTransaction records an event carrying the transaction as the API returns it
+func (s *OutboxService) recordTransaction(ctx context.Context, tx pgx.Tx, eventType event.Type, transaction *model.Transaction) error {
+	return s.record(ctx, tx, eventType, event.AggregateTransaction, transaction.ID, toTransactionResponse(transaction))
+}
+
+// recordAccount records an event carrying the account as the API returns it
+func (s *OutboxService) recordAccount(ctx context.Context, tx pgx.Tx, eventType event.Type, account *model.Account) error {
+	return s.record(ctx, tx, eventType, event.AggregateAccount, account.ID, toAccountResponse(account))
+}
+
+// Relay publishes up to limit unpublished events in the order they were recorded, queues
+// their webhook deliveries, appends them to the event feed and returns how many it
+// published. It stops at the first event the publisher rejects and keeps the ones before it,
+// so the rest are retried in order on the next run.
+func (s *OutboxService) Relay(ctx context.Context, limit int) (int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to begin transaction")
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	events, err := s.outboxRepo.LockUnpublished(ctx, tx, limit)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to lock unpublished events")
+		return 0, fmt.Errorf("failed to lock unpublished events: %w", err)
+	}
+
+	published := make([]int64, 0, len(events))
+	var publishErr error
+	for _, e := range events {
+		if err := s.publisher.Publish(ctx, e); err != nil {
+			s.logger.Error().Err(err).Int64("event_id", e.ID).Str("event_type", string(e.Type)).Msg("failed to publish event")
+			publishErr = fmt.Errorf("failed to publish event %d: %w", e.ID, err)
+			break
+		}
+
+		if err := s.webhooks.enqueue(ctx, tx, e); err != nil {
+			return 0, err
+		}
+		published = append(published, e.ID)
+	}
+
+	if len(published) > 0 {
+		if err := s.outboxRepo.MarkPublished(ctx, tx, published); err != nil {
+			s.logger.Error().Err(err).Msg("failed to mark events published")
+			return 0, fmt.Errorf("failed to mark events published: %w", err)
+		}
+
+		if err := s.feedRepo.Append(ctx, tx, published); err != nil {
+			s.logger.Error().Err(err).Msg("failed to append events to feed")
+			return 0, fmt.Errorf("failed to append events to feed: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("failed to commit transaction")
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	if len(published) > 0 {
+		s.logger.Info().Int("events", len(published)).Msg("outbox events published")
+	}
+
+	return len(published), publishErr
+}
+
+// Close releases the publisher once the relay has stopped
+func (s *OutboxService) Close() error {
+	return s.publisher.Close()
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/rs/zerolog"
+)
+
+type ReconciliationService struct {
+	db                 database.DB
+	accountRepo        repository.AccountRepository
+	ledgerRepo         repository.LedgerRepository
+	reconciliationRepo repository.ReconciliationRepository
+	logger             *zerolog.Logger
+}
+
+func NewReconciliationService(db database.DB, accountRepo repository.AccountRepository, ledgerRepo repository.LedgerRepository, reconciliationRepo repository.ReconciliationRepository, logger *zerolog.Logger) *ReconciliationService {
+	return &ReconciliationService{
+		db:                 db,
+		accountRepo:        accountRepo,
+		ledgerRepo:         ledgerRepo,
+		reconciliationRepo: reconciliationRepo,
+		logger:             logger,
+	}
+}
+
+// Run checks the balance invariants and reports every violation. With fix set,
+// ledger drift is corrected by recording reconciliation entries.
+func (s *ReconciliationService) Run(ctx context.Context, fix bool) (*model.ReconciliationReport, error) {
+	report := &model.ReconciliationReport{
+		StartedAt:    time.Now(),
+		FixRequested: fix,
+		Issues:       []model.ReconciliationIssue{},
+	}
+
+	if err := s.checkConservation(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNegativeBalances(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCompletedAt(ctx, report); err != nil {
+		return nil, err
+	}
+
+	hasPostings, err := s.reconciliationRepo.HasLedgerPostings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if hasPostings {
+		report.LedgerChecked = true
+		if err := s.checkLedger(ctx, report, fix); err != nil {
+			return nil, err
+		}
+	}
+
+	report.FinishedAt = time.Now()
+
+	s.logger.Info().
+		Int64("accounts_checked", report.AccountsChecked).
+		Int("issues", len(report.Issues)).
+		Bool("clean", report.Clean()).
+		Bool("fix", fix).
+		Dur("duration", report.FinishedAt.Sub(report.StartedAt)).
+		Msg("reconciliation completed")
+
+	return report, nil
+}
+
+// checkConservation compares, per currency, the sum of the balances with the money funded into
+// the system, so a shortfall in one currency cannot be hidden by a surplus in another
+func (s *ReconciliationService) checkConservation(ctx context.Context, report *model.ReconciliationReport) error {
+	count, totals, err := s.reconciliationRepo.SumBalances(ctx)
+	if err != nil {
+		return err
+	}
+
+	funded, err := s.reconciliationRepo.SumFunding(ctx)
+	if err != nil {
+		return err
+	}
+
+	codes := make([]string, 0, len(totals))
+	for code := range totals {
+		codes = append(codes, code)
+	}
+	for code := range funded {
+		if _, ok := totals[code]; !ok {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	report.AccountsChecked = count
+	report.Totals = make([]model.ReconciliationTotal, 0, len(codes))
+	for _, code := range codes {
+		total, fund := totals[code], funded[code]
+		report.Totals = append(report.Totals, model.ReconciliationTotal{
+			Currency:     code,
+			TotalBalance: total.String(),
+			TotalFunded:  fund.String(),
+		})
+
+		if !total.Equal(fund) {
+			s.addIssue(report, model.ReconciliationIssue{
+				Check:      model.ReconciliationCheckConservation,
+				Currency:   code,
+				Expected:   fund.String(),
+				Actual:     total.String(),
+				Difference: total.Sub(fund).String(),
+			})
+		}
+	}
+
+	return nil
+}
+
+// checkNegativeBalances reports every balance that is below its overdraft limit
+func (s *ReconciliationService) checkNegativeBalances(ctx context.Context, report *model.ReconciliationReport) error {
+	accounts, err := s.reconciliationRepo.GetBalancesBelowOverdraft(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		s.addIssue(report, model.ReconciliationIssue{
+			Check:     model.ReconciliationCheckNegativeBalance,
+			AccountID: &account.ID,
+			Expected:  ">= " + account.OverdraftLimit.Neg().String(),
+			Actual:    account.Balance.String(),
+		})
+	}
+
+	return nil
+}
+
+func (s *ReconciliationService) checkCompletedAt(ctx context.Context, report *model.ReconciliationReport) error {
+	ids, err := s.reconciliationRepo.GetCompletedWithoutTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range ids {
+		s.addIssue(report, model.ReconciliationIssue{
+			Check:         model.ReconciliationCheckMissingCompleted,
+			TransactionID: &ids[i],
+		})
+	}
+
+	return nil
+}
+
+// checkLedger rebuilds every balance from its postings and compares it with accounts.balance
+func (s *ReconciliationService) checkLedger(ctx context.Context, report *model.ReconciliationReport, fix bool) error {
+	drifts, err := s.reconciliationRepo.GetLedgerDrift(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, drift := range drifts {
+		issue := model.ReconciliationIssue{
+			Check:      model.ReconciliationCheckLedgerDrift,
+			AccountID:  &drift.AccountID,
+			Expected:   drift.LedgerBalance.String(),
+			Actual:     drift.Balance.String(),
+			Difference: drift.Balance.Sub(drift.LedgerBalance).String(),
+		}
+
+		if fix {
+			fixed, err := s.fixLedgerDrift(ctx, drift.AccountID)
+			if err != nil {
+				return err
+			}
+			issue.Fixed = fixed
+		}
+
+		s.addIssue(report, issue)
+	}
+
+	return nil
+}
+
+// fixLedgerDrift records a reconciliation entry for the difference between the balance and
+// its postings. The account is locked and the drift recomputed so a concurrent transfer
+// cannot be mistaken for drift.
+func (s *ReconciliationService) fixLedgerDrift(ctx context.Context, accountID int64) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	account, err := s.accountRepo.GetByIDForUpdate(ctx, tx, accountID)
+	if err != nil {
+		return false, fmt.Errorf("failed to lock account %d: %w", accountID, err)
+	}
+
+	posted, err := s.reconciliationRepo.GetLedgerBalance(ctx, tx, accountID)
+	if err != nil {
+		return false, err
+	}
+
+	difference := account.Balance.Sub(posted)
+	if difference.IsZero() {
+		return false, nil
+	}
+
+	entry := &model.LedgerEntry{
+		AccountID: accountID,
+		Direction: model.LedgerDirectionCredit,
+		EntryType: model.LedgerEntryTypeReconciliation,
+		Amount:    difference,
+	}
+	if difference.IsNegative() {
+		entry.Direction = model.LedgerDirectionDebit
+		entry.Amount = difference.Neg()
+	}
+
+	if err := s.ledgerRepo.RecordCorrection(ctx, tx, entry); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	s.logger.Info().
+		Int64("account_id", accountID).
+		Int64("ledger_entry_id", entry.ID).
+		Str("direction", string(entry.Direction)).
+		Str("amount", entry.Amount.String()).
+		Msg("recorded reconciliation entry")
+
+	return true, nil
+}
+
+// addIssue appends the issue to the report and logs it as a structured event
+func (s *ReconciliationService) addIssue(report *model.ReconciliationReport, issue model.ReconciliationIssue) {
+	report.Issues = append(report.Issues, issue)
+
+	event := s.logger.Warn().Str("check", string(issue.Check))
+	if issue.Currency != "" {
+		event = event.Str("currency", issue.Currency)
+	}
+	if issue.AccountID != nil {
+		event = event.Int64("account_id", *issue.AccountID)
+	}
+	if issue.TransactionID != nil {
+		event = event.Int64("transaction_id", *issue.TransactionID)
+	}
+	event.
+		Str("expected", issue.Expected).
+		Str("actual", issue.Actual).
+		Str("difference", issue.Difference).
+		Bool("fixed", issue.Fixed).
+		Msg("reconciliation drift detected")
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/event"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/jackc/pgx/v5"
+)
+
+// DefaultScheduledListLimit is the page size of the scheduled transfer list when none is requested
+const DefaultScheduledListLimit = 20
+
+// ExecuteDueScheduled settles up to limit scheduled transfers whose execution time has
+// passed and returns how many it processed. Each transfer runs in its own database
+// transaction, so one rejection does not hold back the others.
+func (s *TransactionService) ExecuteDueScheduled(ctx context.Context, limit int) (int, error) {
+	return s.executeClaimed(ctx, limit, "scheduled", s.transactionRepo.ClaimDueScheduled)
+}
+
+// executeClaimed settles up to limit transfers handed out by claim, each in its own database
+// transaction, and returns how many it processed
+func (s *TransactionService) executeClaimed(ctx context.Context, limit int, queue string, claim func(ctx context.Context, tx pgx.Tx) (*model.Transaction, error)) (int, error) {
+	processed := 0
+	for processed < limit {
+		executed, err := s.executeNext(ctx, queue, claim)
+		if err != nil {
+			return processed, err
+		}
+		if !executed {
+			break
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// executeNext claims one transfer and runs it through the regular settlement.
+// It reports false when nothing is left to claim.
+func (s *TransactionService) executeNext(ctx context.Context, queue string, claim func(ctx context.Context, tx pgx.Tx) (*model.Transaction, error)) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to begin transaction")
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	// SKIP LOCKED lets several workers and instances run without claiming the same transfer
+	transaction, err := claim(ctx, tx)
+	if err != nil {
+		s.logger.Error().Err(err).Str("queue", queue).Msg("failed to claim transaction")
+		return false, fmt.Errorf("failed to claim %s transaction: %w", queue, err)
+	}
+	if transaction == nil {
+		return false, nil
+	}
+
+	// A business rejection has already marked the transfer failed with its reason and is committed
+	settleErr := s.settle(ctx, tx, transaction)
+	if settleErr != nil {
+		if _, ok := errs.IsHTTPError(settleErr); !ok {
+			return false, settleErr
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("failed to commit transaction")
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	if settleErr != nil {
+		s.logger.Warn().
+			Err(settleErr).
+			Int64("transaction_id", transaction.ID).
+			Msg(queue + " transaction failed")
+	} else {
+		s.logger.Info().
+			Int64("transaction_id", transaction.ID).
+			Int64("source_account_id", transaction.SourceAccountID).
+			Int64("destination_account_id", transaction.DestinationAccountID).
+			Str("amount", transaction.Amount.String()).
+			Msg(queue + " transaction completed successfully")
+	}
+
+	return true, nil
+}
+
+// CancelScheduledTransaction cancels a transfer that is still waiting for its execution time
+func (s *TransactionService) CancelScheduledTransaction(ctx context.Context, transactionID int64) (*model.TransactionResponse, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	// The row lock waits for a scheduler that is executing this transfer right now
+	transaction, err := s.transactionRepo.GetByIDForUpdate(ctx, tx, transactionID)
+	if err != nil {
+		if err.Error() == "transaction not found" {
+			return nil, errs.WrapHTTPError(errs.ErrTransactionNotFound, "transaction with ID %d not found", transactionID)
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if transaction.Status != model.TransactionStatusScheduled {
+		return nil, errs.WrapHTTPError(errs.ErrTransactionNotCancellable, "transaction with status %s cannot be cancelled", transaction.Status)
+	}
+
+	if err := s.transactionRepo.UpdateStatus(ctx, tx, transaction.ID, model.TransactionStatusCancelled); err != nil {
+		s.logger.Error().Err(err).Msg("failed to update transaction status")
+		return nil, fmt.Errorf("failed to update transaction status: %w", err)
+	}
+	transaction.Status = model.TransactionStatusCancelled
+
+	if err := s.outbox.recordTransaction(ctx, tx, event.TypeTransactionCancelled, transaction); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	s.logger.Info().Int64("transaction_id", transaction.ID).Msg("scheduled transaction cancelled")
+
+	return toTransactionResponse(transaction), nil
+}
+
+// ListScheduledTransactions returns a page of future-dated transfers in execution order.
+// Without a status filter only transfers that are still waiting are listed.
+func (s *TransactionService) ListScheduledTransactions(ctx context.Context, req *model.ListScheduledTransactionsRequest) (*model.CursorPaginatedResponse[model.TransactionResponse], error) {
+	filter := &model.ScheduledTransactionFilter{
+		Status: model.TransactionStatusScheduled,
+		Limit:  req.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultScheduledListLimit
+	}
+	if req.Status != "" {
+		filter.Status = model.TransactionStatus(req.Status)
+	}
+	if req.AccountID != 0 {
+		filter.AccountID = &req.AccountID
+	}
+	if req.Cursor != "" {
+		cursor, err := model.DecodeKeysetCursor(req.Cursor)
+		if err != nil {
+			return nil, errs.ErrInvalidFormat.WithMessage("Invalid cursor")
+		}
+		filter.After = cursor
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	limit := filter.Limit
+	filter.Limit = limit + 1
+
+	transactions, err := s.transactionRepo.ListScheduled(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list scheduled transactions")
+		return nil, fmt.Errorf("failed to list scheduled transactions: %w", err)
+	}
+
+	response := &model.CursorPaginatedResponse[model.TransactionResponse]{
+		Data:  make([]model.TransactionResponse, 0, limit),
+		Limit: limit,
+	}
+
+	if len(transactions) > limit {
+		transactions = transactions[:limit]
+		last := transactions[limit-1]
+		response.HasMore = true
+		response.NextCursor = model.KeysetCursor{Timestamp: *last.ExecuteAt, ID: last.ID}.Encode()
+	}
+
+	for _, transaction := range transactions {
+		response.Data = append(response.Data, *toTransactionResponse(transaction))
+	}
+
+	return response, nil
+}
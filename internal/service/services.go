@@ -1,18 +1,120 @@
 package service
 
 import (
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/event"
+	"github.com/chandra-shekhar/internal-transfers/internal/fx"
 	"github.com/chandra-shekhar/internal-transfers/internal/repository"
 	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/chandra-shekhar/internal-transfers/internal/webhook"
+	"github.com/shopspring/decimal"
 )
 
 type Services struct {
-	Account     *AccountService
-	Transaction *TransactionService
+	Account        *AccountService
+	Transaction    *TransactionService
+	Idempotency    *IdempotencyService
+	Reconciliation *ReconciliationService
+	StandingOrder  *StandingOrderService
+	Hold           *HoldService
+	FX             *FXService
+	Velocity       *VelocityService
+	Fee            *FeeService
+	Interest       *InterestService
+	Outbox         *OutboxService
+	Webhook        *WebhookService
+	EventFeed      *EventFeedService
+	Snapshot       *BalanceSnapshotService
+	Statement      *StatementService
+	AccountStream  *AccountStreamService
 }
 
 func NewServices(s *server.Server, repos *repository.Repositories) *Services {
+	idempotencyRetention := time.Duration(s.Config.Idempotency.RetentionHours) * time.Hour
+	idempotency := NewIdempotencyService(repos.Idempotency, idempotencyRetention, s.Logger)
+
+	fxService := NewFXService(repos.Account, repos.FX, newRateProvider(s, repos), decimal.RequireFromString(s.Config.FX.Spread),
+		time.Duration(s.Config.FX.QuoteTTL)*time.Second, fxPnLAccounts(s), s.Logger)
+	velocity := NewVelocityService(repos.Account, repos.VelocityLimit, s.Logger)
+	fees := NewFeeService(repos.Account, repos.FeeRule, s.Logger)
+	webhooks := newWebhookService(s, repos)
+	outbox := NewOutboxService(s.DB, repos.Outbox, repos.EventFeed, newPublisher(s), webhooks, s.Logger)
+	transaction := NewTransactionService(s.DB, repos.Account, repos.Transaction, repos.Ledger, fxService, velocity, fees, outbox, idempotency, s.Logger)
+	standingOrderRetryDelay := time.Duration(s.Config.Scheduler.RetryDelay) * time.Second
+	holdTTL := time.Duration(s.Config.Hold.DefaultTTL) * time.Second
+
 	return &Services{
-		Account:     NewAccountService(repos.Account, s.Logger),
-		Transaction: NewTransactionService(s.DB, repos.Account, repos.Transaction, s.Logger),
+		Account:        NewAccountService(s.DB, repos.Account, repos.AccountLimit, repos.Ledger, transaction, outbox, idempotency, s.Config.Currency.Default, s.Logger),
+		Transaction:    transaction,
+		Idempotency:    idempotency,
+		Reconciliation: NewReconciliationService(s.DB, repos.Account, repos.Ledger, repos.Reconciliation, s.Logger),
+		StandingOrder:  NewStandingOrderService(s.DB, repos.Account, repos.StandingOrder, transaction, idempotency, standingOrderRetryDelay, s.Logger),
+		Hold:           NewHoldService(s.DB, repos.Account, repos.Hold, transaction, idempotency, holdTTL, s.Logger),
+		FX:             fxService,
+		Velocity:       velocity,
+		Fee:            fees,
+		Interest:       NewInterestService(s.DB, repos.Account, repos.Interest, transaction, s.Logger),
+		Outbox:         outbox,
+		Webhook:        webhooks,
+		EventFeed:      NewEventFeedService(repos.EventFeed, s.Logger),
+		Snapshot: NewBalanceSnapshotService(s.DB, repos.Account, repos.Snapshot,
+			time.Duration(s.Config.Snapshot.Delay)*time.Second, s.Logger),
+		Statement: NewStatementService(s.DB, repos.Account, repos.Ledger, repos.Snapshot, s.Logger),
+		AccountStream: NewAccountStreamService(s.DB, repos.Account, repos.Transaction, repos.Ledger,
+			time.Duration(s.Config.Stream.Heartbeat)*time.Second, s.Logger),
+	}
+}
+
+// newRateProvider returns the configured source of exchange rates
+func newRateProvider(s *server.Server, repos *repository.Repositories) fx.RateProvider {
+	if s.Config.FX.Provider != "file" {
+		return repos.FX
+	}
+
+	provider, err := fx.LoadFile(s.Config.FX.RatesFile)
+	if err != nil {
+		s.Logger.Fatal().Err(err).Str("path", s.Config.FX.RatesFile).Msg("could not load fx rates file")
+	}
+
+	return provider
+}
+
+// newPublisher returns the configured destination of outbox events
+func newPublisher(s *server.Server) event.Publisher {
+	if s.Config.Outbox.Publisher != "file" {
+		return event.NewStdoutPublisher()
 	}
+
+	publisher, err := event.OpenFilePublisher(s.Config.Outbox.File)
+	if err != nil {
+		s.Logger.Fatal().Err(err).Str("path", s.Config.Outbox.File).Msg("could not open outbox file")
+	}
+
+	return publisher
+}
+
+// newWebhookService returns the webhook service with the configured retry policy. A claimed
+// delivery is leased for twice the receiver timeout, so it is not sent twice while in flight.
+func newWebhookService(s *server.Server, repos *repository.Repositories) *WebhookService {
+	cfg := s.Config.Webhook
+	timeout := time.Duration(cfg.Timeout) * time.Second
+	retry := webhook.RetryPolicy{
+		MaxAttempts: cfg.MaxAttempts,
+		BaseDelay:   time.Duration(cfg.BackoffBase) * time.Second,
+		MaxDelay:    time.Duration(cfg.BackoffMax) * time.Second,
+	}
+
+	return NewWebhookService(s.DB, repos.Webhook, webhook.NewSender(timeout), retry, 2*timeout,
+		time.Duration(cfg.RotationGrace)*time.Second, s.Logger)
+}
+
+// fxPnLAccounts returns the FX P&L account of every currency FX can convert into
+func fxPnLAccounts(s *server.Server) map[string]int64 {
+	accounts, err := fx.ParseAccounts(s.Config.FX.PnLAccounts)
+	if err != nil {
+		s.Logger.Fatal().Err(err).Msg("invalid fx pnl accounts")
+	}
+
+	return accounts
 }
@@ -0,0 +1,499 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/chandra-shekhar/internal-transfers/internal/schedule"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultStandingOrderListLimit is the page size of the standing order list when none is requested
+const DefaultStandingOrderListLimit = 20
+
+type StandingOrderService struct {
+	db                database.DB
+	accountRepo       repository.AccountRepository
+	standingOrderRepo repository.StandingOrderRepository
+	transactions      *TransactionService
+	idempotency       *IdempotencyService
+	retryDelay        time.Duration
+	logger            *zerolog.Logger
+}
+
+func NewStandingOrderService(db database.DB, accountRepo repository.AccountRepository, standingOrderRepo repository.StandingOrderRepository, transactions *TransactionService, idempotency *IdempotencyService, retryDelay time.Duration, logger *zerolog.Logger) *StandingOrderService {
+	return &StandingOrderService{
+		db:                db,
+		accountRepo:       accountRepo,
+		standingOrderRepo: standingOrderRepo,
+		transactions:      transactions,
+		idempotency:       idempotency,
+		retryDelay:        retryDelay,
+		logger:            logger,
+	}
+}
+
+// CreateStandingOrder validates the schedule and stores the order with its first run time
+func (s *StandingOrderService) CreateStandingOrder(ctx context.Context, req *model.CreateStandingOrderRequest) (*model.StandingOrderResponse, error) {
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount format: %w", err)
+	}
+
+	response, err := inIdempotentTx(ctx, s.db, s.idempotency, s.logger, idempotencyScopeCreateStandingOrder, req.IdempotencyKey, req,
+		func(tx pgx.Tx) (*model.StandingOrderResponse, error) {
+			return s.createStandingOrder(ctx, tx, req, amount)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().
+		Int64("standing_order_id", response.ID).
+		Int64("source_account_id", response.SourceAccountID).
+		Int64("destination_account_id", response.DestinationAccountID).
+		Str("frequency", string(response.Frequency)).
+		Msg("standing order created successfully")
+
+	return response, nil
+}
+
+func (s *StandingOrderService) createStandingOrder(ctx context.Context, tx pgx.Tx, req *model.CreateStandingOrderRequest, amount decimal.Decimal) (*model.StandingOrderResponse, error) {
+	if !amount.IsPositive() {
+		return nil, errs.ErrAmountMustBePositive
+	}
+
+	if req.SourceAccountID == req.DestinationAccountID {
+		return nil, errs.ErrSameAccount
+	}
+
+	sourceAccount, err := s.accountRepo.GetByID(ctx, req.SourceAccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.ErrSourceAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to verify source account: %w", err)
+	}
+
+	destinationAccount, err := s.accountRepo.GetByID(ctx, req.DestinationAccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.ErrDestinationAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to verify destination account: %w", err)
+	}
+
+	if rejection := checkTransferable(sourceAccount, destinationAccount, amount); rejection != nil {
+		return nil, rejection
+	}
+
+	order := &model.StandingOrder{
+		SourceAccountID:         req.SourceAccountID,
+		DestinationAccountID:    req.DestinationAccountID,
+		Amount:                  amount,
+		Frequency:               schedule.Frequency(req.Frequency),
+		StartAt:                 time.Now(),
+		EndAt:                   req.EndAt,
+		MaxOccurrences:          req.MaxOccurrences,
+		InsufficientFundsPolicy: model.InsufficientFundsSkip,
+		MaxRetries:              req.MaxRetries,
+		Status:                  model.StandingOrderStatusActive,
+	}
+	if req.StartAt != nil {
+		order.StartAt = *req.StartAt
+	}
+	if req.OnInsufficientFunds != "" {
+		order.InsufficientFundsPolicy = model.InsufficientFundsPolicy(req.OnInsufficientFunds)
+	}
+
+	switch order.Frequency {
+	case schedule.FrequencyWeekly, schedule.FrequencyMonthly:
+		if req.Day == nil {
+			return nil, errs.ErrInvalidStandingOrder.WithMessage("day is required for weekly and monthly orders")
+		}
+		order.ScheduleDay = req.Day
+	case schedule.FrequencyCron:
+		if req.Cron == "" {
+			return nil, errs.ErrInvalidStandingOrder.WithMessage("cron is required for cron orders")
+		}
+		order.CronExpression = &req.Cron
+	}
+
+	if order.InsufficientFundsPolicy == model.InsufficientFundsRetry && order.MaxRetries == 0 {
+		return nil, errs.ErrInvalidStandingOrder.WithMessage("max_retries must be at least 1 for the retry policy")
+	}
+
+	sched, err := scheduleFor(order)
+	if err != nil {
+		return nil, errs.ErrInvalidStandingOrder.WithMessage(err.Error())
+	}
+
+	// The start time itself is the first occurrence when it matches the schedule
+	first := sched.Next(order.StartAt.Add(-time.Nanosecond))
+	if first.IsZero() || (order.EndAt != nil && first.After(*order.EndAt)) {
+		return nil, errs.ErrInvalidStandingOrder.WithMessage("schedule has no occurrence before end_at")
+	}
+	order.NextRunAt = &first
+
+	if err := s.standingOrderRepo.Create(ctx, tx, order); err != nil {
+		s.logger.Error().Err(err).Msg("failed to create standing order")
+		return nil, fmt.Errorf("failed to create standing order: %w", err)
+	}
+
+	return toStandingOrderResponse(order), nil
+}
+
+// GetStandingOrder retrieves a standing order by its ID
+func (s *StandingOrderService) GetStandingOrder(ctx context.Context, orderID int64) (*model.StandingOrderResponse, error) {
+	order, err := s.standingOrderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		if err.Error() == "standing order not found" {
+			return nil, errs.WrapHTTPError(errs.ErrStandingOrderNotFound, "standing order with ID %d not found", orderID)
+		}
+		s.logger.Error().Err(err).Int64("standing_order_id", orderID).Msg("failed to get standing order")
+		return nil, fmt.Errorf("failed to get standing order: %w", err)
+	}
+
+	return toStandingOrderResponse(order), nil
+}
+
+// ListStandingOrders returns a page of the orders paying out of an account, oldest first
+func (s *StandingOrderService) ListStandingOrders(ctx context.Context, req *model.ListStandingOrdersRequest) (*model.CursorPaginatedResponse[model.StandingOrderResponse], error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultStandingOrderListLimit
+	}
+
+	var after *model.KeysetCursor
+	if req.Cursor != "" {
+		cursor, err := model.DecodeKeysetCursor(req.Cursor)
+		if err != nil {
+			return nil, errs.ErrInvalidFormat.WithMessage("Invalid cursor")
+		}
+		after = cursor
+	}
+
+	_, err := s.accountRepo.GetByID(ctx, req.AccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", req.AccountID)
+		}
+		return nil, fmt.Errorf("failed to verify account: %w", err)
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	orders, err := s.standingOrderRepo.ListBySourceAccount(ctx, req.AccountID, after, limit+1)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", req.AccountID).Msg("failed to list standing orders")
+		return nil, fmt.Errorf("failed to list standing orders: %w", err)
+	}
+
+	response := &model.CursorPaginatedResponse[model.StandingOrderResponse]{
+		Data:  make([]model.StandingOrderResponse, 0, limit),
+		Limit: limit,
+	}
+
+	if len(orders) > limit {
+		orders = orders[:limit]
+		last := orders[limit-1]
+		response.HasMore = true
+		response.NextCursor = model.KeysetCursor{Timestamp: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	for _, order := range orders {
+		response.Data = append(response.Data, *toStandingOrderResponse(order))
+	}
+
+	return response, nil
+}
+
+// PauseStandingOrder stops an active order from running until it is resumed
+func (s *StandingOrderService) PauseStandingOrder(ctx context.Context, orderID int64) (*model.StandingOrderResponse, error) {
+	return s.changeState(ctx, orderID, func(order *model.StandingOrder) error {
+		if order.Status != model.StandingOrderStatusActive {
+			return errs.WrapHTTPError(errs.ErrStandingOrderStateConflict, "standing order with status %s cannot be paused", order.Status)
+		}
+		order.Status = model.StandingOrderStatusPaused
+		order.NextRunAt = nil
+		return nil
+	})
+}
+
+// ResumeStandingOrder reactivates a paused or suspended order from its next occurrence.
+// Occurrences missed in the meantime are not made up.
+func (s *StandingOrderService) ResumeStandingOrder(ctx context.Context, orderID int64) (*model.StandingOrderResponse, error) {
+	return s.changeState(ctx, orderID, func(order *model.StandingOrder) error {
+		if order.Status != model.StandingOrderStatusPaused && order.Status != model.StandingOrderStatusSuspended {
+			return errs.WrapHTTPError(errs.ErrStandingOrderStateConflict, "standing order with status %s cannot be resumed", order.Status)
+		}
+
+		sched, err := scheduleFor(order)
+		if err != nil {
+			return fmt.Errorf("failed to build schedule: %w", err)
+		}
+
+		order.Status = model.StandingOrderStatusActive
+		order.RetryCount = 0
+		advance(order, sched, time.Now())
+		return nil
+	})
+}
+
+// CancelStandingOrder ends an order for good
+func (s *StandingOrderService) CancelStandingOrder(ctx context.Context, orderID int64) (*model.StandingOrderResponse, error) {
+	return s.changeState(ctx, orderID, func(order *model.StandingOrder) error {
+		if order.Status == model.StandingOrderStatusCompleted || order.Status == model.StandingOrderStatusCancelled {
+			return errs.WrapHTTPError(errs.ErrStandingOrderStateConflict, "standing order with status %s cannot be cancelled", order.Status)
+		}
+		order.Status = model.StandingOrderStatusCancelled
+		order.NextRunAt = nil
+		return nil
+	})
+}
+
+// changeState locks the order, applies change and saves the result. The lock waits for a
+// scheduler that is running the order right now.
+func (s *StandingOrderService) changeState(ctx context.Context, orderID int64, change func(order *model.StandingOrder) error) (*model.StandingOrderResponse, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	order, err := s.standingOrderRepo.GetByIDForUpdate(ctx, tx, orderID)
+	if err != nil {
+		if err.Error() == "standing order not found" {
+			return nil, errs.WrapHTTPError(errs.ErrStandingOrderNotFound, "standing order with ID %d not found", orderID)
+		}
+		return nil, fmt.Errorf("failed to get standing order: %w", err)
+	}
+
+	if err := change(order); err != nil {
+		return nil, err
+	}
+
+	if err := s.standingOrderRepo.UpdateState(ctx, tx, order); err != nil {
+		s.logger.Error().Err(err).Int64("standing_order_id", orderID).Msg("failed to update standing order")
+		return nil, fmt.Errorf("failed to update standing order: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	s.logger.Info().
+		Int64("standing_order_id", order.ID).
+		Str("status", string(order.Status)).
+		Msg("standing order status changed")
+
+	return toStandingOrderResponse(order), nil
+}
+
+// RunDue executes up to limit due standing orders and returns how many it processed.
+// Each occurrence runs in its own database transaction.
+func (s *StandingOrderService) RunDue(ctx context.Context, limit int) (int, error) {
+	processed := 0
+	for processed < limit {
+		ran, err := s.runNext(ctx)
+		if err != nil {
+			return processed, err
+		}
+		if !ran {
+			break
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// runNext claims one due order, makes its transfer through the regular transfer path and
+// moves the order on according to the outcome. It reports false when nothing is due.
+func (s *StandingOrderService) runNext(ctx context.Context) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to begin transaction")
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	order, err := s.standingOrderRepo.ClaimDue(ctx, tx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to claim standing order")
+		return false, fmt.Errorf("failed to claim standing order: %w", err)
+	}
+	if order == nil {
+		return false, nil
+	}
+
+	sched, err := scheduleFor(order)
+	if err != nil {
+		return false, fmt.Errorf("failed to build schedule for standing order %d: %w", order.ID, err)
+	}
+
+	transaction := &model.Transaction{
+		SourceAccountID:      order.SourceAccountID,
+		DestinationAccountID: order.DestinationAccountID,
+		Amount:               order.Amount,
+		Status:               model.TransactionStatusPending,
+		Kind:                 model.TransactionKindTransfer,
+		StandingOrderID:      &order.ID,
+	}
+
+	now := time.Now()
+	order.LastRunAt = &now
+
+	// A rejected occurrence keeps its failed transaction, the policy decides what comes next
+	executeErr := s.transactions.execute(ctx, tx, transaction)
+	if executeErr != nil {
+		rejection, ok := errs.IsHTTPError(executeErr)
+		if !ok {
+			return false, executeErr
+		}
+		s.applyRejection(order, sched, rejection, now)
+	} else {
+		order.Occurrences++
+		order.RetryCount = 0
+		order.LastFailureCode = nil
+		order.LastFailureReason = nil
+		advance(order, sched, now)
+	}
+
+	if err := s.standingOrderRepo.UpdateState(ctx, tx, order); err != nil {
+		s.logger.Error().Err(err).Int64("standing_order_id", order.ID).Msg("failed to update standing order")
+		return false, fmt.Errorf("failed to update standing order: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("failed to commit transaction")
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	logEvent := s.logger.Info()
+	if executeErr != nil {
+		logEvent = s.logger.Warn().Err(executeErr)
+	}
+	logEvent.
+		Int64("standing_order_id", order.ID).
+		Int64("transaction_id", transaction.ID).
+		Str("status", string(order.Status)).
+		Msg("standing order occurrence processed")
+
+	return true, nil
+}
+
+// applyRejection moves an order on after its transfer was rejected. Insufficient funds follow
+// the order's policy; any other rejection cannot be fixed by waiting and suspends the order.
+func (s *StandingOrderService) applyRejection(order *model.StandingOrder, sched schedule.Schedule, rejection *errs.HTTPError, now time.Time) {
+	order.LastFailureCode = &rejection.Code
+	order.LastFailureReason = &rejection.Message
+
+	if rejection.Code != errs.ErrInsufficientBalance.Code {
+		order.Status = model.StandingOrderStatusSuspended
+		order.NextRunAt = nil
+		return
+	}
+
+	switch order.InsufficientFundsPolicy {
+	case model.InsufficientFundsRetry:
+		if order.RetryCount < order.MaxRetries {
+			order.RetryCount++
+			retryAt := now.Add(s.retryDelay)
+			order.NextRunAt = &retryAt
+			return
+		}
+		order.RetryCount = 0
+		advance(order, sched, now)
+	case model.InsufficientFundsSuspend:
+		order.Status = model.StandingOrderStatusSuspended
+		order.NextRunAt = nil
+	default:
+		order.RetryCount = 0
+		advance(order, sched, now)
+	}
+}
+
+// advance sets the next run after now, or completes the order once its end date or
+// occurrence cap is reached
+func advance(order *model.StandingOrder, sched schedule.Schedule, now time.Time) {
+	next := sched.Next(now)
+	capped := order.MaxOccurrences != nil && order.Occurrences >= *order.MaxOccurrences
+	ended := next.IsZero() || (order.EndAt != nil && next.After(*order.EndAt))
+
+	if capped || ended {
+		order.Status = model.StandingOrderStatusCompleted
+		order.NextRunAt = nil
+		return
+	}
+
+	order.NextRunAt = &next
+}
+
+// scheduleFor builds the schedule an order was created with
+func scheduleFor(order *model.StandingOrder) (schedule.Schedule, error) {
+	day := 0
+	if order.ScheduleDay != nil {
+		day = *order.ScheduleDay
+	}
+
+	expression := ""
+	if order.CronExpression != nil {
+		expression = *order.CronExpression
+	}
+
+	return schedule.New(order.Frequency, day, expression, order.StartAt)
+}
+
+// toStandingOrderResponse converts a standing order into its API representation
+func toStandingOrderResponse(order *model.StandingOrder) *model.StandingOrderResponse {
+	return &model.StandingOrderResponse{
+		ID:                   order.ID,
+		SourceAccountID:      order.SourceAccountID,
+		DestinationAccountID: order.DestinationAccountID,
+		Amount:               order.Amount.String(),
+		Currency:             order.Currency,
+		Frequency:            order.Frequency,
+		Day:                  order.ScheduleDay,
+		Cron:                 order.CronExpression,
+		StartAt:              order.StartAt,
+		EndAt:                order.EndAt,
+		MaxOccurrences:       order.MaxOccurrences,
+		Occurrences:          order.Occurrences,
+		OnInsufficientFunds:  order.InsufficientFundsPolicy,
+		MaxRetries:           order.MaxRetries,
+		RetryCount:           order.RetryCount,
+		Status:               order.Status,
+		NextRunAt:            order.NextRunAt,
+		LastRunAt:            order.LastRunAt,
+		LastFailureCode:      order.LastFailureCode,
+		LastFailureReason:    order.LastFailureReason,
+		CreatedAt:            order.CreatedAt,
+	}
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/currency"
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/chandra-shekhar/internal-transfers/internal/statement"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+// statementBatchSize is how many ledger entries are read and written at a time
+const statementBatchSize = 500
+
+// StatementService produces account statements for a period
+type StatementService struct {
+	db           database.DB
+	accountRepo  repository.AccountRepository
+	ledgerRepo   repository.LedgerRepository
+	snapshotRepo repository.BalanceSnapshotRepository
+	logger       *zerolog.Logger
+}
+
+func NewStatementService(db database.DB, accountRepo repository.AccountRepository, ledgerRepo repository.LedgerRepository, snapshotRepo repository.BalanceSnapshotRepository, logger *zerolog.Logger) *StatementService {
+	return &StatementService{
+		db:           db,
+		accountRepo:  accountRepo,
+		ledgerRepo:   ledgerRepo,
+		snapshotRepo: snapshotRepo,
+		logger:       logger,
+	}
+}
+
+// AccountStatement is a statement that is ready to be written. Its balances and lines are all
+// read in one repeatable read transaction, so they agree with each other however long writing
+// takes. It must be closed.
+type AccountStatement struct {
+	Format    statement.Format
+	Statement *statement.Statement
+	tx        pgx.Tx
+	service   *StatementService
+}
+
+// OpenStatement checks the request and reads the opening and closing balances of the period.
+// A period that ends in the future is cut off at now.
+func (s *StatementService) OpenStatement(ctx context.Context, req *model.GetStatementRequest) (*AccountStatement, error) {
+	format := statement.FormatCSV
+	if req.Format != "" {
+		format = statement.Format(req.Format)
+	}
+	if !format.Valid() {
+		return nil, errs.ErrInvalidFormat.WithMessage("format must be one of csv, ofx, camt053 or beancount")
+	}
+
+	from, err := parseStatementTime(req.From, false)
+	if err != nil {
+		return nil, errs.ErrInvalidFormat.WithMessage("from must be an RFC 3339 timestamp or a date")
+	}
+	to, err := parseStatementTime(req.To, true)
+	if err != nil {
+		return nil, errs.ErrInvalidFormat.WithMessage("to must be an RFC 3339 timestamp or a date")
+	}
+	now := time.Now()
+	if to.After(now) {
+		to = now
+	}
+	if !from.Before(to) {
+		return nil, errs.ErrInvalidFormat.WithMessage("from must be before to and not in the future")
+	}
+
+	account, err := s.accountRepo.GetByID(ctx, req.AccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", req.AccountID)
+		}
+		s.logger.Error().Err(err).Int64("account_id", req.AccountID).Msg("failed to get account")
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	precision := int32(2)
+	if c, ok := currency.Lookup(account.Currency); ok {
+		precision = c.Precision
+	}
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	opened := false
+	defer func() {
+		if !opened {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	opening, err := s.snapshotRepo.GetBalanceBefore(ctx, tx, account.ID, from)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", account.ID).Msg("failed to get opening balance")
+		return nil, fmt.Errorf("failed to get opening balance: %w", err)
+	}
+	closing, err := s.snapshotRepo.GetBalanceBefore(ctx, tx, account.ID, to)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", account.ID).Msg("failed to get closing balance")
+		return nil, fmt.Errorf("failed to get closing balance: %w", err)
+	}
+	opened = true
+
+	return &AccountStatement{
+		Format: format,
+		Statement: &statement.Statement{
+			AccountID:      account.ID,
+			Currency:       account.Currency,
+			Precision:      precision,
+			From:           from.UTC(),
+			To:             to.UTC(),
+			OpeningBalance: opening,
+			ClosingBalance: closing,
+			GeneratedAt:    now.UTC(),
+		},
+		tx:      tx,
+		service: s,
+	}, nil
+}
+
+// WriteTo writes the statement to w a batch of lines at a time, calling flush after each batch
+// so the client receives it while the next is read
+func (a *AccountStatement) WriteTo(ctx context.Context, w io.Writer, flush func()) error {
+	writer, err := statement.NewWriter(a.Format, w, a.Statement)
+	if err != nil {
+		return fmt.Errorf("failed to write statement header: %w", err)
+	}
+	flush()
+
+	filter := &model.StatementEntryFilter{
+		AccountID: a.Statement.AccountID,
+		From:      a.Statement.From,
+		To:        a.Statement.To,
+		Limit:     statementBatchSize,
+	}
+	balance := a.Statement.OpeningBalance
+
+	for {
+		entries, err := a.service.ledgerRepo.ListStatementEntries(ctx, a.tx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to get statement entries: %w", err)
+		}
+
+		for _, entry := range entries {
+			balance = balance.Add(entry.SignedAmount())
+			if err := writer.WriteLine(toStatementLine(entry, balance)); err != nil {
+				return fmt.Errorf("failed to write statement line: %w", err)
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			return fmt.Errorf("failed to write statement lines: %w", err)
+		}
+		flush()
+
+		if len(entries) < filter.Limit {
+			break
+		}
+		last := entries[len(entries)-1]
+		filter.After = &model.KeysetCursor{Timestamp: last.CreatedAt, ID: last.ID}
+	}
+
+	if !balance.Equal(a.Statement.ClosingBalance) {
+		// The lines and the closing balance come from the same snapshot, so this is a bug
+		a.service.logger.Error().Int64("account_id", a.Statement.AccountID).Str("running_balance", balance.String()).
+			Str("closing_balance", a.Statement.ClosingBalance.String()).Msg("statement lines do not add up to the closing balance")
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write statement footer: %w", err)
+	}
+	flush()
+
+	return nil
+}
+
+// Close ends the transaction the statement reads from
+func (a *AccountStatement) Close(ctx context.Context) {
+	if err := a.tx.Rollback(ctx); err != nil {
+		a.service.logger.Error().Err(err).Msg("failed to rollback transaction")
+	}
+}
+
+func toStatementLine(entry *model.StatementEntry, balance decimal.Decimal) *statement.Line {
+	direction := statement.Credit
+	if entry.Direction == model.LedgerDirectionDebit {
+		direction = statement.Debit
+	}
+
+	return &statement.Line{
+		EntryID:               entry.ID,
+		TransactionID:         entry.TransactionID,
+		PostedAt:              entry.CreatedAt,
+		Direction:             direction,
+		Amount:                entry.Amount,
+		Balance:               balance,
+		EntryType:             string(entry.EntryType),
+		CounterpartyAccountID: entry.CounterpartyAccountID(),
+		Reference:             entry.Reference,
+		Description:           entry.Description,
+	}
+}
+
+// parseStatementTime parses an end of a statement period. A date is midnight UTC at its start,
+// or at its end when it ends the period, so that a period of dates includes both of them.
+func parseStatementTime(value string, end bool) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		if end {
+			date = date.AddDate(0, 0, 1)
+		}
+		return date, nil
+	}
+
+	return time.Parse(time.RFC3339Nano, value)
+}
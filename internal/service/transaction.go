@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/chandra-shekhar/internal-transfers/internal/database"
 	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/event"
 	"github.com/chandra-shekhar/internal-transfers/internal/model"
 	"github.com/chandra-shekhar/internal-transfers/internal/repository"
 	"github.com/jackc/pgx/v5"
@@ -17,14 +20,26 @@ type TransactionService struct {
 	db              database.DB
 	accountRepo     repository.AccountRepository
 	transactionRepo repository.TransactionRepository
+	ledgerRepo      repository.LedgerRepository
+	fx              *FXService
+	velocity        *VelocityService
+	fees            *FeeService
+	outbox          *OutboxService
+	idempotency     *IdempotencyService
 	logger          *zerolog.Logger
 }
 
-func NewTransactionService(db database.DB, accountRepo repository.AccountRepository, transactionRepo repository.TransactionRepository, logger *zerolog.Logger) *TransactionService {
+func NewTransactionService(db database.DB, accountRepo repository.AccountRepository, transactionRepo repository.TransactionRepository, ledgerRepo repository.LedgerRepository, fx *FXService, velocity *VelocityService, fees *FeeService, outbox *OutboxService, idempotency *IdempotencyService, logger *zerolog.Logger) *TransactionService {
 	return &TransactionService{
 		db:              db,
 		accountRepo:     accountRepo,
 		transactionRepo: transactionRepo,
+		ledgerRepo:      ledgerRepo,
+		fx:              fx,
+		velocity:        velocity,
+		fees:            fees,
+		outbox:          outbox,
+		idempotency:     idempotency,
 		logger:          logger,
 	}
 }
@@ -36,6 +51,34 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, req *model.C
 		return nil, fmt.Errorf("invalid amount format: %w", err)
 	}
 
+	response, err := inIdempotentTx(ctx, s.db, s.idempotency, s.logger, idempotencyScopeCreateTransaction, req.IdempotencyKey, req.IdempotencyPayload(),
+		func(tx pgx.Tx) (*model.TransactionResponse, error) {
+			return s.transfer(ctx, tx, req, amount)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	message := "transaction completed successfully"
+	switch response.Status {
+	case model.TransactionStatusScheduled:
+		message = "transaction scheduled successfully"
+	case model.TransactionStatusPending:
+		message = "transaction queued successfully"
+	}
+
+	s.logger.Info().
+		Int64("transaction_id", response.ID).
+		Int64("source_account_id", req.SourceAccountID).
+		Int64("destination_account_id", req.DestinationAccountID).
+		Str("amount", amount.String()).
+		Msg(message)
+
+	return response, nil
+}
+
+// transfer validates the request and moves the funds inside tx
+func (s *TransactionService) transfer(ctx context.Context, tx pgx.Tx, req *model.CreateTransactionRequest, amount decimal.Decimal) (*model.TransactionResponse, error) {
 	// Validate amount
 	if amount.IsNegative() || amount.IsZero() {
 		return nil, errs.ErrAmountMustBePositive
@@ -46,8 +89,8 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, req *model.C
 		return nil, errs.ErrSameAccount
 	}
 
-	// Verify accounts exist before starting transaction
-	_, err = s.accountRepo.GetByID(ctx, req.SourceAccountID)
+	// Verify accounts exist before creating the transaction record
+	sourceAccount, err := s.accountRepo.GetByID(ctx, req.SourceAccountID)
 	if err != nil {
 		if err.Error() == "account not found" {
 			return nil, errs.ErrSourceAccountNotFound
@@ -55,7 +98,7 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, req *model.C
 		return nil, fmt.Errorf("failed to verify source account: %w", err)
 	}
 
-	_, err = s.accountRepo.GetByID(ctx, req.DestinationAccountID)
+	destinationAccount, err := s.accountRepo.GetByID(ctx, req.DestinationAccountID)
 	if err != nil {
 		if err.Error() == "account not found" {
 			return nil, errs.ErrDestinationAccountNotFound
@@ -63,140 +106,695 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, req *model.C
 		return nil, fmt.Errorf("failed to verify destination account: %w", err)
 	}
 
-	// Start a database transaction
-	tx, err := s.db.Begin(ctx)
-	if err != nil {
-		s.logger.Error().Err(err).Msg("failed to begin transaction")
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	if rejection := checkPrecision(sourceAccount.Currency, amount); rejection != nil {
+		return nil, rejection
 	}
 
-	committed := false
-	defer func() {
-		if !committed {
-			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
-				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
-			}
+	// Converting between currencies has to be asked for
+	fxRequested := req.Convert || req.FXQuoteID != nil
+	if !fxRequested {
+		if rejection := checkSameCurrency(sourceAccount, destinationAccount); rejection != nil {
+			return nil, rejection
 		}
-	}()
+	}
 
 	// Create transaction record
 	transaction := &model.Transaction{
-		SourceAccountID:      req.SourceAccountID,
-		DestinationAccountID: req.DestinationAccountID,
-		Amount:               amount,
-		Status:               model.TransactionStatusPending,
+		SourceAccountID:       req.SourceAccountID,
+		DestinationAccountID:  req.DestinationAccountID,
+		Amount:                amount,
+		Status:                model.TransactionStatusPending,
+		Kind:                  model.TransactionKindTransfer,
+		FXRequested:           fxRequested,
+		FXQuoteID:             req.FXQuoteID,
+		ExpectedSourceVersion: req.ExpectedSourceVersion,
+		Reference:             optionalString(req.Reference),
+		Description:           optionalString(req.Description),
+		ExternalID:            optionalString(req.ExternalID),
+		Metadata:              req.Metadata,
 	}
-	err = s.transactionRepo.Create(ctx, tx, transaction)
-	if err != nil {
+
+	// Future-dated transfers are only recorded here, the scheduler settles them when due
+	if req.ExecuteAt != nil && req.ExecuteAt.After(time.Now()) {
+		if req.FXQuoteID != nil {
+			return nil, errs.ErrFXQuoteMismatch.WithMessage("A quote cannot be used for a scheduled transfer")
+		}
+
+		// Versions change with every posting, so none can be expected of the future
+		if req.ExpectedSourceVersion != nil {
+			return nil, errs.ErrValidationError.WithMessage("expected_source_version cannot be used for a scheduled transfer")
+		}
+
+		transaction.Status = model.TransactionStatusScheduled
+		transaction.ExecuteAt = req.ExecuteAt
+
+		if err := s.createTransaction(ctx, tx, transaction); err != nil {
+			return nil, err
+		}
+
+		return toTransactionResponse(transaction), nil
+	}
+
+	// Asynchronous transfers are queued as pending, a worker settles them
+	if req.Async {
+		if err := s.createTransaction(ctx, tx, transaction); err != nil {
+			return nil, err
+		}
+
+		return toTransactionResponse(transaction), nil
+	}
+
+	if err := s.execute(ctx, tx, transaction); err != nil {
+		return nil, err
+	}
+
+	return toTransactionResponse(transaction), nil
+}
+
+// execute records the transaction and settles it inside tx. Every flow that moves money,
+// such as reversals and standing orders, goes through here.
+func (s *TransactionService) execute(ctx context.Context, tx pgx.Tx, transaction *model.Transaction) error {
+	if err := s.createTransaction(ctx, tx, transaction); err != nil {
+		return err
+	}
+
+	return s.settle(ctx, tx, transaction)
+}
+
+// createTransaction records the transaction, rejecting an external ID its source account already used
+func (s *TransactionService) createTransaction(ctx context.Context, tx pgx.Tx, transaction *model.Transaction) error {
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		if err.Error() == "external id already exists" {
+			return errs.WrapHTTPError(errs.ErrDuplicateExternalID, "account %d already made a transfer with external ID %s", transaction.SourceAccountID, *transaction.ExternalID)
+		}
 		s.logger.Error().Err(err).Msg("failed to create transaction record")
-		return nil, fmt.Errorf("failed to create transaction: %w", err)
+		return fmt.Errorf("failed to create transaction: %w", err)
 	}
 
-	// Lock accounts in consistent order (by ID) to prevent deadlocks
-	// Always lock the lower ID first, then the higher ID
-	var firstAccount, secondAccount *model.Account
-	var firstIsSource bool
+	return nil
+}
 
-	if req.SourceAccountID < req.DestinationAccountID {
-		firstIsSource = true
-		firstAccount, err = s.accountRepo.GetByIDForUpdate(ctx, tx, req.SourceAccountID)
+// settle locks both accounts, checks the source balance and posts the transaction.
+// A business rejection marks the transaction failed before the error is returned.
+func (s *TransactionService) settle(ctx context.Context, tx pgx.Tx, transaction *model.Transaction) error {
+	accountIDs := []int64{transaction.SourceAccountID, transaction.DestinationAccountID}
+
+	// A conversion also credits the FX P&L account, which has to be locked in the same ascending order
+	if transaction.FXRequested {
+		pnlAccountID, ok, err := s.fx.pnlAccountFor(ctx, transaction.DestinationAccountID)
 		if err != nil {
-			if err.Error() == "account not found" {
-				s.transactionRepo.UpdateStatus(ctx, tx, transaction.ID, model.TransactionStatusFailed)
-				return nil, errs.ErrSourceAccountNotFound
-			}
-			return nil, fmt.Errorf("failed to get source account: %w", err)
+			return err
+		}
+		if ok {
+			accountIDs = append(accountIDs, pnlAccountID)
 		}
+	}
 
-		secondAccount, err = s.accountRepo.GetByIDForUpdate(ctx, tx, req.DestinationAccountID)
+	// Fees are charged on transfers only. The revenue account is locked with the others.
+	var transactionFee *model.TransactionFee
+	if transaction.Kind == model.TransactionKindTransfer && !transaction.WaiveFees {
+		assessed, err := s.fees.assess(ctx, tx, transaction)
 		if err != nil {
-			if err.Error() == "account not found" {
-				s.transactionRepo.UpdateStatus(ctx, tx, transaction.ID, model.TransactionStatusFailed)
-				return nil, errs.ErrDestinationAccountNotFound
+			return err
+		}
+		transactionFee = assessed
+		if feeCharged(transactionFee) {
+			accountIDs = append(accountIDs, *transactionFee.RevenueAccountID)
+		}
+	}
+
+	locked, err := s.lockAccounts(ctx, tx, accountIDs...)
+	if err != nil {
+		return err
+	}
+
+	sourceAccount, ok := locked[transaction.SourceAccountID]
+	if !ok {
+		return s.fail(ctx, tx, transaction, errs.ErrSourceAccountNotFound)
+	}
+
+	destinationAccount, ok := locked[transaction.DestinationAccountID]
+	if !ok {
+		return s.fail(ctx, tx, transaction, errs.ErrDestinationAccountNotFound)
+	}
+
+	if rejection := checkVersion(sourceAccount, transaction.ExpectedSourceVersion); rejection != nil {
+		return s.fail(ctx, tx, transaction, rejection)
+	}
+
+	if rejection := checkStatus(sourceAccount, destinationAccount); rejection != nil {
+		return s.fail(ctx, tx, transaction, rejection)
+	}
+
+	if rejection := checkPrecision(sourceAccount.Currency, transaction.Amount); rejection != nil {
+		return s.fail(ctx, tx, transaction, rejection)
+	}
+
+	crossCurrency := sourceAccount.Currency != destinationAccount.Currency
+	if crossCurrency && !transaction.FXRequested {
+		return s.fail(ctx, tx, transaction, checkSameCurrency(sourceAccount, destinationAccount))
+	}
+	if !crossCurrency && transaction.FXQuoteID != nil {
+		return s.fail(ctx, tx, transaction, errs.ErrFXQuoteMismatch.WithMessage("Both accounts hold "+sourceAccount.Currency+", no conversion is needed"))
+	}
+
+	debit := transaction.Amount
+	if feeCharged(transactionFee) {
+		// The receiver of a conversion holds another currency, so the sender pays its fee
+		if crossCurrency {
+			transactionFee.Payer = model.FeePayerSender
+		}
+
+		if err := s.fees.checkRevenueAccount(transactionFee, locked, sourceAccount.Currency); err != nil {
+			return err
+		}
+
+		if transactionFee.Payer == model.FeePayerSender {
+			debit = debit.Add(transactionFee.Amount)
+		}
+	}
+
+	// The source may spend what holds do not reserve, down to its overdraft limit
+	if rejection := checkDebit(sourceAccount, debit); rejection != nil {
+		return s.fail(ctx, tx, transaction, rejection)
+	}
+
+	// A conversion checks the destination once the converted amount is known
+	if !crossCurrency {
+		if rejection := checkCredit(destinationAccount, transaction.Amount); rejection != nil {
+			return s.fail(ctx, tx, transaction, rejection)
+		}
+	}
+
+	// A receiver paying the fee pays it out of the credited amount and its own funds
+	if feeCharged(transactionFee) && transactionFee.Payer == model.FeePayerReceiver {
+		if !destinationAccount.AcceptsDebits() {
+			return s.fail(ctx, tx, transaction, errs.WrapHTTPError(errs.ErrDestinationAccountFrozen,
+				"destination account %d is frozen for debits and cannot pay the fee", destinationAccount.ID))
+		}
+		if destinationAccount.SpendableBalance().Add(transaction.Amount).LessThan(transactionFee.Amount) {
+			return s.fail(ctx, tx, transaction, errs.WrapHTTPError(errs.ErrInsufficientBalance,
+				"destination account %d cannot cover the fee of %s", destinationAccount.ID, transactionFee.Amount.String()))
+		}
+	}
+
+	// Velocity limits restrict what customers send, not reversals or sweeps
+	if transaction.Kind == model.TransactionKindTransfer {
+		if err := s.checkVelocity(ctx, tx, transaction, sourceAccount); err != nil {
+			return err
+		}
+	}
+
+	if crossCurrency {
+		if err := s.convert(ctx, tx, transaction, sourceAccount.Currency, destinationAccount.Currency, locked); err != nil {
+			if rejection, ok := errs.IsHTTPError(err); ok {
+				return s.fail(ctx, tx, transaction, rejection)
 			}
-			return nil, fmt.Errorf("failed to get destination account: %w", err)
+			return err
+		}
+	}
+
+	// Post the debit and credit legs, which move the balances
+	if err := s.processTransfer(ctx, tx, transaction); err != nil {
+		return err
+	}
+
+	if transactionFee != nil {
+		if err := s.chargeFee(ctx, tx, transaction, transactionFee); err != nil {
+			return err
+		}
+	}
+
+	return s.complete(ctx, tx, transaction)
+}
+
+// complete marks a settled transaction completed and records that it was
+func (s *TransactionService) complete(ctx context.Context, tx pgx.Tx, transaction *model.Transaction) error {
+	if err := s.transactionRepo.UpdateStatus(ctx, tx, transaction.ID, model.TransactionStatusCompleted); err != nil {
+		s.logger.Error().Err(err).Msg("failed to update transaction status")
+		return fmt.Errorf("failed to update transaction status: %w", err)
+	}
+	transaction.Status = model.TransactionStatusCompleted
+
+	return s.outbox.recordTransaction(ctx, tx, event.TypeTransactionCompleted, transaction)
+}
+
+// chargeFee posts the fee of a settled transfer as a child transaction from the payer to the
+// revenue account and records the fee on the transfer. A waived or zero fee is only recorded.
+func (s *TransactionService) chargeFee(ctx context.Context, tx pgx.Tx, transaction *model.Transaction, transactionFee *model.TransactionFee) error {
+	if feeCharged(transactionFee) {
+		payerAccountID := transaction.SourceAccountID
+		if transactionFee.Payer == model.FeePayerReceiver {
+			payerAccountID = transaction.DestinationAccountID
+		}
+
+		feeTransaction := &model.Transaction{
+			SourceAccountID:      payerAccountID,
+			DestinationAccountID: *transactionFee.RevenueAccountID,
+			Amount:               transactionFee.Amount,
+			Status:               model.TransactionStatusPending,
+			Kind:                 model.TransactionKindFee,
+			ParentTransactionID:  &transaction.ID,
+		}
+		if err := s.transactionRepo.Create(ctx, tx, feeTransaction); err != nil {
+			s.logger.Error().Err(err).Int64("transaction_id", transaction.ID).Msg("failed to create fee transaction")
+			return fmt.Errorf("failed to create fee transaction: %w", err)
+		}
+
+		if err := s.processTransfer(ctx, tx, feeTransaction); err != nil {
+			return err
+		}
+
+		if err := s.transactionRepo.UpdateStatus(ctx, tx, feeTransaction.ID, model.TransactionStatusCompleted); err != nil {
+			s.logger.Error().Err(err).Msg("failed to update fee transaction status")
+			return fmt.Errorf("failed to update fee transaction status: %w", err)
+		}
+		transactionFee.TransactionID = &feeTransaction.ID
+	}
+
+	if err := s.transactionRepo.RecordFee(ctx, tx, transaction.ID, transactionFee); err != nil {
+		s.logger.Error().Err(err).Int64("transaction_id", transaction.ID).Msg("failed to record fee")
+		return fmt.Errorf("failed to record fee: %w", err)
+	}
+	transaction.Fee = transactionFee
+
+	return nil
+}
+
+// checkVelocity locks the velocity limits of the source, after its account, and fails the
+// transaction if it breaches one of them
+func (s *TransactionService) checkVelocity(ctx context.Context, tx pgx.Tx, transaction *model.Transaction, sourceAccount *model.Account) error {
+	limits, err := s.velocity.lockLimits(ctx, tx, sourceAccount)
+	if err != nil {
+		return err
+	}
+
+	if err := s.velocity.check(ctx, tx, limits, sourceAccount, transaction.Amount, nil); err != nil {
+		if rejection, ok := errs.IsHTTPError(err); ok {
+			return s.fail(ctx, tx, transaction, rejection)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// convert prices a cross-currency transaction and locks the conversion into its row
+func (s *TransactionService) convert(ctx context.Context, tx pgx.Tx, transaction *model.Transaction, sourceCurrency, destinationCurrency string, locked map[int64]*model.Account) error {
+	// The P&L account must exist in the destination currency before a quote is used up
+	if pnlAccountID, ok := s.fx.pnlAccounts[destinationCurrency]; ok {
+		if pnlAccount, found := locked[pnlAccountID]; !found || pnlAccount.Currency != destinationCurrency {
+			s.logger.Error().Int64("account_id", pnlAccountID).Str("currency", destinationCurrency).Msg("fx pnl account is missing or holds another currency")
+			return s.fx.unavailable(sourceCurrency, destinationCurrency)
+		}
+	}
+
+	conversion, err := s.fx.conversionFor(ctx, tx, transaction, sourceCurrency, destinationCurrency)
+	if err != nil {
+		return err
+	}
+
+	if rejection := checkCredit(locked[transaction.DestinationAccountID], conversion.DestinationAmount); rejection != nil {
+		return rejection
+	}
+
+	// The quote is only used up by a conversion that goes through
+	if err := s.fx.useQuote(ctx, tx, transaction); err != nil {
+		return err
+	}
+
+	if err := s.transactionRepo.RecordConversion(ctx, tx, transaction.ID, conversion); err != nil {
+		s.logger.Error().Err(err).Int64("transaction_id", transaction.ID).Msg("failed to record conversion")
+		return fmt.Errorf("failed to record conversion: %w", err)
+	}
+	transaction.FX = conversion
+
+	return nil
+}
+
+// fail marks the transaction failed with the reason it was rejected and returns the rejection
+func (s *TransactionService) fail(ctx context.Context, tx pgx.Tx, transaction *model.Transaction, rejection *errs.HTTPError) error {
+	if err := s.transactionRepo.MarkFailed(ctx, tx, transaction.ID, rejection.Code, rejection.Message); err != nil {
+		s.logger.Error().Err(err).Int64("transaction_id", transaction.ID).Msg("failed to mark transaction failed")
+		return fmt.Errorf("failed to mark transaction failed: %w", err)
+	}
+
+	transaction.Status = model.TransactionStatusFailed
+	transaction.FailureCode = &rejection.Code
+	transaction.FailureReason = &rejection.Message
+
+	if err := s.outbox.recordTransaction(ctx, tx, event.TypeTransactionFailed, transaction); err != nil {
+		return err
+	}
+
+	return rejection
+}
+
+// lockAccounts locks the distinct accounts in ids once each, in ascending ID order, so any
+// two transfers touching overlapping accounts cannot deadlock. Missing accounts are absent
+// from the returned map.
+func (s *TransactionService) lockAccounts(ctx context.Context, tx pgx.Tx, ids ...int64) (map[int64]*model.Account, error) {
+	seen := make(map[int64]bool, len(ids))
+	distinct := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			distinct = append(distinct, id)
 		}
-	} else {
-		firstIsSource = false
-		firstAccount, err = s.accountRepo.GetByIDForUpdate(ctx, tx, req.DestinationAccountID)
+	}
+	sort.Slice(distinct, func(i, j int) bool { return distinct[i] < distinct[j] })
+
+	accounts, err := s.accountRepo.GetByIDsForUpdate(ctx, tx, distinct)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to lock accounts")
+		return nil, fmt.Errorf("failed to lock accounts: %w", err)
+	}
+
+	locked := make(map[int64]*model.Account, len(accounts))
+	for _, account := range accounts {
+		locked[account.ID] = account
+	}
+
+	return locked, nil
+}
+
+// CreateBatchTransaction makes every leg of the batch in one database transaction. Legs are
+// applied in order, so a leg may spend funds credited by an earlier one. If any leg is
+// rejected nothing is transferred and every failing leg is reported.
+func (s *TransactionService) CreateBatchTransaction(ctx context.Context, req *model.CreateBatchTransactionRequest) (*model.BatchTransactionResponse, error) {
+	response, err := inIdempotentTx(ctx, s.db, s.idempotency, s.logger, idempotencyScopeCreateBatch, req.IdempotencyKey, req,
+		func(tx pgx.Tx) (*model.BatchTransactionResponse, error) {
+			return s.transferBatch(ctx, tx, req)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().
+		Int("legs", len(response.Transactions)).
+		Msg("batch transaction completed successfully")
+
+	return response, nil
+}
+
+// transferBatch validates every leg against the locked balances before posting any of them
+func (s *TransactionService) transferBatch(ctx context.Context, tx pgx.Tx, req *model.CreateBatchTransactionRequest) (*model.BatchTransactionResponse, error) {
+	var fieldErrors []errs.FieldError
+	legError := func(leg int, field, message string) {
+		fieldErrors = append(fieldErrors, errs.FieldError{
+			Field: fmt.Sprintf("legs[%d].%s", leg, field),
+			Error: message,
+		})
+	}
+
+	amounts := make([]decimal.Decimal, len(req.Legs))
+	accountIDs := make([]int64, 0, 2*len(req.Legs))
+	for i, leg := range req.Legs {
+		amount, err := decimal.NewFromString(leg.Amount)
 		if err != nil {
-			if err.Error() == "account not found" {
-				s.transactionRepo.UpdateStatus(ctx, tx, transaction.ID, model.TransactionStatusFailed)
-				return nil, errs.ErrDestinationAccountNotFound
-			}
-			return nil, fmt.Errorf("failed to get destination account: %w", err)
+			legError(i, "amount", "Invalid amount format")
+			continue
+		}
+		if !amount.IsPositive() {
+			legError(i, "amount", errs.ErrAmountMustBePositive.Message)
+			continue
+		}
+		if leg.SourceAccountID == leg.DestinationAccountID {
+			legError(i, "destination_account_id", errs.ErrSameAccount.Message)
+			continue
 		}
+		amounts[i] = amount
+		accountIDs = append(accountIDs, leg.SourceAccountID, leg.DestinationAccountID)
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, errs.ErrBatchRejected.WithErrors(fieldErrors)
+	}
 
-		secondAccount, err = s.accountRepo.GetByIDForUpdate(ctx, tx, req.SourceAccountID)
+	// Every leg is a transfer and pays fees like one. Revenue accounts are locked with the others.
+	legFees := make([]*model.TransactionFee, len(req.Legs))
+	for i, leg := range req.Legs {
+		assessed, err := s.fees.assess(ctx, tx, &model.Transaction{
+			SourceAccountID:      leg.SourceAccountID,
+			DestinationAccountID: leg.DestinationAccountID,
+			Amount:               amounts[i],
+		})
 		if err != nil {
-			if err.Error() == "account not found" {
-				s.transactionRepo.UpdateStatus(ctx, tx, transaction.ID, model.TransactionStatusFailed)
-				return nil, errs.ErrSourceAccountNotFound
-			}
-			return nil, fmt.Errorf("failed to get source account: %w", err)
+			return nil, err
+		}
+		legFees[i] = assessed
+		if feeCharged(assessed) {
+			accountIDs = append(accountIDs, *assessed.RevenueAccountID)
 		}
 	}
 
-	// Assign source and destination based on the original request
-	var sourceAccount, destAccount *model.Account
-	if firstIsSource {
-		sourceAccount = firstAccount
-		destAccount = secondAccount
-	} else {
-		sourceAccount = secondAccount
-		destAccount = firstAccount
+	// Lock every account the batch touches once, before any leg is checked
+	locked, err := s.lockAccounts(ctx, tx, accountIDs...)
+	if err != nil {
+		return nil, err
 	}
 
-	// Check if source account has sufficient balance
-	if sourceAccount.Balance.LessThan(amount) {
-		s.transactionRepo.UpdateStatus(ctx, tx, transaction.ID, model.TransactionStatusFailed)
-		return nil, errs.ErrInsufficientBalance
+	// Walk the legs against running copies of the accounts so later legs see the effect of earlier ones
+	running := make(map[int64]*model.Account, len(locked))
+	for id, account := range locked {
+		copied := *account
+		running[id] = &copied
 	}
 
-	// Calculate new balances
-	newSourceBalance := sourceAccount.Balance.Sub(amount)
-	newDestBalance := destAccount.Balance.Add(amount)
+	sources := make([]*model.Account, 0, len(req.Legs))
+	for _, leg := range req.Legs {
+		if source, ok := locked[leg.SourceAccountID]; ok {
+			sources = append(sources, source)
+		}
+	}
 
-	// Update source account balance
-	if err := s.accountRepo.UpdateBalance(ctx, tx, req.SourceAccountID, newSourceBalance); err != nil {
-		s.logger.Error().Err(err).Msg("failed to update source account balance")
-		return nil, fmt.Errorf("failed to update source account balance: %w", err)
+	limits, err := s.velocity.lockLimits(ctx, tx, sources...)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update destination account balance
-	if err := s.accountRepo.UpdateBalance(ctx, tx, req.DestinationAccountID, newDestBalance); err != nil {
-		s.logger.Error().Err(err).Msg("failed to update destination account balance")
-		return nil, fmt.Errorf("failed to update destination account balance: %w", err)
+	// Earlier legs count against the velocity limits of later ones
+	var pending []pendingDebit
+
+	for i, leg := range req.Legs {
+		source, sourceFound := running[leg.SourceAccountID]
+		destination, destinationFound := running[leg.DestinationAccountID]
+		if !sourceFound {
+			legError(i, "source_account_id", errs.ErrSourceAccountNotFound.Message)
+		}
+		if !destinationFound {
+			legError(i, "destination_account_id", errs.ErrDestinationAccountNotFound.Message)
+		}
+		if !sourceFound || !destinationFound {
+			continue
+		}
+
+		if rejection := checkDebitStatus(source); rejection != nil {
+			legError(i, "source_account_id", rejection.Message)
+			continue
+		}
+
+		if rejection := checkCreditStatus(destination); rejection != nil {
+			legError(i, "destination_account_id", rejection.Message)
+			continue
+		}
+
+		if rejection := checkSameCurrency(source, destination); rejection != nil {
+			legError(i, "destination_account_id", rejection.Message)
+			continue
+		}
+
+		if rejection := checkPrecision(source.Currency, amounts[i]); rejection != nil {
+			legError(i, "amount", rejection.Message)
+			continue
+		}
+
+		debit, fee := amounts[i], legFees[i]
+		if feeCharged(fee) {
+			if err := s.fees.checkRevenueAccount(fee, running, source.Currency); err != nil {
+				return nil, err
+			}
+			if fee.Payer == model.FeePayerSender {
+				debit = debit.Add(fee.Amount)
+			}
+		}
+
+		if rejection := checkDebit(source, debit); rejection != nil {
+			legError(i, "amount", rejection.Message)
+			continue
+		}
+
+		if rejection := checkCredit(destination, amounts[i]); rejection != nil {
+			legError(i, "amount", rejection.Message)
+			continue
+		}
+
+		// A receiver paying the fee pays it out of the credited amount and its own funds
+		receiverPays := feeCharged(fee) && fee.Payer == model.FeePayerReceiver
+		if receiverPays {
+			if !destination.AcceptsDebits() {
+				legError(i, "destination_account_id", fmt.Sprintf("destination account %d is frozen for debits and cannot pay the fee", destination.ID))
+				continue
+			}
+			if destination.SpendableBalance().Add(amounts[i]).LessThan(fee.Amount) {
+				legError(i, "amount", fmt.Sprintf("destination account %d cannot cover the fee of %s", destination.ID, fee.Amount.String()))
+				continue
+			}
+		}
+
+		if err := s.velocity.check(ctx, tx, limits, source, amounts[i], pending); err != nil {
+			if rejection, ok := errs.IsHTTPError(err); ok {
+				legError(i, "amount", rejection.Message)
+				continue
+			}
+			return nil, err
+		}
+		pending = append(pending, pendingDebit{account: source, amount: amounts[i]})
+
+		source.Balance = source.Balance.Sub(debit)
+		destination.Balance = destination.Balance.Add(amounts[i])
+		if receiverPays {
+			destination.Balance = destination.Balance.Sub(fee.Amount)
+		}
+		if feeCharged(fee) {
+			revenue := running[*fee.RevenueAccountID]
+			revenue.Balance = revenue.Balance.Add(fee.Amount)
+		}
 	}
 
-	// Update transaction status to completed
-	if err := s.transactionRepo.UpdateStatus(ctx, tx, transaction.ID, model.TransactionStatusCompleted); err != nil {
-		s.logger.Error().Err(err).Msg("failed to update transaction status")
-		return nil, fmt.Errorf("failed to update transaction status: %w", err)
+	if len(fieldErrors) > 0 {
+		return nil, errs.ErrBatchRejected.WithErrors(fieldErrors)
 	}
 
-	// Commit the transaction
-	if err := tx.Commit(ctx); err != nil {
-		s.logger.Error().Err(err).Msg("failed to commit transaction")
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	response := &model.BatchTransactionResponse{
+		Transactions: make([]*model.TransactionResponse, 0, len(req.Legs)),
+	}
+	for i, leg := range req.Legs {
+		transaction := &model.Transaction{
+			SourceAccountID:      leg.SourceAccountID,
+			DestinationAccountID: leg.DestinationAccountID,
+			Amount:               amounts[i],
+			Status:               model.TransactionStatusPending,
+			Kind:                 model.TransactionKindTransfer,
+		}
+		if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+			s.logger.Error().Err(err).Int("leg", i).Msg("failed to create transaction record")
+			return nil, fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		if err := s.processTransfer(ctx, tx, transaction); err != nil {
+			return nil, err
+		}
+
+		if legFees[i] != nil {
+			if err := s.chargeFee(ctx, tx, transaction, legFees[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := s.complete(ctx, tx, transaction); err != nil {
+			return nil, err
+		}
+
+		response.Transactions = append(response.Transactions, toTransactionResponse(transaction))
+	}
+
+	return response, nil
+}
+
+// ReverseTransaction sends all or part of a completed transfer back to its source
+// as a new transaction linked to the original
+func (s *TransactionService) ReverseTransaction(ctx context.Context, req *model.CreateReversalRequest) (*model.TransactionResponse, error) {
+	var amount *decimal.Decimal
+	if req.Amount != "" {
+		parsed, err := decimal.NewFromString(req.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount format: %w", err)
+		}
+		amount = &parsed
+	}
+
+	response, err := inIdempotentTx(ctx, s.db, s.idempotency, s.logger, idempotencyScopeCreateReversal, req.IdempotencyKey, req.IdempotencyPayload(),
+		func(tx pgx.Tx) (*model.TransactionResponse, error) {
+			return s.reverse(ctx, tx, req, amount)
+		})
+	if err != nil {
+		return nil, err
 	}
-	committed = true
 
 	s.logger.Info().
-		Int64("transaction_id", transaction.ID).
-		Int64("source_account_id", req.SourceAccountID).
-		Int64("destination_account_id", req.DestinationAccountID).
-		Str("amount", amount.String()).
-		Msg("transaction completed successfully")
+		Int64("transaction_id", response.ID).
+		Int64("reversed_transaction_id", req.TransactionID).
+		Str("amount", response.Amount).
+		Msg("transaction reversed successfully")
 
-	return &model.TransactionResponse{
-		ID:                   transaction.ID,
-		SourceAccountID:      transaction.SourceAccountID,
-		DestinationAccountID: transaction.DestinationAccountID,
-		Amount:               transaction.Amount.String(),
-		Status:               model.TransactionStatusCompleted,
-		CreatedAt:            transaction.CreatedAt,
-	}, nil
+	return response, nil
+}
+
+// reverse locks the original transfer, checks how much of it is left to reverse and settles
+// the compensating transfer inside tx
+func (s *TransactionService) reverse(ctx context.Context, tx pgx.Tx, req *model.CreateReversalRequest, amount *decimal.Decimal) (*model.TransactionResponse, error) {
+	// Lock the original so concurrent reversals cannot exceed its amount
+	original, err := s.transactionRepo.GetByIDForUpdate(ctx, tx, req.TransactionID)
+	if err != nil {
+		if err.Error() == "transaction not found" {
+			return nil, errs.WrapHTTPError(errs.ErrTransactionNotFound, "transaction with ID %d not found", req.TransactionID)
+		}
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if original.Kind != model.TransactionKindTransfer {
+		return nil, errs.ErrTransactionNotReversible.WithMessage("Only transfers can be reversed")
+	}
+
+	if original.FX != nil {
+		return nil, errs.ErrTransactionNotReversible.WithMessage("Cross-currency transfers cannot be reversed")
+	}
+
+	if original.Status != model.TransactionStatusCompleted && original.Status != model.TransactionStatusPartiallyReversed {
+		return nil, errs.WrapHTTPError(errs.ErrTransactionNotReversible, "transaction with status %s cannot be reversed", original.Status)
+	}
+
+	remaining := original.Amount.Sub(original.ReversedAmount)
+	if amount == nil {
+		amount = &remaining
+	}
+
+	if !amount.IsPositive() {
+		return nil, errs.ErrAmountMustBePositive
+	}
+
+	if amount.GreaterThan(remaining) {
+		return nil, errs.WrapHTTPError(errs.ErrReversalExceedsAmount, "reversal amount exceeds the %s left to reverse", remaining.String())
+	}
+
+	reversal := &model.Transaction{
+		SourceAccountID:      original.DestinationAccountID,
+		DestinationAccountID: original.SourceAccountID,
+		Amount:               *amount,
+		Status:               model.TransactionStatusPending,
+		Kind:                 model.TransactionKindReversal,
+		ParentTransactionID:  &original.ID,
+	}
+	if req.Reason != "" {
+		reversal.Reason = &req.Reason
+	}
+
+	if err := s.execute(ctx, tx, reversal); err != nil {
+		return nil, err
+	}
+
+	reversed, err := s.transactionRepo.AddReversedAmount(ctx, tx, original.ID, *amount)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("transaction_id", original.ID).Msg("failed to update reversed amount")
+		return nil, fmt.Errorf("failed to update reversed amount: %w", err)
+	}
+
+	if err := s.outbox.recordTransaction(ctx, tx, event.TypeTransactionReversed, reversed); err != nil {
+		return nil, err
+	}
+
+	return toTransactionResponse(reversal), nil
 }
 
 // GetTransaction retrieves a transaction by its ID
@@ -204,25 +802,320 @@ func (s *TransactionService) GetTransaction(ctx context.Context, transactionID i
 	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
 	if err != nil {
 		if err.Error() == "transaction not found" {
-			return nil, fmt.Errorf("transaction with ID %d not found", transactionID)
+			return nil, errs.WrapHTTPError(errs.ErrTransactionNotFound, "transaction with ID %d not found", transactionID)
 		}
 		s.logger.Error().Err(err).Int64("transaction_id", transactionID).Msg("failed to get transaction")
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
 
-	return &model.TransactionResponse{
+	return toTransactionResponse(transaction), nil
+}
+
+// toTransactionResponse converts a transaction into its API representation
+func toTransactionResponse(transaction *model.Transaction) *model.TransactionResponse {
+	response := &model.TransactionResponse{
 		ID:                   transaction.ID,
 		SourceAccountID:      transaction.SourceAccountID,
 		DestinationAccountID: transaction.DestinationAccountID,
 		Amount:               transaction.Amount.String(),
+		Currency:             transaction.Currency,
 		Status:               transaction.Status,
+		Kind:                 transaction.Kind,
+		ParentTransactionID:  transaction.ParentTransactionID,
+		Reason:               transaction.Reason,
+		ExecuteAt:            transaction.ExecuteAt,
+		FailureCode:          transaction.FailureCode,
+		FailureReason:        transaction.FailureReason,
+		StandingOrderID:      transaction.StandingOrderID,
+		Reference:            transaction.Reference,
+		Description:          transaction.Description,
+		ExternalID:           transaction.ExternalID,
+		Metadata:             transaction.Metadata,
 		CreatedAt:            transaction.CreatedAt,
-	}, nil
+	}
+
+	if transaction.ReversedAmount.IsPositive() {
+		response.ReversedAmount = transaction.ReversedAmount.String()
+	}
+
+	if transaction.FX != nil {
+		response.FX = toFXConversionResponse(transaction.FXQuoteID, transaction.FX)
+	}
+
+	// Fees are charged in the currency of the source
+	if transaction.Fee != nil {
+		response.Fee = toTransactionFeeResponse(transaction.Fee, transaction.Currency)
+	}
+
+	return response
 }
 
-// processTransfer handles the actual money transfer logic within a transaction
-func (s *TransactionService) processTransfer(ctx context.Context, tx pgx.Tx, sourceID, destID int64, amount decimal.Decimal) error {
-	// This is a helper method that could be expanded with additional business logic
-	// For now, the logic is in CreateTransaction method
+// processTransfer posts the debit and credit legs of a transfer within a transaction.
+// Balances only change through ledger postings.
+func (s *TransactionService) processTransfer(ctx context.Context, tx pgx.Tx, transaction *model.Transaction) error {
+	entryType, credited := model.LedgerEntryTypeTransfer, transaction.Amount
+	switch transaction.Kind {
+	case model.TransactionKindFee:
+		entryType = model.LedgerEntryTypeFee
+	case model.TransactionKindInterest:
+		entryType = model.LedgerEntryTypeInterest
+	}
+	if transaction.FX != nil {
+		entryType, credited = model.LedgerEntryTypeFX, transaction.FX.DestinationAmount
+	}
+
+	debit := &model.LedgerEntry{
+		AccountID:     transaction.SourceAccountID,
+		TransactionID: &transaction.ID,
+		Direction:     model.LedgerDirectionDebit,
+		EntryType:     entryType,
+		Amount:        transaction.Amount,
+	}
+	if err := s.ledgerRepo.Post(ctx, tx, debit); err != nil {
+		s.logger.Error().Err(err).Msg("failed to post debit to source account")
+		return fmt.Errorf("failed to post debit to source account: %w", err)
+	}
+
+	credit := &model.LedgerEntry{
+		AccountID:     transaction.DestinationAccountID,
+		TransactionID: &transaction.ID,
+		Direction:     model.LedgerDirectionCredit,
+		EntryType:     entryType,
+		Amount:        credited,
+	}
+	if err := s.ledgerRepo.Post(ctx, tx, credit); err != nil {
+		s.logger.Error().Err(err).Msg("failed to post credit to destination account")
+		return fmt.Errorf("failed to post credit to destination account: %w", err)
+	}
+	posted := []*model.LedgerEntry{debit, credit}
+
+	// What rounding the converted amount down left over goes to the FX P&L account, once it
+	// adds up to a whole minor unit
+	if transaction.FX != nil && transaction.FX.Remainder.IsPositive() {
+		due, err := s.fx.accrueRemainder(ctx, tx, transaction.FX)
+		if err != nil {
+			return err
+		}
+		if due.IsPositive() {
+			remainder := &model.LedgerEntry{
+				AccountID:     transaction.FX.PnLAccountID,
+				TransactionID: &transaction.ID,
+				Direction:     model.LedgerDirectionCredit,
+				EntryType:     model.LedgerEntryTypeFX,
+				Amount:        due,
+			}
+			if err := s.ledgerRepo.Post(ctx, tx, remainder); err != nil {
+				s.logger.Error().Err(err).Msg("failed to post fx remainder")
+				return fmt.Errorf("failed to post fx remainder: %w", err)
+			}
+			posted = append(posted, remainder)
+		}
+	}
+
+	// Account streams on every instance pick the postings up once the transaction commits
+	if err := s.ledgerRepo.Notify(ctx, tx, posted...); err != nil {
+		s.logger.Error().Err(err).Msg("failed to notify ledger postings")
+		return err
+	}
+
 	return nil
 }
+
+// DefaultStatementLimit is the page size of an account statement when none is requested
+const DefaultStatementLimit = 20
+
+// ListAccountTransactions returns a page of an account statement, newest first
+func (s *TransactionService) ListAccountTransactions(ctx context.Context, req *model.ListAccountTransactionsRequest) (*model.CursorPaginatedResponse[model.AccountTransactionResponse], error) {
+	filter, err := buildAccountTransactionFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.accountRepo.GetByID(ctx, req.AccountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", req.AccountID)
+		}
+		return nil, fmt.Errorf("failed to verify account: %w", err)
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	limit := filter.Limit
+	filter.Limit = limit + 1
+
+	transactions, err := s.transactionRepo.ListByAccount(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("account_id", req.AccountID).Msg("failed to list account transactions")
+		return nil, fmt.Errorf("failed to list account transactions: %w", err)
+	}
+
+	response := &model.CursorPaginatedResponse[model.AccountTransactionResponse]{
+		Data:  make([]model.AccountTransactionResponse, 0, limit),
+		Limit: limit,
+	}
+
+	if len(transactions) > limit {
+		transactions = transactions[:limit]
+		last := transactions[limit-1]
+		response.HasMore = true
+		response.NextCursor = model.KeysetCursor{Timestamp: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	for _, transaction := range transactions {
+		response.Data = append(response.Data, toAccountTransactionResponse(req.AccountID, transaction))
+	}
+
+	return response, nil
+}
+
+// buildAccountTransactionFilter parses the statement query into a repository filter
+func buildAccountTransactionFilter(req *model.ListAccountTransactionsRequest) (*model.AccountTransactionFilter, error) {
+	filter := &model.AccountTransactionFilter{
+		AccountID: req.AccountID,
+		Limit:     req.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultStatementLimit
+	}
+
+	if req.Cursor != "" {
+		cursor, err := model.DecodeKeysetCursor(req.Cursor)
+		if err != nil {
+			return nil, errs.ErrInvalidFormat.WithMessage("Invalid cursor")
+		}
+		filter.After = cursor
+	}
+
+	if req.From != "" {
+		from, err := time.Parse(time.RFC3339Nano, req.From)
+		if err != nil {
+			return nil, errs.ErrInvalidFormat.WithMessage("from must be an RFC 3339 timestamp")
+		}
+		filter.From = &from
+	}
+
+	if req.To != "" {
+		to, err := time.Parse(time.RFC3339Nano, req.To)
+		if err != nil {
+			return nil, errs.ErrInvalidFormat.WithMessage("to must be an RFC 3339 timestamp")
+		}
+		filter.To = &to
+	}
+
+	if req.Direction != "" {
+		direction := model.TransactionDirection(req.Direction)
+		filter.Direction = &direction
+	}
+
+	if req.Status != "" {
+		status := model.TransactionStatus(req.Status)
+		filter.Status = &status
+	}
+
+	if req.MinAmount != "" {
+		minAmount, err := decimal.NewFromString(req.MinAmount)
+		if err != nil {
+			return nil, errs.ErrInvalidFormat.WithMessage("Invalid min_amount format")
+		}
+		filter.MinAmount = &minAmount
+	}
+
+	if req.MaxAmount != "" {
+		maxAmount, err := decimal.NewFromString(req.MaxAmount)
+		if err != nil {
+			return nil, errs.ErrInvalidFormat.WithMessage("Invalid max_amount format")
+		}
+		filter.MaxAmount = &maxAmount
+	}
+
+	return filter, nil
+}
+
+// SearchTransactions returns a page of the transactions matching the reference, external ID or
+// metadata their callers supplied, newest first
+func (s *TransactionService) SearchTransactions(ctx context.Context, req *model.SearchTransactionsRequest) (*model.CursorPaginatedResponse[model.TransactionResponse], error) {
+	if req.Reference == "" && req.ExternalID == "" && len(req.Metadata) == 0 {
+		return nil, errs.ErrInvalidFormat.WithMessage("At least one of reference, external_id or a metadata.<key> filter is required")
+	}
+
+	filter := &model.TransactionSearchFilter{
+		Reference:  req.Reference,
+		ExternalID: req.ExternalID,
+		AccountID:  req.AccountID,
+		Metadata:   req.Metadata,
+		Limit:      req.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultStatementLimit
+	}
+	if req.Cursor != "" {
+		cursor, err := model.DecodeKeysetCursor(req.Cursor)
+		if err != nil {
+			return nil, errs.ErrInvalidFormat.WithMessage("Invalid cursor")
+		}
+		filter.After = cursor
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	limit := filter.Limit
+	filter.Limit = limit + 1
+
+	transactions, err := s.transactionRepo.Search(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to search transactions")
+		return nil, fmt.Errorf("failed to search transactions: %w", err)
+	}
+
+	response := &model.CursorPaginatedResponse[model.TransactionResponse]{
+		Data:  make([]model.TransactionResponse, 0, limit),
+		Limit: limit,
+	}
+
+	if len(transactions) > limit {
+		transactions = transactions[:limit]
+		last := transactions[limit-1]
+		response.HasMore = true
+		response.NextCursor = model.KeysetCursor{Timestamp: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	for _, transaction := range transactions {
+		response.Data = append(response.Data, *toTransactionResponse(transaction))
+	}
+
+	return response, nil
+}
+
+// toAccountTransactionResponse presents a transaction from the point of view of accountID
+func toAccountTransactionResponse(accountID int64, transaction *model.AccountTransaction) model.AccountTransactionResponse {
+	response := model.AccountTransactionResponse{
+		ID:                    transaction.ID,
+		Direction:             model.TransactionDirectionIn,
+		CounterpartyAccountID: transaction.SourceAccountID,
+		Amount:                transaction.Amount.String(),
+		Currency:              transaction.Currency,
+		Status:                transaction.Status,
+		Kind:                  transaction.Kind,
+		ParentTransactionID:   transaction.ParentTransactionID,
+		Reference:             transaction.Reference,
+		Description:           transaction.Description,
+		ExternalID:            transaction.ExternalID,
+		CreatedAt:             transaction.CreatedAt,
+		CompletedAt:           transaction.CompletedAt,
+	}
+
+	if transaction.SourceAccountID == accountID {
+		response.Direction = model.TransactionDirectionOut
+		response.CounterpartyAccountID = transaction.DestinationAccountID
+	} else if transaction.FX != nil {
+		// The destination of a conversion received the converted amount
+		response.Amount = transaction.FX.DestinationAmount.String()
+		response.Currency = transaction.FX.DestinationCurrency
+	}
+
+	if transaction.RunningBalance != nil {
+		runningBalance := transaction.RunningBalance.String()
+		response.RunningBalance = &runningBalance
+	}
+
+	return response
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/currency"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/chandra-shekhar/internal-transfers/internal/velocity"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type VelocityService struct {
+	accountRepo       repository.AccountRepository
+	velocityLimitRepo repository.VelocityLimitRepository
+	logger            *zerolog.Logger
+}
+
+func NewVelocityService(accountRepo repository.AccountRepository, velocityLimitRepo repository.VelocityLimitRepository, logger *zerolog.Logger) *VelocityService {
+	return &VelocityService{
+		accountRepo:       accountRepo,
+		velocityLimitRepo: velocityLimitRepo,
+		logger:            logger,
+	}
+}
+
+// CreateLimit adds a velocity limit for an account or a group
+func (s *VelocityService) CreateLimit(ctx context.Context, req *model.CreateVelocityLimitRequest) (*model.VelocityLimitResponse, error) {
+	if (req.AccountID == nil) == (req.AccountGroup == nil) {
+		return nil, errs.ErrValidationError.WithMessage("Exactly one of account_id and account_group is required")
+	}
+
+	if (req.Kind == model.VelocityLimitSingleAmount) != (req.WindowSeconds == nil) {
+		return nil, errs.ErrValidationError.WithMessage("window_seconds is required for rolling limits and not allowed for single_amount")
+	}
+
+	maxValue, err := decimal.NewFromString(req.MaxValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount format: %w", err)
+	}
+
+	if !maxValue.IsPositive() {
+		return nil, errs.ErrValidationError.WithMessage("max_value must be positive")
+	}
+
+	if req.Kind == model.VelocityLimitOutgoingCount && !maxValue.IsInteger() {
+		return nil, errs.ErrValidationError.WithMessage("max_value of a count limit must be a whole number")
+	}
+
+	code := strings.ToUpper(req.Currency)
+	if req.AccountID != nil {
+		account, err := s.accountRepo.GetByID(ctx, *req.AccountID)
+		if err != nil {
+			if err.Error() == "account not found" {
+				return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", *req.AccountID)
+			}
+			return nil, fmt.Errorf("failed to get account: %w", err)
+		}
+
+		if code == "" {
+			code = account.Currency
+		}
+		if code != account.Currency {
+			return nil, errs.WrapHTTPError(errs.ErrCurrencyMismatch, "account %d holds %s, not %s", account.ID, account.Currency, code)
+		}
+	} else if code == "" {
+		return nil, errs.ErrValidationError.WithMessage("currency is required for group limits")
+	}
+
+	// A count limit has no amount, so only amount limits are held to the currency precision
+	if req.Kind == model.VelocityLimitOutgoingCount {
+		if _, ok := currency.Lookup(code); !ok {
+			return nil, errs.WrapHTTPError(errs.ErrUnsupportedCurrency, "currency %s is not supported", code)
+		}
+	} else if rejection := checkPrecision(code, maxValue); rejection != nil {
+		return nil, rejection
+	}
+
+	limit := &model.VelocityLimit{
+		AccountID:     req.AccountID,
+		AccountGroup:  req.AccountGroup,
+		Currency:      code,
+		Kind:          req.Kind,
+		WindowSeconds: req.WindowSeconds,
+		MaxValue:      maxValue,
+	}
+	if err := s.velocityLimitRepo.Create(ctx, limit); err != nil {
+		s.logger.Error().Err(err).Msg("failed to create velocity limit")
+		return nil, fmt.Errorf("failed to create velocity limit: %w", err)
+	}
+
+	s.logger.Info().
+		Int64("velocity_limit_id", limit.ID).
+		Str("limit", limit.String()).
+		Msg("velocity limit created")
+
+	return toVelocityLimitResponse(limit), nil
+}
+
+// ListLimits returns the velocity limits matching the filter
+func (s *VelocityService) ListLimits(ctx context.Context, req *model.ListVelocityLimitsRequest) (*model.ListResponse[*model.VelocityLimitResponse], error) {
+	limits, err := s.velocityLimitRepo.List(ctx, req)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list velocity limits")
+		return nil, fmt.Errorf("failed to list velocity limits: %w", err)
+	}
+
+	responses := make([]*model.VelocityLimitResponse, 0, len(limits))
+	for _, limit := range limits {
+		responses = append(responses, toVelocityLimitResponse(limit))
+	}
+
+	return &model.ListResponse[*model.VelocityLimitResponse]{Data: responses}, nil
+}
+
+// DeleteLimit removes a velocity limit
+func (s *VelocityService) DeleteLimit(ctx context.Context, limitID int64) error {
+	if err := s.velocityLimitRepo.Delete(ctx, limitID); err != nil {
+		if err.Error() == "velocity limit not found" {
+			return errs.WrapHTTPError(errs.ErrVelocityLimitNotFound, "velocity limit with ID %d not found", limitID)
+		}
+		s.logger.Error().Err(err).Int64("velocity_limit_id", limitID).Msg("failed to delete velocity limit")
+		return fmt.Errorf("failed to delete velocity limit: %w", err)
+	}
+
+	s.logger.Info().Int64("velocity_limit_id", limitID).Msg("velocity limit deleted")
+
+	return nil
+}
+
+// UpdateAccountGroup moves an account into a group, or out of its group
+func (s *VelocityService) UpdateAccountGroup(ctx context.Context, req *model.UpdateAccountGroupRequest) (*model.AccountResponse, error) {
+	if req.ExpectedVersion != nil {
+		account, err := s.accountRepo.GetByID(ctx, req.AccountID)
+		if err != nil {
+			if err.Error() == "account not found" {
+				return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", req.AccountID)
+			}
+			return nil, fmt.Errorf("failed to get account: %w", err)
+		}
+
+		if rejection := checkVersion(account, req.ExpectedVersion); rejection != nil {
+			return nil, rejection
+		}
+	}
+
+	account, err := s.accountRepo.UpdateGroup(ctx, req.AccountID, req.AccountGroup, req.ExpectedVersion)
+	if err != nil {
+		switch err.Error() {
+		case "account not found":
+			return nil, errs.WrapHTTPError(errs.ErrAccountNotFound, "account with ID %d not found", req.AccountID)
+		case "account not found or version changed":
+			// The account existed a moment ago, so another update got in first
+			return nil, errs.WrapHTTPError(errs.ErrAccountVersionMismatch, "account %d changed while it was updated", req.AccountID)
+		}
+		s.logger.Error().Err(err).Int64("account_id", req.AccountID).Msg("failed to update account group")
+		return nil, fmt.Errorf("failed to update account group: %w", err)
+	}
+
+	return toAccountResponse(account), nil
+}
+
+// pendingDebit is a transfer made earlier in the same database transaction, which the
+// transactions table does not show as completed yet
+type pendingDebit struct {
+	account *model.Account
+	amount  decimal.Decimal
+}
+
+// lockLimits locks every limit that applies to transfers from the given accounts. The
+// accounts must already be locked, so limits are always locked after accounts.
+func (s *VelocityService) lockLimits(ctx context.Context, tx pgx.Tx, sources ...*model.Account) ([]*model.VelocityLimit, error) {
+	accountIDs := make([]int64, 0, len(sources))
+	groups := make([]string, 0, len(sources))
+	for _, account := range sources {
+		accountIDs = append(accountIDs, account.ID)
+		if account.AccountGroup != nil {
+			groups = append(groups, *account.AccountGroup)
+		}
+	}
+
+	limits, err := s.velocityLimitRepo.LockApplicable(ctx, tx, accountIDs, groups)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to lock velocity limits")
+		return nil, fmt.Errorf("failed to lock velocity limits: %w", err)
+	}
+
+	return limits, nil
+}
+
+// check rejects a transfer of amount from source that breaches any of the locked limits
+func (s *VelocityService) check(ctx context.Context, tx pgx.Tx, limits []*model.VelocityLimit, source *model.Account, amount decimal.Decimal, pending []pendingDebit) error {
+	now := time.Now()
+	for _, limit := range limits {
+		if !limit.AppliesTo(source) {
+			continue
+		}
+
+		var result velocity.Result
+		switch limit.Kind {
+		case model.VelocityLimitSingleAmount:
+			result = velocity.Result{Allowed: amount.LessThanOrEqual(limit.MaxValue)}
+		case model.VelocityLimitOutgoingAmount, model.VelocityLimitOutgoingCount:
+			history, err := s.history(ctx, tx, limit, now, pending)
+			if err != nil {
+				return err
+			}
+
+			if limit.Kind == model.VelocityLimitOutgoingAmount {
+				result = velocity.CheckAmount(history, amount, limit.MaxValue, limit.Window(), now)
+			} else {
+				result = velocity.CheckCount(history, limit.MaxValue.IntPart(), limit.Window(), now)
+			}
+		default:
+			s.logger.Warn().Int64("velocity_limit_id", limit.ID).Str("kind", string(limit.Kind)).Msg("unknown velocity limit kind")
+			continue
+		}
+
+		if !result.Allowed {
+			return limitExceeded(limit, result.ResetsAt)
+		}
+	}
+
+	return nil
+}
+
+// history returns the outgoing transfers counted against a rolling limit, including the
+// pending ones it covers
+func (s *VelocityService) history(ctx context.Context, tx pgx.Tx, limit *model.VelocityLimit, now time.Time, pending []pendingDebit) ([]velocity.Movement, error) {
+	since := now.Add(-limit.Window())
+
+	var history []velocity.Movement
+	var err error
+	if limit.AccountID != nil {
+		history, err = s.velocityLimitRepo.ListOutgoingByAccount(ctx, tx, *limit.AccountID, limit.Currency, since)
+	} else {
+		history, err = s.velocityLimitRepo.ListOutgoingByGroup(ctx, tx, *limit.AccountGroup, limit.Currency, since)
+	}
+	if err != nil {
+		s.logger.Error().Err(err).Int64("velocity_limit_id", limit.ID).Msg("failed to list outgoing transfers")
+		return nil, fmt.Errorf("failed to list outgoing transfers: %w", err)
+	}
+
+	for _, debit := range pending {
+		if limit.AppliesTo(debit.account) {
+			history = append(history, velocity.Movement{Amount: debit.amount, At: now})
+		}
+	}
+
+	return history, nil
+}
+
+// limitExceeded names the breached limit and when the transfer would fit again
+func limitExceeded(limit *model.VelocityLimit, resetsAt *time.Time) *errs.HTTPError {
+	var message strings.Builder
+	message.WriteString(limit.String() + " exceeded")
+	if resetsAt != nil {
+		// Round up so the transfer fits at the reported second
+		reset := resetsAt.UTC().Truncate(time.Second)
+		if reset.Before(*resetsAt) {
+			reset = reset.Add(time.Second)
+		}
+		message.WriteString(", resets at " + reset.Format(time.RFC3339))
+	} else {
+		message.WriteString(", the amount alone is above the limit")
+	}
+
+	return errs.ErrLimitExceeded.WithMessage(message.String())
+}
+
+// toVelocityLimitResponse converts a velocity limit into its API representation
+func toVelocityLimitResponse(limit *model.VelocityLimit) *model.VelocityLimitResponse {
+	return &model.VelocityLimitResponse{
+		ID:            limit.ID,
+		AccountID:     limit.AccountID,
+		AccountGroup:  limit.AccountGroup,
+		Currency:      limit.Currency,
+		Kind:          limit.Kind,
+		WindowSeconds: limit.WindowSeconds,
+		MaxValue:      limit.MaxValue.String(),
+		CreatedAt:     limit.CreatedAt,
+	}
+}
@@ -0,0 +1,485 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/database"
+	"github.com/chandra-shekhar/internal-transfers/internal/errs"
+	"github.com/chandra-shekhar/internal-transfers/internal/event"
+	"github.com/chandra-shekhar/internal-transfers/internal/model"
+	"github.com/chandra-shekhar/internal-transfers/internal/repository"
+	"github.com/chandra-shekhar/internal-transfers/internal/webhook"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+// DefaultDeadLetterListLimit is the page size of the dead letter list when none is requested
+const DefaultDeadLetterListLimit = 20
+
+type WebhookService struct {
+	db          database.DB
+	webhookRepo repository.WebhookRepository
+	sender      *webhook.Sender
+	retry       webhook.RetryPolicy
+	// lease is how long a claimed delivery is hidden from other workers while it is sent
+	lease time.Duration
+	// rotationGrace is how long a rotated secret stays valid when the request does not say
+	rotationGrace time.Duration
+	logger        *zerolog.Logger
+}
+
+func NewWebhookService(db database.DB, webhookRepo repository.WebhookRepository, sender *webhook.Sender, retry webhook.RetryPolicy, lease, rotationGrace time.Duration, logger *zerolog.Logger) *WebhookService {
+	return &WebhookService{
+		db:            db,
+		webhookRepo:   webhookRepo,
+		sender:        sender,
+		retry:         retry,
+		lease:         lease,
+		rotationGrace: rotationGrace,
+		logger:        logger,
+	}
+}
+
+// CreateWebhook subscribes an endpoint to event types. The response carries the signing
+// secret, which is not returned again.
+func (s *WebhookService) CreateWebhook(ctx context.Context, req *model.CreateWebhookRequest) (*model.WebhookResponse, error) {
+	eventTypes, err := checkEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	hook := &model.Webhook{
+		URL:         req.URL,
+		EventTypes:  eventTypes,
+		Description: optionalString(req.Description),
+		Active:      true,
+		Secret:      secret,
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+
+	if err := s.webhookRepo.Create(ctx, hook); err != nil {
+		s.logger.Error().Err(err).Msg("failed to create webhook")
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	s.logger.Info().
+		Int64("webhook_id", hook.ID).
+		Strs("event_types", hook.EventTypes).
+		Msg("webhook created")
+
+	response := toWebhookResponse(hook)
+	response.Secret = hook.Secret
+	return response, nil
+}
+
+// ListWebhooks returns every webhook
+func (s *WebhookService) ListWebhooks(ctx context.Context) (*model.ListResponse[*model.WebhookResponse], error) {
+	webhooks, err := s.webhookRepo.List(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to list webhooks")
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	responses := make([]*model.WebhookResponse, 0, len(webhooks))
+	for _, hook := range webhooks {
+		responses = append(responses, toWebhookResponse(hook))
+	}
+
+	return &model.ListResponse[*model.WebhookResponse]{Data: responses}, nil
+}
+
+// GetWebhook retrieves a webhook by its ID
+func (s *WebhookService) GetWebhook(ctx context.Context, webhookID int64) (*model.WebhookResponse, error) {
+	hook, err := s.getWebhook(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toWebhookResponse(hook), nil
+}
+
+// UpdateWebhook changes the URL, event types, description or active flag of a webhook
+func (s *WebhookService) UpdateWebhook(ctx context.Context, req *model.UpdateWebhookRequest) (*model.WebhookResponse, error) {
+	hook, err := s.getWebhook(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		hook.URL = *req.URL
+	}
+	if req.EventTypes != nil {
+		eventTypes, err := checkEventTypes(req.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		hook.EventTypes = eventTypes
+	}
+	if req.Description != nil {
+		hook.Description = optionalString(*req.Description)
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+
+	if err := s.webhookRepo.Update(ctx, hook); err != nil {
+		if err.Error() == "webhook not found" {
+			return nil, errs.WrapHTTPError(errs.ErrWebhookNotFound, "webhook with ID %d not found", req.ID)
+		}
+		s.logger.Error().Err(err).Int64("webhook_id", req.ID).Msg("failed to update webhook")
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	s.logger.Info().Int64("webhook_id", hook.ID).Msg("webhook updated")
+
+	return toWebhookResponse(hook), nil
+}
+
+// DeleteWebhook removes a webhook; deliveries that have not been sent are dropped
+func (s *WebhookService) DeleteWebhook(ctx context.Context, webhookID int64) error {
+	if err := s.webhookRepo.Delete(ctx, webhookID); err != nil {
+		if err.Error() == "webhook not found" {
+			return errs.WrapHTTPError(errs.ErrWebhookNotFound, "webhook with ID %d not found", webhookID)
+		}
+		s.logger.Error().Err(err).Int64("webhook_id", webhookID).Msg("failed to delete webhook")
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	s.logger.Info().Int64("webhook_id", webhookID).Msg("webhook deleted")
+
+	return nil
+}
+
+// RotateSecret gives a webhook a new secret. Deliveries are signed with both secrets until
+// the grace period ends, so receivers can switch over without dropping any.
+func (s *WebhookService) RotateSecret(ctx context.Context, req *model.RotateWebhookSecretRequest) (*model.WebhookResponse, error) {
+	grace := s.rotationGrace
+	if req.GracePeriodSeconds != nil {
+		grace = time.Duration(*req.GracePeriodSeconds) * time.Second
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	hook, err := s.webhookRepo.RotateSecret(ctx, req.ID, secret, time.Now().Add(grace))
+	if err != nil {
+		if err.Error() == "webhook not found" {
+			return nil, errs.WrapHTTPError(errs.ErrWebhookNotFound, "webhook with ID %d not found", req.ID)
+		}
+		s.logger.Error().Err(err).Int64("webhook_id", req.ID).Msg("failed to rotate webhook secret")
+		return nil, fmt.Errorf("failed to rotate webhook secret: %w", err)
+	}
+
+	s.logger.Info().
+		Int64("webhook_id", hook.ID).
+		Dur("grace_period", grace).
+		Msg("webhook secret rotated")
+
+	response := toWebhookResponse(hook)
+	response.Secret = hook.Secret
+	return response, nil
+}
+
+// ListDeadLetters returns a page of the deliveries to a webhook that failed every attempt,
+// newest first
+func (s *WebhookService) ListDeadLetters(ctx context.Context, req *model.ListWebhookDeadLettersRequest) (*model.CursorPaginatedResponse[model.WebhookDeadLetterResponse], error) {
+	if _, err := s.getWebhook(ctx, req.WebhookID); err != nil {
+		return nil, err
+	}
+
+	filter := &model.WebhookDeadLetterFilter{
+		WebhookID: req.WebhookID,
+		Limit:     req.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultDeadLetterListLimit
+	}
+	if req.Cursor != "" {
+		cursor, err := model.DecodeKeysetCursor(req.Cursor)
+		if err != nil {
+			return nil, errs.ErrInvalidFormat.WithMessage("Invalid cursor")
+		}
+		filter.Before = cursor
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	limit := filter.Limit
+	filter.Limit = limit + 1
+
+	deadLetters, err := s.webhookRepo.ListDeadLetters(ctx, filter)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("webhook_id", req.WebhookID).Msg("failed to list webhook dead letters")
+		return nil, fmt.Errorf("failed to list webhook dead letters: %w", err)
+	}
+
+	response := &model.CursorPaginatedResponse[model.WebhookDeadLetterResponse]{
+		Data:  make([]model.WebhookDeadLetterResponse, 0, limit),
+		Limit: limit,
+	}
+
+	if len(deadLetters) > limit {
+		deadLetters = deadLetters[:limit]
+		last := deadLetters[limit-1]
+		response.HasMore = true
+		response.NextCursor = model.KeysetCursor{Timestamp: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	for _, deadLetter := range deadLetters {
+		response.Data = append(response.Data, *toWebhookDeadLetterResponse(deadLetter))
+	}
+
+	return response, nil
+}
+
+// ReplayDeadLetter queues a dead letter for delivery again with a fresh set of attempts
+func (s *WebhookService) ReplayDeadLetter(ctx context.Context, webhookID, deadLetterID int64) (*model.WebhookDeliveryResponse, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to begin transaction")
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+				s.logger.Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	deadLetter, err := s.webhookRepo.GetDeadLetterForUpdate(ctx, tx, webhookID, deadLetterID)
+	if err != nil {
+		if err.Error() == "webhook dead letter not found" {
+			return nil, errs.WrapHTTPError(errs.ErrWebhookDeadLetterNotFound, "dead letter with ID %d not found for webhook %d", deadLetterID, webhookID)
+		}
+		return nil, fmt.Errorf("failed to get webhook dead letter: %w", err)
+	}
+
+	if deadLetter.ReplayedAt != nil {
+		return nil, errs.WrapHTTPError(errs.ErrDeadLetterAlreadyReplayed, "dead letter %d was replayed at %s", deadLetter.ID, deadLetter.ReplayedAt.Format(time.RFC3339))
+	}
+
+	delivery, err := s.webhookRepo.Replay(ctx, tx, deadLetter)
+	if err != nil {
+		s.logger.Error().Err(err).Int64("dead_letter_id", deadLetterID).Msg("failed to replay webhook dead letter")
+		return nil, fmt.Errorf("failed to replay webhook dead letter: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("failed to commit transaction")
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	s.logger.Info().
+		Int64("webhook_id", webhookID).
+		Int64("dead_letter_id", deadLetterID).
+		Int64("delivery_id", delivery.ID).
+		Msg("webhook dead letter replayed")
+
+	return toWebhookDeliveryResponse(delivery), nil
+}
+
+// enqueue queues a published event for every webhook subscribed to its type, inside the
+// relay transaction that marks the event published
+func (s *WebhookService) enqueue(ctx context.Context, tx pgx.Tx, e *event.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %d: %w", e.ID, err)
+	}
+
+	if _, err := s.webhookRepo.Enqueue(ctx, tx, e.ID, string(e.Type), payload); err != nil {
+		s.logger.Error().Err(err).Int64("event_id", e.ID).Msg("failed to enqueue webhook deliveries")
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// DeliverDue sends up to limit due deliveries concurrently and returns how many it attempted.
+// Failed attempts are retried with exponential backoff until the retry policy gives up, and
+// the delivery is moved to the dead letters.
+func (s *WebhookService) DeliverDue(ctx context.Context, limit int) (int, error) {
+	targets, err := s.webhookRepo.ClaimDue(ctx, limit, time.Now().Add(s.lease))
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to claim webhook deliveries")
+		return 0, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target *model.WebhookTarget) {
+			defer wg.Done()
+			if err := s.deliver(ctx, target); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(target)
+	}
+	wg.Wait()
+
+	return len(targets), firstErr
+}
+
+// deliver makes one attempt at a claimed delivery and records its outcome
+func (s *WebhookService) deliver(ctx context.Context, target *model.WebhookTarget) error {
+	delivery := target.Delivery
+	logger := s.logger.With().
+		Int64("webhook_id", delivery.WebhookID).
+		Int64("delivery_id", delivery.ID).
+		Int64("event_id", delivery.EventID).
+		Logger()
+
+	statusCode, sendErr := s.sender.Send(ctx, &webhook.Delivery{
+		URL:       target.URL,
+		EventID:   delivery.EventID,
+		EventType: delivery.EventType,
+		Payload:   delivery.Payload,
+		Secrets:   target.Secrets,
+	})
+
+	// An attempt cut short by shutdown is not counted; the lease hands it to the next run
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	if sendErr == nil {
+		if err := s.webhookRepo.MarkDelivered(ctx, delivery.ID, statusCode); err != nil {
+			logger.Error().Err(err).Msg("failed to mark webhook delivery delivered")
+			return fmt.Errorf("failed to mark webhook delivery delivered: %w", err)
+		}
+		logger.Debug().Int("status_code", statusCode).Msg("webhook delivered")
+		return nil
+	}
+
+	var lastStatusCode *int
+	if statusCode != 0 {
+		lastStatusCode = &statusCode
+	}
+
+	attempt := delivery.Attempts + 1
+	if s.retry.Exhausted(attempt) {
+		deadLetter, err := s.webhookRepo.DeadLetter(ctx, delivery.ID, lastStatusCode, sendErr.Error())
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to dead-letter webhook delivery")
+			return fmt.Errorf("failed to dead-letter webhook delivery: %w", err)
+		}
+		logger.Warn().Err(sendErr).Int("attempts", attempt).Int64("dead_letter_id", deadLetter.ID).Msg("webhook delivery dead-lettered")
+		return nil
+	}
+
+	delay := s.retry.Delay(attempt)
+	if err := s.webhookRepo.ScheduleRetry(ctx, delivery.ID, lastStatusCode, sendErr.Error(), time.Now().Add(delay)); err != nil {
+		logger.Error().Err(err).Msg("failed to schedule webhook delivery retry")
+		return fmt.Errorf("failed to schedule webhook delivery retry: %w", err)
+	}
+	logger.Info().Err(sendErr).Int("attempt", attempt).Dur("retry_in", delay).Msg("webhook delivery failed")
+
+	return nil
+}
+
+// getWebhook loads a webhook, mapping a missing one to a not found error
+func (s *WebhookService) getWebhook(ctx context.Context, webhookID int64) (*model.Webhook, error) {
+	hook, err := s.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		if err.Error() == "webhook not found" {
+			return nil, errs.WrapHTTPError(errs.ErrWebhookNotFound, "webhook with ID %d not found", webhookID)
+		}
+		s.logger.Error().Err(err).Int64("webhook_id", webhookID).Msg("failed to get webhook")
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return hook, nil
+}
+
+// checkEventTypes rejects unknown event types and drops duplicates, keeping the first of each
+func checkEventTypes(eventTypes []string) ([]string, error) {
+	seen := make(map[string]bool, len(eventTypes))
+	distinct := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !event.Type(eventType).Valid() {
+			return nil, errs.ErrValidationError.WithMessage(fmt.Sprintf("Unknown event type %s", eventType))
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			distinct = append(distinct, eventType)
+		}
+	}
+
+	return distinct, nil
+}
+
+// toWebhookResponse converts a webhook into its API representation, without its secret
+func toWebhookResponse(hook *model.Webhook) *model.WebhookResponse {
+	response := &model.WebhookResponse{
+		ID:          hook.ID,
+		URL:         hook.URL,
+		EventTypes:  hook.EventTypes,
+		Description: hook.Description,
+		Active:      hook.Active,
+		CreatedAt:   hook.CreatedAt,
+		UpdatedAt:   hook.UpdatedAt,
+	}
+
+	// The previous secret is only worth mentioning while it still signs deliveries
+	if hook.PreviousSecretExpiresAt != nil && hook.PreviousSecretExpiresAt.After(time.Now()) {
+		response.PreviousSecretExpiresAt = hook.PreviousSecretExpiresAt
+	}
+
+	return response
+}
+
+// toWebhookDeliveryResponse converts a delivery into its API representation
+func toWebhookDeliveryResponse(delivery *model.WebhookDelivery) *model.WebhookDeliveryResponse {
+	return &model.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+}
+
+// toWebhookDeadLetterResponse converts a dead letter into its API representation
+func toWebhookDeadLetterResponse(deadLetter *model.WebhookDeadLetter) *model.WebhookDeadLetterResponse {
+	return &model.WebhookDeadLetterResponse{
+		ID:             deadLetter.ID,
+		WebhookID:      deadLetter.WebhookID,
+		EventID:        deadLetter.EventID,
+		EventType:      deadLetter.EventType,
+		Payload:        deadLetter.Payload,
+		Attempts:       deadLetter.Attempts,
+		LastStatusCode: deadLetter.LastStatusCode,
+		LastError:      deadLetter.LastError,
+		CreatedAt:      deadLetter.CreatedAt,
+		ReplayedAt:     deadLetter.ReplayedAt,
+	}
+}
@@ -0,0 +1,148 @@
+package statement
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// beancountOpeningAccount is the equity account the opening balance is brought in from
+const beancountOpeningAccount = "Equity:Internal:Opening"
+
+// beancountWriter writes a Beancount ledger: one transaction per line, each with its running
+// balance as metadata, between the opening balance and a balance assertion for the closing
+// balance. Every account is opened the day before the period, the counterparties just before
+// the first transaction that uses them.
+type beancountWriter struct {
+	*textWriter
+	s        *Statement
+	account  string
+	openDate string
+	opened   map[string]bool
+}
+
+func newBeancountWriter(w io.Writer, s *Statement) (*beancountWriter, error) {
+	b := &beancountWriter{
+		textWriter: newTextWriter(w),
+		s:          s,
+		account:    beancountAccount(s.AccountID),
+		openDate:   beancountDate(s.From.UTC().AddDate(0, 0, -1)),
+		opened:     make(map[string]bool),
+	}
+
+	b.printf("; Statement %s\n", s.ID())
+	b.printf("; Account %d from %s to %s, generated at %s\n\n", s.AccountID,
+		s.From.UTC().Format(time.RFC3339), s.To.UTC().Format(time.RFC3339), s.GeneratedAt.UTC().Format(time.RFC3339))
+	b.printf("option \"operating_currency\" \"%s\"\n\n", beancountString(s.Currency))
+	b.printf("%s open %s %s\n", b.openDate, b.account, s.Currency)
+	b.printf("%s open %s\n\n", b.openDate, beancountOpeningAccount)
+	b.opened[b.account] = true
+	b.opened[beancountOpeningAccount] = true
+
+	opening := s.OpeningBalance.StringFixed(s.Precision)
+	if isMidnight(s.From) {
+		// A balance assertion applies at the start of its day, so the pad that brings in the
+		// opening balance goes on the day before. Beancount rejects a pad with nothing to add.
+		if !s.OpeningBalance.IsZero() {
+			b.printf("%s pad %s %s\n", b.openDate, b.account, beancountOpeningAccount)
+		}
+		b.printf("%s balance %s %s %s\n\n", beancountDate(s.From), b.account, opening, s.Currency)
+	} else {
+		// The opening balance cannot be asserted in the middle of a day, so it is posted as a
+		// transaction on the first day of the period instead
+		b.printf("%s * \"Internal\" \"Opening balance\"\n", beancountDate(s.From))
+		b.printf("  posted_at: \"%s\"\n", s.From.UTC().Format(time.RFC3339Nano))
+		b.printf("  %s  %s %s\n", b.account, opening, s.Currency)
+		b.printf("  %s\n\n", beancountOpeningAccount)
+	}
+
+	if err := b.Flush(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *beancountWriter) WriteLine(line *Line) error {
+	narration := line.EntryType
+	if line.Description != nil {
+		narration = singleLine(*line.Description)
+	}
+	payee := "Internal"
+	counterparty := "Equity:Internal:" + beancountComponent(line.EntryType)
+	if line.CounterpartyAccountID != nil {
+		payee = fmt.Sprintf("Account %d", *line.CounterpartyAccountID)
+		counterparty = beancountAccount(*line.CounterpartyAccountID)
+	}
+
+	if !b.opened[counterparty] {
+		b.printf("%s open %s\n\n", b.openDate, counterparty)
+		b.opened[counterparty] = true
+	}
+
+	b.printf("%s * \"%s\" \"%s\"\n", beancountDate(line.PostedAt), payee, beancountString(narration))
+	b.printf("  entry_id: \"%d\"\n", line.EntryID)
+	if line.TransactionID != nil {
+		b.printf("  transaction_id: \"%d\"\n", *line.TransactionID)
+	}
+	if line.Reference != nil {
+		b.printf("  reference: \"%s\"\n", beancountString(singleLine(*line.Reference)))
+	}
+	b.printf("  posted_at: \"%s\"\n", line.PostedAt.UTC().Format(time.RFC3339Nano))
+	b.printf("  balance: \"%s %s\"\n", line.Balance.StringFixed(b.s.Precision), b.s.Currency)
+	b.printf("  %s  %s %s\n", b.account, line.SignedAmount().StringFixed(b.s.Precision), b.s.Currency)
+	b.printf("  %s\n\n", counterparty)
+	return b.err
+}
+
+func (b *beancountWriter) Close() error {
+	// The closing balance holds at the start of the day after the period, unless the period
+	// ends at midnight
+	closingDate := b.s.To
+	if !isMidnight(closingDate) {
+		closingDate = closingDate.UTC().AddDate(0, 0, 1)
+	}
+	b.printf("%s balance %s %s %s\n", beancountDate(closingDate), b.account, b.s.ClosingBalance.StringFixed(b.s.Precision), b.s.Currency)
+	return b.Flush()
+}
+
+func beancountAccount(accountID int64) string {
+	return fmt.Sprintf("Assets:Internal:Account%d", accountID)
+}
+
+// beancountComponent turns an entry type such as "reconciliation" into an account name
+// component such as "Reconciliation"
+func beancountComponent(text string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range text {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "Other"
+	}
+	return b.String()
+}
+
+// beancountString escapes text for a double-quoted string
+func beancountString(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text)
+}
+
+func beancountDate(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+func isMidnight(t time.Time) bool {
+	t = t.UTC()
+	return t.Equal(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+}
@@ -0,0 +1,101 @@
+package statement
+
+import (
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// camt053Namespace is the ISO 20022 bank-to-customer statement version written
+	camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"
+	// camt053TimeLayout is an ISODateTime in UTC
+	camt053TimeLayout = "2006-01-02T15:04:05Z"
+)
+
+// camt053Writer writes an ISO 20022 camt.053 statement. Both balances go before the entries,
+// as the schema requires; the running balance of each entry goes in its additional information.
+type camt053Writer struct {
+	*textWriter
+	s *Statement
+}
+
+func newCAMT053Writer(w io.Writer, s *Statement) (*camt053Writer, error) {
+	c := &camt053Writer{textWriter: newTextWriter(w), s: s}
+	id := xmlText(truncate(s.ID(), 35))
+
+	c.printf("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	c.printf("<Document xmlns=\"%s\">\n", camt053Namespace)
+	c.printf("<BkToCstmrStmt>\n")
+	c.printf("<GrpHdr><MsgId>%s</MsgId><CreDtTm>%s</CreDtTm></GrpHdr>\n", id, camt053Time(s.GeneratedAt))
+	c.printf("<Stmt>\n")
+	c.printf("<Id>%s</Id><CreDtTm>%s</CreDtTm>\n", id, camt053Time(s.GeneratedAt))
+	c.printf("<FrToDt><FrDtTm>%s</FrDtTm><ToDtTm>%s</ToDtTm></FrToDt>\n", camt053Time(s.From), camt053Time(s.To))
+	c.printf("<Acct><Id><Othr><Id>%d</Id></Othr></Id><Ccy>%s</Ccy></Acct>\n", s.AccountID, xmlText(s.Currency))
+	c.balance("OPBD", s.OpeningBalance, s.From)
+	c.balance("CLBD", s.ClosingBalance, s.To)
+
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// balance writes a Bal element. Amounts are unsigned, with the sign in CdtDbtInd.
+func (c *camt053Writer) balance(code string, amount decimal.Decimal, at time.Time) {
+	c.printf("<Bal><Tp><CdOrPrtry><Cd>%s</Cd></CdOrPrtry></Tp>", code)
+	c.printf("<Amt Ccy=\"%s\">%s</Amt><CdtDbtInd>%s</CdtDbtInd>", xmlText(c.s.Currency), amount.Abs().StringFixed(c.s.Precision), creditDebitIndicator(amount.IsNegative()))
+	c.printf("<Dt><DtTm>%s</DtTm></Dt></Bal>\n", camt053Time(at))
+}
+
+func (c *camt053Writer) WriteLine(line *Line) error {
+	c.printf("<Ntry><NtryRef>%d</NtryRef>", line.EntryID)
+	c.printf("<Amt Ccy=\"%s\">%s</Amt><CdtDbtInd>%s</CdtDbtInd>", xmlText(c.s.Currency), line.Amount.StringFixed(c.s.Precision), creditDebitIndicator(line.Direction == Debit))
+	c.printf("<Sts><Cd>BOOK</Cd></Sts>")
+	c.printf("<BookgDt><DtTm>%s</DtTm></BookgDt><ValDt><DtTm>%s</DtTm></ValDt>", camt053Time(line.PostedAt), camt053Time(line.PostedAt))
+	if line.TransactionID != nil {
+		c.printf("<AcctSvcrRef>%d</AcctSvcrRef>", *line.TransactionID)
+	}
+	c.printf("<BkTxCd><Prtry><Cd>%s</Cd></Prtry></BkTxCd>", xmlText(line.EntryType))
+
+	if line.Reference != nil || line.Description != nil || line.CounterpartyAccountID != nil {
+		c.printf("<NtryDtls><TxDtls>")
+		if line.Reference != nil {
+			c.printf("<Refs><EndToEndId>%s</EndToEndId></Refs>", xmlText(truncate(singleLine(*line.Reference), 35)))
+		}
+		if line.CounterpartyAccountID != nil {
+			// The counterparty is the creditor of a debit and the debtor of a credit
+			party := "DbtrAcct"
+			if line.Direction == Debit {
+				party = "CdtrAcct"
+			}
+			c.printf("<RltdPties><%s><Id><Othr><Id>%d</Id></Othr></Id></%s></RltdPties>", party, *line.CounterpartyAccountID, party)
+		}
+		if line.Description != nil {
+			c.printf("<RmtInf><Ustrd>%s</Ustrd></RmtInf>", xmlText(truncate(singleLine(*line.Description), 140)))
+		}
+		c.printf("</TxDtls></NtryDtls>")
+	}
+
+	c.printf("<AddtlNtryInf>Balance after entry: %s %s</AddtlNtryInf></Ntry>\n", line.Balance.StringFixed(c.s.Precision), xmlText(c.s.Currency))
+	return c.err
+}
+
+func (c *camt053Writer) Close() error {
+	c.printf("</Stmt>\n")
+	c.printf("</BkToCstmrStmt>\n")
+	c.printf("</Document>\n")
+	return c.Flush()
+}
+
+func creditDebitIndicator(debit bool) string {
+	if debit {
+		return "DBIT"
+	}
+	return "CRDT"
+}
+
+func camt053Time(t time.Time) string {
+	return t.UTC().Format(camt053TimeLayout)
+}
@@ -0,0 +1,88 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvHeader names the columns of a CSV statement
+var csvHeader = []string{
+	"date", "entry_id", "transaction_id", "type", "direction", "counterparty_account_id",
+	"reference", "description", "amount", "balance", "currency",
+}
+
+// csvWriter writes a CSV statement: the opening balance as the first row, one row per line and
+// the closing balance as the last row
+type csvWriter struct {
+	w *csv.Writer
+	s *Statement
+}
+
+func newCSVWriter(w io.Writer, s *Statement) (*csvWriter, error) {
+	c := &csvWriter{w: csv.NewWriter(w), s: s}
+
+	if err := c.w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	if err := c.w.Write(c.balanceRow(s.From, "opening_balance", s.OpeningBalance.StringFixed(s.Precision))); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *csvWriter) WriteLine(line *Line) error {
+	return c.w.Write([]string{
+		line.PostedAt.UTC().Format(time.RFC3339),
+		strconv.FormatInt(line.EntryID, 10),
+		optionalID(line.TransactionID),
+		line.EntryType,
+		string(line.Direction),
+		optionalID(line.CounterpartyAccountID),
+		csvText(line.Reference),
+		csvText(line.Description),
+		line.SignedAmount().StringFixed(c.s.Precision),
+		line.Balance.StringFixed(c.s.Precision),
+		c.s.Currency,
+	})
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	if err := c.w.Write(c.balanceRow(c.s.To, "closing_balance", c.s.ClosingBalance.StringFixed(c.s.Precision))); err != nil {
+		return err
+	}
+	return c.Flush()
+}
+
+// balanceRow is an opening or closing balance row, which moves nothing
+func (c *csvWriter) balanceRow(at time.Time, rowType, balance string) []string {
+	return []string{at.UTC().Format(time.RFC3339), "", "", rowType, "", "", "", "", "", balance, c.s.Currency}
+}
+
+func optionalID(id *int64) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatInt(*id, 10)
+}
+
+// csvText returns free text for a cell. Text a spreadsheet would run as a formula is prefixed
+// with a quote, since references and descriptions come from API clients.
+func csvText(text *string) string {
+	if text == nil {
+		return ""
+	}
+	value := singleLine(*text)
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,94 @@
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// ofxBankID identifies this service as the bank in BANKACCTFROM, which requires one
+	ofxBankID = "INTERNAL"
+	// ofxTimeLayout is an OFX date and time in UTC
+	ofxTimeLayout = "20060102150405.000[0:UTC]"
+)
+
+// ofxWriter writes an OFX 2.2 bank statement. OFX has no opening balance or running balance
+// per transaction, so the opening balance goes in BALLIST and the running balances are left out.
+type ofxWriter struct {
+	*textWriter
+	s *Statement
+}
+
+func newOFXWriter(w io.Writer, s *Statement) (*ofxWriter, error) {
+	o := &ofxWriter{textWriter: newTextWriter(w), s: s}
+
+	o.printf("<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"no\"?>\n")
+	o.printf("<?OFX OFXHEADER=\"200\" VERSION=\"220\" SECURITY=\"NONE\" OLDFILEUID=\"NONE\" NEWFILEUID=\"NONE\"?>\n")
+	o.printf("<OFX>\n")
+	o.printf("<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>")
+	o.printf("<DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>\n", ofxTime(s.GeneratedAt))
+	o.printf("<BANKMSGSRSV1><STMTTRNRS><TRNUID>%s</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n", xmlText(s.ID()))
+	o.printf("<STMTRS><CURDEF>%s</CURDEF>\n", xmlText(s.Currency))
+	o.printf("<BANKACCTFROM><BANKID>%s</BANKID><ACCTID>%d</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>\n", ofxBankID, s.AccountID)
+	o.printf("<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n", ofxTime(s.From), ofxTime(s.To))
+
+	if err := o.Flush(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func (o *ofxWriter) WriteLine(line *Line) error {
+	o.printf("<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%d</FITID>",
+		ofxTransactionType(line), ofxTime(line.PostedAt), line.SignedAmount().StringFixed(o.s.Precision), line.EntryID)
+	if line.Reference != nil {
+		o.printf("<REFNUM>%s</REFNUM>", xmlText(truncate(singleLine(*line.Reference), 32)))
+	}
+	if line.CounterpartyAccountID != nil {
+		o.printf("<NAME>Account %d</NAME>", *line.CounterpartyAccountID)
+	}
+	if line.Description != nil {
+		o.printf("<MEMO>%s</MEMO>", xmlText(truncate(singleLine(*line.Description), 255)))
+	}
+	o.printf("</STMTTRN>\n")
+	return o.err
+}
+
+func (o *ofxWriter) Close() error {
+	o.printf("</BANKTRANLIST>\n")
+	o.printf("<LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>\n", o.s.ClosingBalance.StringFixed(o.s.Precision), ofxTime(o.s.To))
+	o.printf("<BALLIST><BAL><NAME>Opening balance</NAME><DESC>Balance at the start of the period</DESC>")
+	o.printf("<BALTYPE>DOLLAR</BALTYPE><VALUE>%s</VALUE><DTASOF>%s</DTASOF></BAL></BALLIST>\n", o.s.OpeningBalance.StringFixed(o.s.Precision), ofxTime(o.s.From))
+	o.printf("</STMTRS></STMTTRNRS></BANKMSGSRSV1>\n")
+	o.printf("</OFX>\n")
+	return o.Flush()
+}
+
+// ofxTransactionType maps a line to the closest OFX transaction type
+func ofxTransactionType(line *Line) string {
+	switch line.EntryType {
+	case "fee":
+		return "FEE"
+	case "interest":
+		return "INT"
+	case "transfer", "fx":
+		return "XFER"
+	}
+	if line.Direction == Debit {
+		return "DEBIT"
+	}
+	return "CREDIT"
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format(ofxTimeLayout)
+}
+
+// xmlText escapes text for an XML element or attribute
+func xmlText(text string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
@@ -0,0 +1,187 @@
+// Package statement renders account statements for accounting tools and bank reconciliation,
+// as CSV, OFX, ISO 20022 camt.053 and Beancount. Lines are written as they are read, so a
+// statement of any length is streamed rather than built in memory.
+package statement
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Format is a statement file format
+type Format string
+
+const (
+	FormatCSV       Format = "csv"
+	FormatOFX       Format = "ofx"
+	FormatCAMT053   Format = "camt053"
+	FormatBeancount Format = "beancount"
+)
+
+// Formats lists every format in a stable order
+var Formats = []Format{FormatCSV, FormatOFX, FormatCAMT053, FormatBeancount}
+
+// Valid reports whether f is a known format
+func (f Format) Valid() bool {
+	for _, known := range Formats {
+		if f == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentType is the media type a statement in this format is served as
+func (f Format) ContentType() string {
+	switch f {
+	case FormatOFX:
+		return "application/x-ofx"
+	case FormatCAMT053:
+		return "application/xml"
+	case FormatBeancount:
+		return "text/plain; charset=utf-8"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Extension is the file name extension of a statement in this format
+func (f Format) Extension() string {
+	switch f {
+	case FormatOFX:
+		return ".ofx"
+	case FormatCAMT053:
+		return ".xml"
+	case FormatBeancount:
+		return ".beancount"
+	default:
+		return ".csv"
+	}
+}
+
+// Direction is the side of a line. Debits reduce the balance, credits increase it.
+type Direction string
+
+const (
+	Debit  Direction = "debit"
+	Credit Direction = "credit"
+)
+
+// Statement describes the account and period a statement covers. The period includes From
+// and excludes To.
+type Statement struct {
+	AccountID int64
+	Currency  string
+	// Precision is the number of decimals amounts in the currency are written with
+	Precision      int32
+	From           time.Time
+	To             time.Time
+	OpeningBalance decimal.Decimal
+	ClosingBalance decimal.Decimal
+	GeneratedAt    time.Time
+}
+
+// ID identifies the statement by account and period
+func (s *Statement) ID() string {
+	return fmt.Sprintf("%d-%s-%s", s.AccountID, s.From.UTC().Format("20060102T150405"), s.To.UTC().Format("20060102T150405"))
+}
+
+// FileName is the name the statement is downloaded as
+func (s *Statement) FileName(format Format) string {
+	return "statement-" + s.ID() + format.Extension()
+}
+
+// Line is one posting to the account
+type Line struct {
+	EntryID       int64
+	TransactionID *int64
+	PostedAt      time.Time
+	Direction     Direction
+	// Amount is positive; Direction says which way it moved the balance
+	Amount decimal.Decimal
+	// Balance is the running balance after the line
+	Balance   decimal.Decimal
+	EntryType string
+	// CounterpartyAccountID is the other account of a transfer, nil for postings without one
+	CounterpartyAccountID *int64
+	Reference             *string
+	Description           *string
+}
+
+// SignedAmount returns the amount as a change to the balance
+func (l *Line) SignedAmount() decimal.Decimal {
+	if l.Direction == Debit {
+		return l.Amount.Neg()
+	}
+	return l.Amount
+}
+
+// Writer writes the lines of a statement after its header
+type Writer interface {
+	// WriteLine writes the next line, in posting order
+	WriteLine(line *Line) error
+	// Flush sends what has been written so far to the underlying writer
+	Flush() error
+	// Close writes the end of the statement and flushes it
+	Close() error
+}
+
+// NewWriter writes the header of a statement in the format and returns the writer for its lines
+func NewWriter(format Format, w io.Writer, s *Statement) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, s)
+	case FormatOFX:
+		return newOFXWriter(w, s)
+	case FormatCAMT053:
+		return newCAMT053Writer(w, s)
+	case FormatBeancount:
+		return newBeancountWriter(w, s)
+	default:
+		return nil, fmt.Errorf("unknown statement format %q", format)
+	}
+}
+
+// textWriter collects the first error of a sequence of writes, so formats can be written
+// without checking every call
+type textWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func newTextWriter(w io.Writer) *textWriter {
+	return &textWriter{w: bufio.NewWriter(w)}
+}
+
+func (t *textWriter) printf(format string, args ...interface{}) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = fmt.Fprintf(t.w, format, args...)
+}
+
+func (t *textWriter) Flush() error {
+	if t.err != nil {
+		return t.err
+	}
+	return t.w.Flush()
+}
+
+// truncate shortens text to at most n characters, for fields with a maximum length
+func truncate(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n])
+}
+
+// singleLine replaces line breaks, which no format allows inside a field
+func singleLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
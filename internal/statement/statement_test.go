@@ -0,0 +1,265 @@
+package statement_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/statement"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func d(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func newStatement() *statement.Statement {
+	return &statement.Statement{
+		AccountID:      7,
+		Currency:       "USD",
+		Precision:      2,
+		From:           time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:             time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		OpeningBalance: d("100"),
+		ClosingBalance: d("65.5"),
+		GeneratedAt:    time.Date(2026, 4, 2, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func newLines() []*statement.Line {
+	return []*statement.Line{
+		{
+			EntryID:               11,
+			TransactionID:         ptr(int64(3)),
+			PostedAt:              time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC),
+			Direction:             statement.Debit,
+			Amount:                d("40"),
+			Balance:               d("60"),
+			EntryType:             "transfer",
+			CounterpartyAccountID: ptr(int64(9)),
+			Reference:             ptr("=HYPERLINK(\"x\")"),
+			Description:           ptr("Rent, \"March\"\nsecond line"),
+		},
+		{
+			EntryID:   12,
+			PostedAt:  time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC),
+			Direction: statement.Credit,
+			Amount:    d("5.5"),
+			Balance:   d("65.5"),
+			EntryType: "interest",
+		},
+	}
+}
+
+func render(t *testing.T, format statement.Format) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := statement.NewWriter(format, &buf, newStatement())
+	require.NoError(t, err)
+	for _, line := range newLines() {
+		require.NoError(t, w.WriteLine(line))
+	}
+	require.NoError(t, w.Close())
+
+	return buf.String()
+}
+
+// requireWellFormedXML decodes every token of the document
+func requireWellFormedXML(t *testing.T, document string) {
+	t.Helper()
+
+	decoder := xml.NewDecoder(strings.NewReader(document))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		format      statement.Format
+		valid       bool
+		contentType string
+		extension   string
+	}{
+		{statement.FormatCSV, true, "text/csv; charset=utf-8", ".csv"},
+		{statement.FormatOFX, true, "application/x-ofx", ".ofx"},
+		{statement.FormatCAMT053, true, "application/xml", ".xml"},
+		{statement.FormatBeancount, true, "text/plain; charset=utf-8", ".beancount"},
+		{statement.Format("pdf"), false, "text/csv; charset=utf-8", ".csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			assert.Equal(t, tt.valid, tt.format.Valid())
+			assert.Equal(t, tt.contentType, tt.format.ContentType())
+			assert.Equal(t, tt.extension, tt.format.Extension())
+		})
+	}
+}
+
+func TestStatement_FileName(t *testing.T) {
+	assert.Equal(t, "statement-7-20260301T000000-20260401T000000.ofx", newStatement().FileName(statement.FormatOFX))
+}
+
+func TestNewWriter_UnknownFormat(t *testing.T) {
+	_, err := statement.NewWriter(statement.Format("pdf"), io.Discard, newStatement())
+	assert.Error(t, err)
+}
+
+func TestCSV(t *testing.T) {
+	records, err := csv.NewReader(strings.NewReader(render(t, statement.FormatCSV))).ReadAll()
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"date", "entry_id", "transaction_id", "type", "direction", "counterparty_account_id", "reference", "description", "amount", "balance", "currency"},
+		{"2026-03-01T00:00:00Z", "", "", "opening_balance", "", "", "", "", "", "100.00", "USD"},
+		{"2026-03-04T12:00:00Z", "11", "3", "transfer", "debit", "9", "'=HYPERLINK(\"x\")", "Rent, \"March\" second line", "-40.00", "60.00", "USD"},
+		{"2026-03-31T23:00:00Z", "12", "", "interest", "credit", "", "", "", "5.50", "65.50", "USD"},
+		{"2026-04-01T00:00:00Z", "", "", "closing_balance", "", "", "", "", "", "65.50", "USD"},
+	}, records)
+}
+
+func TestOFX(t *testing.T) {
+	document := render(t, statement.FormatOFX)
+	requireWellFormedXML(t, document)
+
+	assert.Contains(t, document, "<CURDEF>USD</CURDEF>")
+	assert.Contains(t, document, "<ACCTID>7</ACCTID>")
+	assert.Contains(t, document, "<DTSTART>20260301000000.000[0:UTC]</DTSTART><DTEND>20260401000000.000[0:UTC]</DTEND>")
+	assert.Contains(t, document, "<TRNTYPE>XFER</TRNTYPE><DTPOSTED>20260304120000.000[0:UTC]</DTPOSTED><TRNAMT>-40.00</TRNAMT><FITID>11</FITID>")
+	assert.Contains(t, document, "<NAME>Account 9</NAME><MEMO>Rent, &#34;March&#34; second line</MEMO>")
+	assert.Contains(t, document, "<TRNTYPE>INT</TRNTYPE>")
+	assert.Contains(t, document, "<LEDGERBAL><BALAMT>65.50</BALAMT>")
+	assert.Contains(t, document, "<VALUE>100.00</VALUE>")
+}
+
+func TestCAMT053(t *testing.T) {
+	document := render(t, statement.FormatCAMT053)
+	requireWellFormedXML(t, document)
+
+	var parsed struct {
+		Stmt struct {
+			Acct struct {
+				ID  string `xml:"Id>Othr>Id"`
+				Ccy string `xml:"Ccy"`
+			} `xml:"Acct"`
+			Bal []struct {
+				Code      string `xml:"Tp>CdOrPrtry>Cd"`
+				Amt       string `xml:"Amt"`
+				CdtDbtInd string `xml:"CdtDbtInd"`
+			} `xml:"Bal"`
+			Ntry []struct {
+				Ref       string `xml:"NtryRef"`
+				Amt       string `xml:"Amt"`
+				CdtDbtInd string `xml:"CdtDbtInd"`
+				Creditor  string `xml:"NtryDtls>TxDtls>RltdPties>CdtrAcct>Id>Othr>Id"`
+				Ustrd     string `xml:"NtryDtls>TxDtls>RmtInf>Ustrd"`
+				Info      string `xml:"AddtlNtryInf"`
+			} `xml:"Ntry"`
+		} `xml:"BkToCstmrStmt>Stmt"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(document), &parsed))
+
+	assert.Equal(t, "7", parsed.Stmt.Acct.ID)
+	assert.Equal(t, "USD", parsed.Stmt.Acct.Ccy)
+	require.Len(t, parsed.Stmt.Bal, 2)
+	assert.Equal(t, "OPBD", parsed.Stmt.Bal[0].Code)
+	assert.Equal(t, "100.00", parsed.Stmt.Bal[0].Amt)
+	assert.Equal(t, "CLBD", parsed.Stmt.Bal[1].Code)
+	assert.Equal(t, "65.50", parsed.Stmt.Bal[1].Amt)
+	assert.Equal(t, "CRDT", parsed.Stmt.Bal[1].CdtDbtInd)
+
+	require.Len(t, parsed.Stmt.Ntry, 2)
+	assert.Equal(t, "11", parsed.Stmt.Ntry[0].Ref)
+	assert.Equal(t, "40.00", parsed.Stmt.Ntry[0].Amt)
+	assert.Equal(t, "DBIT", parsed.Stmt.Ntry[0].CdtDbtInd)
+	assert.Equal(t, "9", parsed.Stmt.Ntry[0].Creditor)
+	assert.Equal(t, "Rent, \"March\" second line", parsed.Stmt.Ntry[0].Ustrd)
+	assert.Equal(t, "Balance after entry: 60.00 USD", parsed.Stmt.Ntry[0].Info)
+	assert.Equal(t, "CRDT", parsed.Stmt.Ntry[1].CdtDbtInd)
+}
+
+func TestCAMT053_NegativeBalance(t *testing.T) {
+	s := newStatement()
+	s.OpeningBalance = d("-12.3")
+
+	var buf bytes.Buffer
+	w, err := statement.NewWriter(statement.FormatCAMT053, &buf, s)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Contains(t, buf.String(), "<Cd>OPBD</Cd></CdOrPrtry></Tp><Amt Ccy=\"USD\">12.30</Amt><CdtDbtInd>DBIT</CdtDbtInd>")
+}
+
+func TestBeancount(t *testing.T) {
+	document := render(t, statement.FormatBeancount)
+
+	assert.Contains(t, document, "2026-02-28 open Assets:Internal:Account7 USD\n"+
+		"2026-02-28 open Equity:Internal:Opening\n\n"+
+		"2026-02-28 pad Assets:Internal:Account7 Equity:Internal:Opening\n"+
+		"2026-03-01 balance Assets:Internal:Account7 100.00 USD\n")
+	assert.Contains(t, document, "2026-02-28 open Assets:Internal:Account9\n\n2026-03-04 * ")
+	assert.Contains(t, document, "2026-02-28 open Equity:Internal:Interest\n\n2026-03-31 * ")
+	assert.Equal(t, 1, strings.Count(document, "open Assets:Internal:Account7 "))
+	assert.Contains(t, document, "2026-03-04 * \"Account 9\" \"Rent, \\\"March\\\" second line\"\n"+
+		"  entry_id: \"11\"\n"+
+		"  transaction_id: \"3\"\n"+
+		"  reference: \"=HYPERLINK(\\\"x\\\")\"\n"+
+		"  posted_at: \"2026-03-04T12:00:00Z\"\n"+
+		"  balance: \"60.00 USD\"\n"+
+		"  Assets:Internal:Account7  -40.00 USD\n"+
+		"  Assets:Internal:Account9\n")
+	assert.Contains(t, document, "2026-03-31 * \"Internal\" \"interest\"\n")
+	assert.Contains(t, document, "  Equity:Internal:Interest\n")
+	assert.True(t, strings.HasSuffix(document, "2026-04-01 balance Assets:Internal:Account7 65.50 USD\n"))
+}
+
+func TestBeancount_ZeroOpeningBalance(t *testing.T) {
+	s := newStatement()
+	s.OpeningBalance = decimal.Zero
+
+	var buf bytes.Buffer
+	w, err := statement.NewWriter(statement.FormatBeancount, &buf, s)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	// Beancount rejects a pad that has nothing to add
+	assert.NotContains(t, buf.String(), " pad ")
+	assert.Contains(t, buf.String(), "2026-03-01 balance Assets:Internal:Account7 0.00 USD\n")
+}
+
+func TestBeancount_PeriodNotAtMidnight(t *testing.T) {
+	s := newStatement()
+	s.From = time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	s.To = time.Date(2026, 3, 31, 17, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	w, err := statement.NewWriter(statement.FormatBeancount, &buf, s)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	document := buf.String()
+	assert.NotContains(t, document, "2026-03-01 balance")
+	assert.NotContains(t, document, " pad ")
+	assert.Contains(t, document, "2026-02-28 open Assets:Internal:Account7 USD\n")
+	assert.Contains(t, document, "2026-03-01 * \"Internal\" \"Opening balance\"\n"+
+		"  posted_at: \"2026-03-01T08:00:00Z\"\n"+
+		"  Assets:Internal:Account7  100.00 USD\n"+
+		"  Equity:Internal:Opening\n")
+	assert.True(t, strings.HasSuffix(document, "2026-04-01 balance Assets:Internal:Account7 65.50 USD\n"))
+}
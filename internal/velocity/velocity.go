@@ -0,0 +1,87 @@
+// Package velocity evaluates rolling-window limits on outgoing money movements.
+package velocity
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Movement is one outgoing transfer counted against a limit
+type Movement struct {
+	Amount decimal.Decimal
+	At     time.Time
+}
+
+// Result is the outcome of checking a new movement against a limit
+type Result struct {
+	Allowed bool
+	// ResetsAt is the earliest time the movement would fit, nil if waiting never helps
+	ResetsAt *time.Time
+}
+
+// CheckAmount checks whether amount fits within max together with the movements of the
+// window ending at now
+func CheckAmount(history []Movement, amount, max decimal.Decimal, window time.Duration, now time.Time) Result {
+	inWindow := within(history, window, now)
+
+	used := decimal.Zero
+	for _, m := range inWindow {
+		used = used.Add(m.Amount)
+	}
+
+	if used.Add(amount).LessThanOrEqual(max) {
+		return Result{Allowed: true}
+	}
+
+	if amount.GreaterThan(max) {
+		return Result{}
+	}
+
+	// Drop movements oldest first until the new one fits
+	for _, m := range inWindow {
+		used = used.Sub(m.Amount)
+		if used.Add(amount).LessThanOrEqual(max) {
+			resetsAt := m.At.Add(window)
+			return Result{ResetsAt: &resetsAt}
+		}
+	}
+
+	return Result{}
+}
+
+// CheckCount checks whether one more movement fits within max movements in the window
+// ending at now
+func CheckCount(history []Movement, max int64, window time.Duration, now time.Time) Result {
+	inWindow := within(history, window, now)
+
+	count := int64(len(inWindow))
+	if count+1 <= max {
+		return Result{Allowed: true}
+	}
+
+	if max < 1 {
+		return Result{}
+	}
+
+	// The movement fits once the oldest count+1-max movements have left the window
+	resetsAt := inWindow[count-max].At.Add(window)
+	return Result{ResetsAt: &resetsAt}
+}
+
+// within returns the movements of the window ending at now, oldest first
+func within(history []Movement, window time.Duration, now time.Time) []Movement {
+	since := now.Add(-window)
+
+	inWindow := make([]Movement, 0, len(history))
+	for _, m := range history {
+		if m.At.After(since) && !m.At.After(now) {
+			inWindow = append(inWindow, m)
+		}
+	}
+
+	sort.SliceStable(inWindow, func(i, j int) bool { return inWindow[i].At.Before(inWindow[j].At) })
+
+	return inWindow
+}
@@ -0,0 +1,73 @@
+package velocity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/velocity"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+func movement(amount string, ago time.Duration) velocity.Movement {
+	return velocity.Movement{Amount: decimal.RequireFromString(amount), At: now.Add(-ago)}
+}
+
+func TestCheckAmount_AllowsWithinLimit(t *testing.T) {
+	history := []velocity.Movement{movement("400", 3*time.Hour), movement("300", time.Hour)}
+
+	result := velocity.CheckAmount(history, decimal.RequireFromString("300"), decimal.RequireFromString("1000"), 24*time.Hour, now)
+
+	assert.True(t, result.Allowed)
+	assert.Nil(t, result.ResetsAt)
+}
+
+func TestCheckAmount_ResetsWhenEnoughHasLeftTheWindow(t *testing.T) {
+	history := []velocity.Movement{
+		movement("300", time.Hour),
+		movement("400", 5*time.Hour),
+		movement("200", 3*time.Hour),
+	}
+
+	result := velocity.CheckAmount(history, decimal.RequireFromString("400"), decimal.RequireFromString("1000"), 24*time.Hour, now)
+
+	assert.False(t, result.Allowed)
+	require.NotNil(t, result.ResetsAt)
+	// 900 used: the oldest 400 has to leave the window before 400 more fit
+	assert.Equal(t, now.Add(-5*time.Hour).Add(24*time.Hour), *result.ResetsAt)
+}
+
+func TestCheckAmount_IgnoresMovementsOutsideTheWindow(t *testing.T) {
+	history := []velocity.Movement{movement("900", 25*time.Hour)}
+
+	result := velocity.CheckAmount(history, decimal.RequireFromString("500"), decimal.RequireFromString("1000"), 24*time.Hour, now)
+
+	assert.True(t, result.Allowed)
+}
+
+func TestCheckAmount_NeverResetsForAnAmountAboveTheLimit(t *testing.T) {
+	result := velocity.CheckAmount(nil, decimal.RequireFromString("1500"), decimal.RequireFromString("1000"), 24*time.Hour, now)
+
+	assert.False(t, result.Allowed)
+	assert.Nil(t, result.ResetsAt)
+}
+
+func TestCheckCount(t *testing.T) {
+	history := []velocity.Movement{
+		movement("1", 10*time.Minute),
+		movement("1", 50*time.Minute),
+		movement("1", 30*time.Minute),
+	}
+
+	allowed := velocity.CheckCount(history, 4, time.Hour, now)
+	assert.True(t, allowed.Allowed)
+
+	blocked := velocity.CheckCount(history, 2, time.Hour, now)
+	assert.False(t, blocked.Allowed)
+	require.NotNil(t, blocked.ResetsAt)
+	// Two of the three have to leave the window: the one 50 and the one 30 minutes old
+	assert.Equal(t, now.Add(-30*time.Minute).Add(time.Hour), *blocked.ResetsAt)
+}
@@ -0,0 +1,90 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// userAgent identifies deliveries to receivers
+const userAgent = "internal-transfers-webhooks/1"
+
+// maxResponseBody is how much of a receiver's response is read, and kept for error messages
+const maxResponseBody = 1024
+
+// Delivery is one event sent to one endpoint
+type Delivery struct {
+	URL       string
+	EventID   int64
+	EventType string
+	// Payload is the JSON body, sent as is
+	Payload []byte
+	// Secrets are the secrets currently valid for the endpoint, the newest first
+	Secrets []string
+}
+
+// StatusError is returned when a receiver answers with a status outside 2xx
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("receiver responded with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("receiver responded with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Sender posts signed deliveries over HTTP
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender creates a sender whose requests time out after timeout
+func NewSender(timeout time.Duration) *Sender {
+	return NewSenderWithClient(&http.Client{Timeout: timeout})
+}
+
+// NewSenderWithClient creates a sender using client, such as the one of an httptest server
+func NewSenderWithClient(client *http.Client) *Sender {
+	return &Sender{client: client, now: time.Now}
+}
+
+// Send posts the delivery and returns the receiver's status code, or 0 when no response
+// arrived. Any status outside 2xx is returned as a *StatusError.
+func (s *Sender) Send(ctx context.Context, delivery *Delivery) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	timestamp := s.now()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", userAgent)
+	request.Header.Set(IDHeader, strconv.FormatInt(delivery.EventID, 10))
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	request.Header.Set(SignatureHeader, SignatureHeaderValue(delivery.Secrets, timestamp, delivery.Payload))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxResponseBody))
+	// Drain the rest so the connection can be reused
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, &StatusError{StatusCode: response.StatusCode, Body: string(bytes.TrimSpace(body))}
+	}
+
+	return response.StatusCode, nil
+}
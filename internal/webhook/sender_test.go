@@ -0,0 +1,84 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiver records the last delivery it got and answers with status
+type receiver struct {
+	status  int
+	request *http.Request
+	body    []byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.request = req
+	r.body, _ = io.ReadAll(req.Body)
+	w.WriteHeader(r.status)
+	_, _ = w.Write([]byte("  nope  "))
+}
+
+func newDelivery(url string, secrets ...string) *webhook.Delivery {
+	return &webhook.Delivery{
+		URL:       url,
+		EventID:   42,
+		EventType: "transaction.completed",
+		Payload:   []byte(`{"id":42,"type":"transaction.completed"}`),
+		Secrets:   secrets,
+	}
+}
+
+func TestSender_SendsSignedDelivery(t *testing.T) {
+	recv := &receiver{status: http.StatusNoContent}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	status, err := webhook.NewSenderWithClient(server.Client()).Send(context.Background(), newDelivery(server.URL, "new", "old"))
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+	require.NotNil(t, recv.request)
+	assert.Equal(t, http.MethodPost, recv.request.Method)
+	assert.Equal(t, "application/json", recv.request.Header.Get("Content-Type"))
+	assert.Equal(t, "42", recv.request.Header.Get(webhook.IDHeader))
+	assert.Equal(t, "transaction.completed", recv.request.Header.Get(webhook.EventHeader))
+	assert.JSONEq(t, `{"id":42,"type":"transaction.completed"}`, string(recv.body))
+
+	signature := recv.request.Header.Get(webhook.SignatureHeader)
+	timestamp := recv.request.Header.Get(webhook.TimestampHeader)
+	assert.NoError(t, webhook.Verify("new", signature, timestamp, recv.body, time.Minute, time.Now()))
+	assert.NoError(t, webhook.Verify("old", signature, timestamp, recv.body, time.Minute, time.Now()))
+}
+
+func TestSender_ReturnsStatusErrorOutside2xx(t *testing.T) {
+	server := httptest.NewServer(&receiver{status: http.StatusServiceUnavailable})
+	defer server.Close()
+
+	status, err := webhook.NewSenderWithClient(server.Client()).Send(context.Background(), newDelivery(server.URL, "secret"))
+
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	var statusErr *webhook.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+	assert.Equal(t, "nope", statusErr.Body)
+}
+
+func TestSender_ReturnsZeroStatusWithoutResponse(t *testing.T) {
+	server := httptest.NewServer(&receiver{status: http.StatusOK})
+	url := server.URL
+	server.Close()
+
+	status, err := webhook.NewSender(time.Second).Send(context.Background(), newDelivery(url, "secret"))
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, status)
+}
@@ -0,0 +1,98 @@
+package webhook_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var signedAt = time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+var body = []byte(`{"id":1}`)
+
+func unix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+func TestNewSecret_IsRandomAndPrefixed(t *testing.T) {
+	first, err := webhook.NewSecret()
+	require.NoError(t, err)
+	second, err := webhook.NewSecret()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, "whsec_"))
+	assert.Len(t, first, len("whsec_")+64)
+	assert.NotEqual(t, first, second)
+}
+
+func TestSign_IsHMACOfTimestampAndBody(t *testing.T) {
+	// echo -n '1792227600.{"id":1}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "8c84bbc8e049bfb7c4c38f92d3a6dc76543bed4451eedfbabb7556ba84e83a1b", webhook.Sign("secret", signedAt, body))
+}
+
+func TestSignatureHeaderValue_ListsOneSignaturePerSecret(t *testing.T) {
+	header := webhook.SignatureHeaderValue([]string{"new", "old"}, signedAt, body)
+
+	assert.Equal(t, "v1="+webhook.Sign("new", signedAt, body)+",v1="+webhook.Sign("old", signedAt, body), header)
+}
+
+func TestVerify_AcceptsEitherSecretDuringRotation(t *testing.T) {
+	header := webhook.SignatureHeaderValue([]string{"new", "old"}, signedAt, body)
+
+	assert.NoError(t, webhook.Verify("new", header, unix(signedAt), body, time.Minute, signedAt))
+	assert.NoError(t, webhook.Verify("old", header, unix(signedAt), body, time.Minute, signedAt))
+	assert.ErrorIs(t, webhook.Verify("other", header, unix(signedAt), body, time.Minute, signedAt), webhook.ErrInvalidSignature)
+}
+
+func TestVerify_RejectsTamperedDeliveries(t *testing.T) {
+	header := webhook.SignatureHeaderValue([]string{"secret"}, signedAt, body)
+
+	assert.ErrorIs(t, webhook.Verify("secret", header, unix(signedAt), []byte(`{"id":2}`), 0, signedAt), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify("secret", header, unix(signedAt.Add(time.Second)), body, 0, signedAt), webhook.ErrInvalidSignature)
+	assert.ErrorIs(t, webhook.Verify("secret", "v2="+webhook.Sign("secret", signedAt, body), unix(signedAt), body, 0, signedAt), webhook.ErrInvalidSignature)
+}
+
+func TestVerify_ChecksTimestamp(t *testing.T) {
+	header := webhook.SignatureHeaderValue([]string{"secret"}, signedAt, body)
+
+	assert.ErrorIs(t, webhook.Verify("secret", header, unix(signedAt), body, 5*time.Minute, signedAt.Add(6*time.Minute)), webhook.ErrTimestampOutOfRange)
+	assert.ErrorIs(t, webhook.Verify("secret", header, unix(signedAt), body, 5*time.Minute, signedAt.Add(-6*time.Minute)), webhook.ErrTimestampOutOfRange)
+	assert.NoError(t, webhook.Verify("secret", header, unix(signedAt), body, 0, signedAt.Add(time.Hour)))
+	assert.ErrorIs(t, webhook.Verify("secret", header, "", body, 0, signedAt), webhook.ErrMissingSignature)
+	assert.ErrorIs(t, webhook.Verify("secret", header, "yesterday", body, 0, signedAt), webhook.ErrMissingSignature)
+	assert.ErrorIs(t, webhook.Verify("secret", "", unix(signedAt), body, 0, signedAt), webhook.ErrMissingSignature)
+}
+
+func TestRetryPolicy_DelayDoublesUpToMax(t *testing.T) {
+	policy := webhook.RetryPolicy{MaxAttempts: 8, BaseDelay: 10 * time.Second, MaxDelay: time.Minute}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{4, time.Minute},
+		{30, time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.attempt), func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.Delay(tt.attempt))
+		})
+	}
+}
+
+func TestRetryPolicy_Exhausted(t *testing.T) {
+	policy := webhook.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+
+	assert.False(t, policy.Exhausted(1))
+	assert.False(t, policy.Exhausted(2))
+	assert.True(t, policy.Exhausted(3))
+}
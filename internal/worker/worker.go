@@ -0,0 +1,194 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chandra-shekhar/internal-transfers/internal/server"
+	"github.com/chandra-shekhar/internal-transfers/internal/service"
+	"github.com/rs/zerolog"
+)
+
+// Job is a unit of background work that runs on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Runner runs background jobs inside the server process until its context is cancelled
+type Runner struct {
+	logger *zerolog.Logger
+	jobs   []Job
+	// tasks run once for the lifetime of the runner instead of on an interval
+	tasks []Job
+	wg    sync.WaitGroup
+}
+
+// New creates a runner with all jobs enabled by the configuration
+func New(s *server.Server, services *service.Services) *Runner {
+	runner := &Runner{
+		logger: s.Logger,
+	}
+
+	runner.Register(Job{
+		Name:     "idempotency_purge",
+		Interval: time.Duration(s.Config.Idempotency.PurgeInterval) * time.Second,
+		Run:      services.Idempotency.PurgeExpired,
+	})
+
+	runner.Register(Job{
+		Name:     "reconciliation",
+		Interval: time.Duration(s.Config.Reconcile.Interval) * time.Second,
+		Run: func(ctx context.Context) error {
+			_, err := services.Reconciliation.Run(ctx, false)
+			return err
+		},
+	})
+
+	runner.Register(Job{
+		Name:     "scheduled_transfers",
+		Interval: time.Duration(s.Config.Scheduler.Interval) * time.Second,
+		Run: func(ctx context.Context) error {
+			_, err := services.Transaction.ExecuteDueScheduled(ctx, s.Config.Scheduler.BatchSize)
+			return err
+		},
+	})
+
+	runner.Register(Job{
+		Name:     "standing_orders",
+		Interval: time.Duration(s.Config.Scheduler.Interval) * time.Second,
+		Run: func(ctx context.Context) error {
+			_, err := services.StandingOrder.RunDue(ctx, s.Config.Scheduler.BatchSize)
+			return err
+		},
+	})
+
+	runner.Register(Job{
+		Name:     "hold_expiry",
+		Interval: time.Duration(s.Config.Scheduler.Interval) * time.Second,
+		Run: func(ctx context.Context) error {
+			_, err := services.Hold.ExpireDue(ctx, s.Config.Scheduler.BatchSize)
+			return err
+		},
+	})
+
+	runner.Register(Job{
+		Name:     "interest",
+		Interval: time.Duration(s.Config.Interest.Interval) * time.Second,
+		Run:      services.Interest.RunDue,
+	})
+
+	runner.Register(Job{
+		Name:     "outbox_relay",
+		Interval: time.Duration(s.Config.Outbox.Interval) * time.Second,
+		Run: func(ctx context.Context) error {
+			_, err := services.Outbox.Relay(ctx, s.Config.Outbox.BatchSize)
+			return err
+		},
+	})
+
+	runner.Register(Job{
+		Name:     "webhook_delivery",
+		Interval: time.Duration(s.Config.Webhook.Interval) * time.Second,
+		Run: func(ctx context.Context) error {
+			_, err := services.Webhook.DeliverDue(ctx, s.Config.Webhook.BatchSize)
+			return err
+		},
+	})
+
+	runner.Register(Job{
+		Name:     "balance_snapshots",
+		Interval: time.Duration(s.Config.Snapshot.Interval) * time.Second,
+		Run:      services.Snapshot.RunDue,
+	})
+
+	runner.Go(Job{
+		Name: "ledger_listener",
+		Run:  services.AccountStream.Listen,
+	})
+
+	// The async worker pool is one job per worker, all claiming from the same queue
+	for i := 1; i <= s.Config.Async.Workers; i++ {
+		runner.Register(Job{
+			Name:     fmt.Sprintf("async_transfers_%d", i),
+			Interval: time.Duration(s.Config.Async.Interval) * time.Second,
+			Run: func(ctx context.Context) error {
+				_, err := services.Transaction.ExecutePending(ctx, s.Config.Async.BatchSize)
+				return err
+			},
+		})
+	}
+
+	return runner
+}
+
+// Register adds a job. Jobs without a positive interval are disabled.
+func (r *Runner) Register(job Job) {
+	if job.Interval <= 0 {
+		r.logger.Info().Str("job", job.Name).Msg("background job disabled")
+		return
+	}
+	r.jobs = append(r.jobs, job)
+}
+
+// Go adds a long-running task. Its Run is called once and should return when its context is
+// cancelled; the interval is ignored.
+func (r *Runner) Go(task Job) {
+	r.tasks = append(r.tasks, task)
+}
+
+// Start launches every registered job and task in its own goroutine
+func (r *Runner) Start(ctx context.Context) {
+	for _, job := range r.jobs {
+		r.wg.Add(1)
+		go func(job Job) {
+			defer r.wg.Done()
+			r.run(ctx, job)
+		}(job)
+	}
+
+	for _, task := range r.tasks {
+		r.wg.Add(1)
+		go func(task Job) {
+			defer r.wg.Done()
+			logger := r.logger.With().Str("task", task.Name).Logger()
+			logger.Info().Msg("background task started")
+			if err := task.Run(ctx); err != nil && ctx.Err() == nil {
+				logger.Error().Err(err).Msg("background task failed")
+				return
+			}
+			logger.Info().Msg("background task stopped")
+		}(task)
+	}
+}
+
+// Wait blocks until all jobs have stopped
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+func (r *Runner) run(ctx context.Context, job Job) {
+	logger := r.logger.With().Str("job", job.Name).Logger()
+	logger.Info().Dur("interval", job.Interval).Msg("background job started")
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info().Msg("background job stopped")
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+				logger.Error().Err(err).Dur("duration", time.Since(start)).Msg("background job failed")
+				continue
+			}
+			logger.Debug().Dur("duration", time.Since(start)).Msg("background job completed")
+		}
+	}
+}